@@ -3,12 +3,30 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/mark3labs/mcp-go/server"
+	"jsonmcptool/internal/jsonhandler"
 	"jsonmcptool/internal/mcpserver"
 )
 
 func main() {
+	// Override the maximum loadable file size if requested
+	if value := os.Getenv("JSONMCPTOOL_MAX_FILE_SIZE"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid JSONMCPTOOL_MAX_FILE_SIZE %q: %v", value, err)
+		}
+		jsonhandler.SetMaxFileSize(size)
+	}
+
+	// Append a record of every key read or written to an audit log if requested
+	if path := os.Getenv("JSON_MCP_AUDIT_LOG"); path != "" {
+		if err := jsonhandler.SetAuditLog(path); err != nil {
+			log.Fatalf("Invalid JSON_MCP_AUDIT_LOG: %v", err)
+		}
+	}
+
 	// Create the JSON MCP server
 	s := mcpserver.NewJSONMcpServer()
 
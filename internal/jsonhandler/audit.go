@@ -0,0 +1,95 @@
+package jsonhandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Operations recorded in the audit log
+const (
+	AuditRead  = "read"
+	AuditWrite = "write"
+)
+
+// AccessRecord is one line of the audit log. An empty KeyPath stands for the whole document.
+type AccessRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	File      string    `json:"file"`
+	KeyPath   string    `json:"key_path"`
+	Operation string    `json:"operation"`
+}
+
+// auditLog is the file access records are appended to, nil while auditing is off
+var auditLog struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// SetAuditLog starts appending access records, one JSON object per line, to the file at path,
+// creating it if needed. An empty path turns auditing off.
+func SetAuditLog(path string) error {
+	var file *os.File
+	if path != "" {
+		var err error
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return fmt.Errorf("%w: Failed to open audit log %s: %v", ErrFileWriteError, path, err)
+		}
+	}
+
+	auditLog.mutex.Lock()
+	defer auditLog.mutex.Unlock()
+
+	if auditLog.file != nil {
+		auditLog.file.Close()
+	}
+	auditLog.file = file
+	return nil
+}
+
+// AuditEnabled reports whether access records are being written
+func AuditEnabled() bool {
+	auditLog.mutex.Lock()
+	defer auditLog.mutex.Unlock()
+
+	return auditLog.file != nil
+}
+
+// RecordAccess appends an access record for keyPath in the handler's file to the audit log,
+// if one is set. A record that can't be written is logged rather than failing the access.
+func (h *JSONHandler) RecordAccess(keyPath, operation string) {
+	auditLog.mutex.Lock()
+	defer auditLog.mutex.Unlock()
+
+	if auditLog.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(AccessRecord{
+		Timestamp: time.Now().UTC(),
+		File:      h.filePath,
+		KeyPath:   keyPath,
+		Operation: operation,
+	})
+	if err == nil {
+		_, err = auditLog.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		log.Printf("Failed to write audit record for %s: %v", h.filePath, err)
+	}
+}
+
+// auditWrite records a save that changed paths, or the whole document when no paths are known
+func (h *JSONHandler) auditWrite(paths []string) {
+	if len(paths) == 0 {
+		h.RecordAccess("", AuditWrite)
+		return
+	}
+	for _, path := range paths {
+		h.RecordAccess(path, AuditWrite)
+	}
+}
\ No newline at end of file
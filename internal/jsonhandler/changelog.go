@@ -0,0 +1,148 @@
+package jsonhandler
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"jsonmcptool/internal/pathresolver"
+)
+
+// MaxChangeRecords is the number of saves whose changed paths are kept per file
+const MaxChangeRecords = 100
+
+// ChangeRecord lists the paths changed by one save
+type ChangeRecord struct {
+	Time  time.Time `json:"time"`
+	Paths []string  `json:"paths"`
+}
+
+// recordChanges appends the paths that differ between previous and current to the change
+// log, dropping the oldest record when full, and returns them; the caller must hold h.mutex
+func (h *JSONHandler) recordChanges(previous, current map[string]interface{}) []string {
+	var paths []string
+	changedPaths(previous, current, "", &paths)
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+
+	h.changes = append(h.changes, ChangeRecord{Time: time.Now(), Paths: paths})
+	if len(h.changes) > MaxChangeRecords {
+		h.changes = h.changes[len(h.changes)-MaxChangeRecords:]
+	}
+	return paths
+}
+
+// RecentChanges returns up to limit change records, newest first. A limit of zero or
+// less returns every record kept.
+func (h *JSONHandler) RecentChanges(limit int) []ChangeRecord {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if limit <= 0 || limit > len(h.changes) {
+		limit = len(h.changes)
+	}
+
+	records := make([]ChangeRecord, 0, limit)
+	for i := len(h.changes) - 1; i >= len(h.changes)-limit; i-- {
+		records = append(records, h.changes[i])
+	}
+	return records
+}
+
+// WriteCapture collects the file's content before the first and after the last write made
+// through its handler while the capture is active
+type WriteCapture struct {
+	handler *JSONHandler
+	before  []byte
+	after   []byte
+	wrote   bool
+}
+
+// CaptureWrites starts recording the on-disk content around the handler's writes. The
+// replaced content is read just before each write, while the file is locked, so the capture
+// holds exactly what the writes changed. Stop must be called to end it.
+func (h *JSONHandler) CaptureWrites() *WriteCapture {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	capture := &WriteCapture{handler: h}
+	h.captures = append(h.captures, capture)
+	return capture
+}
+
+// Stop ends the capture and returns the content before the first write and after the last
+// one; wrote is false if nothing was written while it was active
+func (c *WriteCapture) Stop() (before, after []byte, wrote bool) {
+	h := c.handler
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, capture := range h.captures {
+		if capture == c {
+			h.captures = append(h.captures[:i], h.captures[i+1:]...)
+			break
+		}
+	}
+	return c.before, c.after, c.wrote
+}
+
+// capturedContent returns the file's current content if a capture is active, reading a
+// missing file as empty; the caller must hold h.mutex
+func (h *JSONHandler) capturedContent() []byte {
+	if len(h.captures) == 0 {
+		return nil
+	}
+	content, _ := os.ReadFile(h.filePath)
+	return content
+}
+
+// captureWrite records a completed write replacing previous with content in every active
+// capture; the caller must hold h.mutex
+func (h *JSONHandler) captureWrite(previous, content []byte) {
+	for _, capture := range h.captures {
+		if !capture.wrote {
+			capture.before = previous
+			capture.wrote = true
+		}
+		capture.after = content
+	}
+}
+
+// changedPaths records the paths under prefix where previous and current differ. Added and
+// removed keys are reported themselves; an array whose length changed is reported as a whole.
+func changedPaths(previous, current interface{}, prefix string, paths *[]string) {
+	switch prev := previous.(type) {
+	case map[string]interface{}:
+		if cur, ok := current.(map[string]interface{}); ok {
+			for key, value := range prev {
+				path := pathresolver.JoinPath(prefix, key)
+				if other, exists := cur[key]; exists {
+					changedPaths(value, other, path, paths)
+				} else {
+					*paths = append(*paths, path)
+				}
+			}
+			for key := range cur {
+				if _, exists := prev[key]; !exists {
+					*paths = append(*paths, pathresolver.JoinPath(prefix, key))
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if cur, ok := current.([]interface{}); ok && len(cur) == len(prev) {
+			for i := range prev {
+				changedPaths(prev[i], cur[i], pathresolver.JoinPath(prefix, strconv.Itoa(i)), paths)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(previous, current) {
+		*paths = append(*paths, prefix)
+	}
+}
\ No newline at end of file
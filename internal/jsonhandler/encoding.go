@@ -0,0 +1,181 @@
+package jsonhandler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings reported by DetectEncoding
+const (
+	EncodingUTF8    = "UTF-8"
+	EncodingUTF16LE = "UTF-16LE"
+	EncodingUTF16BE = "UTF-16BE"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// EncodingInfo describes the character encoding of JSON content
+type EncodingInfo struct {
+	Encoding string `json:"encoding"`
+	BOM      bool   `json:"bom"`
+}
+
+// DetectEncoding sniffs the encoding of JSON content from its byte order mark or,
+// without one, from the zero bytes around the first (always ASCII) character.
+// Only the first few bytes are inspected.
+func DetectEncoding(data []byte) EncodingInfo {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingInfo{Encoding: EncodingUTF8, BOM: true}
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingInfo{Encoding: EncodingUTF16LE, BOM: true}
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingInfo{Encoding: EncodingUTF16BE, BOM: true}
+	}
+
+	if len(data) >= 2 {
+		if data[0] == 0 && data[1] != 0 {
+			return EncodingInfo{Encoding: EncodingUTF16BE}
+		}
+		if data[0] != 0 && data[1] == 0 {
+			return EncodingInfo{Encoding: EncodingUTF16LE}
+		}
+	}
+
+	return EncodingInfo{Encoding: EncodingUTF8}
+}
+
+// DecodeToUTF8 converts UTF-16 content to UTF-8 and strips any byte order mark,
+// so the result can be handed to encoding/json
+func DecodeToUTF8(data []byte) ([]byte, error) {
+	info := DetectEncoding(data)
+
+	var order binary.ByteOrder
+	switch info.Encoding {
+	case EncodingUTF16LE:
+		order = binary.LittleEndian
+	case EncodingUTF16BE:
+		order = binary.BigEndian
+	default:
+		if info.BOM {
+			return data[len(bomUTF8):], nil
+		}
+		return data, nil
+	}
+
+	if info.BOM {
+		data = data[2:]
+	}
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("%w: %s content has an odd number of bytes", ErrParseError, info.Encoding)
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+
+	decoded := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		decoded = utf8.AppendRune(decoded, r)
+	}
+	return decoded, nil
+}
+
+// NewUTF8Reader returns a reader that yields r's content as DecodeToUTF8 would, transcoding
+// UTF-16 and dropping any byte order mark as it goes, so large files need not be read whole
+func NewUTF8Reader(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	head, _ := buffered.Peek(4)
+	info := DetectEncoding(head)
+
+	switch info.Encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if info.BOM {
+			buffered.Discard(2)
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if info.Encoding == EncodingUTF16BE {
+			order = binary.BigEndian
+		}
+		return &utf16Reader{src: buffered, order: order, encoding: info.Encoding}
+	}
+
+	if info.BOM {
+		buffered.Discard(len(bomUTF8))
+	}
+	return buffered
+}
+
+// utf16Reader transcodes a UTF-16 stream to UTF-8. Unpaired surrogates become U+FFFD,
+// as with utf16.Decode.
+type utf16Reader struct {
+	src      *bufio.Reader
+	order    binary.ByteOrder
+	encoding string
+	pending  []byte
+	held     *uint16
+	err      error
+}
+
+// Read implements io.Reader
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.pending) < len(p) && u.err == nil {
+		unit, err := u.nextUnit()
+		if err != nil {
+			u.err = err
+			break
+		}
+
+		r := rune(unit)
+		if utf16.IsSurrogate(r) {
+			next, err := u.nextUnit()
+			switch {
+			case err == nil && utf16.DecodeRune(r, rune(next)) != utf8.RuneError:
+				r = utf16.DecodeRune(r, rune(next))
+			case err == nil:
+				// Not a valid pair; the second unit starts the next rune
+				u.held = &next
+				r = utf8.RuneError
+			default:
+				u.err = err
+				r = utf8.RuneError
+			}
+		}
+		u.pending = utf8.AppendRune(u.pending, r)
+	}
+
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	if n == 0 && u.err != nil {
+		return 0, u.err
+	}
+	return n, nil
+}
+
+// nextUnit reads one UTF-16 code unit, returning a held-back unit first
+func (u *utf16Reader) nextUnit() (uint16, error) {
+	if u.held != nil {
+		unit := *u.held
+		u.held = nil
+		return unit, nil
+	}
+
+	var buf [2]byte
+	if _, err := io.ReadFull(u.src, buf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, fmt.Errorf("%w: %s content has an odd number of bytes", ErrParseError, u.encoding)
+		}
+		return 0, err
+	}
+	return u.order.Uint16(buf[:]), nil
+}
\ No newline at end of file
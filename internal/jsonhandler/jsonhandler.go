@@ -1,78 +1,424 @@
 package jsonhandler
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	ErrFileNotFound   = errors.New("FILE_NOT_FOUND")
-	ErrInvalidJSON    = errors.New("INVALID_JSON")
-	ErrFileReadError  = errors.New("FILE_READ_ERROR")
-	ErrFileWriteError = errors.New("FILE_WRITE_ERROR")
-	ErrParseError     = errors.New("PARSE_ERROR")
-	ErrUnknownError   = errors.New("UNKNOWN_ERROR")
+	ErrFileNotFound      = errors.New("FILE_NOT_FOUND")
+	ErrInvalidJSON       = errors.New("INVALID_JSON")
+	ErrFileReadError     = errors.New("FILE_READ_ERROR")
+	ErrFileWriteError    = errors.New("FILE_WRITE_ERROR")
+	ErrParseError        = errors.New("PARSE_ERROR")
+	ErrUnknownError      = errors.New("UNKNOWN_ERROR")
+	ErrFileTooLarge      = errors.New("FILE_TOO_LARGE")
+	ErrConflict          = errors.New("CONFLICT")
+	ErrNotRegularFile    = errors.New("NOT_A_REGULAR_FILE")
+	ErrCircularStructure = errors.New("CIRCULAR_STRUCTURE")
+	ErrVerifyFailed      = errors.New("VERIFY_FAILED")
 )
 
+// MaxHistory is the number of edits kept per file for undo
+const MaxHistory = 20
+
+// DefaultMaxFileSize is the largest file, in bytes, loaded into memory unless overridden
+const DefaultMaxFileSize int64 = 64 << 20
+
+var maxFileSize atomic.Int64
+
+func init() {
+	maxFileSize.Store(DefaultMaxFileSize)
+}
+
+// SetMaxFileSize sets the largest file size, in bytes, that will be loaded into memory.
+// A value of zero or less disables the guard.
+func SetMaxFileSize(size int64) {
+	maxFileSize.Store(size)
+}
+
+// MaxFileSize returns the current file size limit in bytes, or zero or less if disabled
+func MaxFileSize() int64 {
+	return maxFileSize.Load()
+}
+
+// checkFileSize rejects files larger than the configured limit before they are read
+func checkFileSize(filePath string, size int64) error {
+	if limit := maxFileSize.Load(); limit > 0 && size > limit {
+		return fmt.Errorf("%w: File %s is %d bytes, exceeding the %d byte limit", ErrFileTooLarge, filePath, size, limit)
+	}
+	return nil
+}
+
+// checkRegularFile rejects directories, devices, pipes and sockets, which can't be read as
+// JSON documents. fileInfo must come from os.Stat, so symlinks are judged by their target.
+func checkRegularFile(filePath string, fileInfo os.FileInfo) error {
+	mode := fileInfo.Mode()
+	if mode.IsRegular() {
+		return nil
+	}
+
+	kind := "not a regular file"
+	switch {
+	case mode.IsDir():
+		kind = "a directory"
+	case mode&os.ModeNamedPipe != 0:
+		kind = "a named pipe"
+	case mode&os.ModeSocket != 0:
+		kind = "a socket"
+	case mode&os.ModeDevice != 0:
+		kind = "a device"
+	}
+	return fmt.Errorf("%w: %s is %s; expected a JSON file", ErrNotRegularFile, filePath, kind)
+}
+
 // JSONHandler handles JSON file operations with caching support
 type JSONHandler struct {
 	filePath   string
 	cachedData map[string]interface{}
 	fileMTime  time.Time
+	fileSize   int64
 	mutex      sync.RWMutex
+	editMutex  sync.Mutex
+	editors    atomic.Int32
+	editMTime  time.Time
+	editSize   int64
+	options    *fileOptions
+
+	// Undo/redo history of whole documents; only valid while the file still
+	// matches historyMTime/historySize, the stat recorded after our last write
+	undoStack    []map[string]interface{}
+	redoStack    []map[string]interface{}
+	historyMTime time.Time
+	historySize  int64
+
+	// Cache statistics, guarded by mutex
+	stats CacheStats
+
+	// Paths changed by recent saves, oldest first, guarded by mutex
+	changes []ChangeRecord
+
+	// Active captures of the content around writes, guarded by mutex
+	captures []*WriteCapture
+}
+
+// CacheStats counts how LoadJSON requests were served
+type CacheStats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Reloads       int64 `json:"reloads"`
+	UncachedReads int64 `json:"uncached_reads"`
 }
 
 // NewJSONHandler creates a new JSON handler for a specific file
 func NewJSONHandler(filePath string) *JSONHandler {
 	return &JSONHandler{
 		filePath: filePath,
+		options:  &fileOptions{},
 	}
 }
 
-// LoadJSON loads JSON data from file with optional caching
+// LoadJSON loads JSON data from file with optional caching, recording a read of the whole
+// document in the audit log
 func (h *JSONHandler) LoadJSON(useCache bool) (map[string]interface{}, error) {
+	return h.LoadJSONAt("", useCache)
+}
+
+// LoadJSONAt loads JSON data like LoadJSON for reading the value at keyPath, which is
+// recorded in the audit log instead of the whole document
+func (h *JSONHandler) LoadJSONAt(keyPath string, useCache bool) (map[string]interface{}, error) {
+	data, _, err := h.loadJSON(useCache)
+	if err != nil {
+		return nil, err
+	}
+	h.RecordAccess(keyPath, AuditRead)
+	return data, nil
+}
+
+// loadJSON loads JSON data and also returns the stat info the data corresponds to
+func (h *JSONHandler) loadJSON(useCache bool) (map[string]interface{}, os.FileInfo, error) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
 	// Check if file exists
 	fileInfo, err := os.Stat(h.filePath)
 	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("%w: File %s not found", ErrFileNotFound, h.filePath)
+		return nil, nil, fmt.Errorf("%w: File %s not found", ErrFileNotFound, h.filePath)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("%w: Failed to stat %s: %v", ErrFileReadError, h.filePath, err)
+		return nil, nil, fmt.Errorf("%w: Failed to stat %s: %v", ErrFileReadError, h.filePath, err)
 	}
 
 	currentMTime := fileInfo.ModTime()
 
+	if err := checkRegularFile(h.filePath, fileInfo); err != nil {
+		return nil, nil, err
+	}
+	if err := checkFileSize(h.filePath, fileInfo.Size()); err != nil {
+		return nil, nil, err
+	}
+
 	// Use cache if available and file hasn't changed
-	if useCache && h.cachedData != nil && h.fileMTime.Equal(currentMTime) {
-		return h.cachedData, nil
+	if useCache && h.cachedData != nil && h.fileMTime.Equal(currentMTime) && h.fileSize == fileInfo.Size() {
+		h.stats.Hits++
+		return h.cachedData, fileInfo, nil
+	}
+
+	switch {
+	case !useCache:
+		h.stats.UncachedReads++
+	case h.cachedData != nil:
+		h.stats.Reloads++
+	default:
+		h.stats.Misses++
 	}
 
 	// Read and parse file
 	data, err := os.ReadFile(h.filePath)
 	if err != nil {
-		return nil, fmt.Errorf("%w: Failed to read %s: %v", ErrFileReadError, h.filePath, err)
+		return nil, nil, fmt.Errorf("%w: Failed to read %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	data, err = DecodeToUTF8(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: File %s could not be decoded: %v", ErrInvalidJSON, h.filePath, err)
 	}
 
 	var jsonData map[string]interface{}
 	if err := json.Unmarshal(data, &jsonData); err != nil {
-		return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, h.filePath, err)
+		return nil, nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, h.filePath, err)
 	}
 
 	// Update cache
 	if useCache {
 		h.cachedData = jsonData
 		h.fileMTime = currentMTime
+		h.fileSize = fileInfo.Size()
 	}
 
-	return jsonData, nil
+	return jsonData, fileInfo, nil
+}
+
+// SetEscapeHTML controls whether <, > and & are HTML-escaped when saving (off by default).
+// Handlers from GetHandler share the setting with later handlers for the same file.
+func (h *JSONHandler) SetEscapeHTML(escape bool) {
+	h.options.escapeHTML.Store(escape)
+}
+
+// SetVerifyWrites controls whether saves read the written content back and compare it with
+// the data being saved before replacing the file (off by default). Like SetEscapeHTML, the
+// setting outlives the handler for files opened through GetHandler.
+func (h *JSONHandler) SetVerifyWrites(verify bool) {
+	h.options.verify.Store(verify)
+}
+
+// EscapeHTML reports whether saves HTML-escape <, > and &
+func (h *JSONHandler) EscapeHTML() bool {
+	return h.options.escapeHTML.Load()
+}
+
+// BeginEdit serializes load-modify-save cycles on this handler's file.
+// Every call must be paired with EndEdit.
+func (h *JSONHandler) BeginEdit() {
+	// Count waiters too, so the registry never evicts a handler someone is queued on
+	h.editors.Add(1)
+	h.editMutex.Lock()
+}
+
+// EndEdit releases the edit lock taken by BeginEdit
+func (h *JSONHandler) EndEdit() {
+	h.editMutex.Unlock()
+	h.editors.Add(-1)
+}
+
+// editing reports whether an edit holds or waits for the edit lock
+func (h *JSONHandler) editing() bool {
+	return h.editors.Load() > 0
+}
+
+// hasHistory reports whether the handler holds undo/redo steps or change records that
+// would be lost if it were replaced
+func (h *JSONHandler) hasHistory() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return len(h.undoStack) > 0 || len(h.redoStack) > 0 || len(h.changes) > 0
+}
+
+// LoadJSONForEdit loads JSON data as a private deep copy that can be modified freely.
+// The cached data stays untouched until the copy is saved, so readers never observe
+// partial edits and a failed edit leaves nothing to roll back. The file's modification
+// time and size are recorded so SaveJSONForEdit can detect changes made in between.
+// Must be called between BeginEdit and EndEdit.
+func (h *JSONHandler) LoadJSONForEdit() (map[string]interface{}, error) {
+	data, fileInfo, err := h.loadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	h.editMTime = fileInfo.ModTime()
+	h.editSize = fileInfo.Size()
+	return deepCopy(data).(map[string]interface{}), nil
+}
+
+// SaveJSONForEdit saves data loaded by LoadJSONForEdit, failing with ErrConflict if the
+// file was modified by someone else since it was loaded.
+// Must be called between BeginEdit and EndEdit.
+func (h *JSONHandler) SaveJSONForEdit(data map[string]interface{}, indent int) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	fileInfo, err := h.checkEditBase()
+	if err != nil {
+		return err
+	}
+
+	// The cached data is the document the edit started from
+	previous := h.cachedData
+	if err := h.saveJSON(data, indent); err != nil {
+		return err
+	}
+
+	h.pushHistory(previous, fileInfo)
+	return nil
+}
+
+// ReplaceContentsForEdit is SaveJSONForEdit for edits that produce the file's text directly,
+// such as ones that must keep each number exactly as written. content must hold a JSON
+// object; it is cached as if loaded from the file.
+// Must be called between BeginEdit and EndEdit.
+func (h *JSONHandler) ReplaceContentsForEdit(content []byte) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("%w: Content is not a JSON object: %v", ErrInvalidJSON, err)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	fileInfo, err := h.checkEditBase()
+	if err != nil {
+		return err
+	}
+
+	previous := h.cachedData
+	if err := h.writeContents(content); err != nil {
+		return err
+	}
+
+	var changed []string
+	if previous != nil {
+		changed = h.recordChanges(previous, data)
+	}
+	h.auditWrite(changed)
+
+	h.cachedData = data
+	if written, err := os.Stat(h.filePath); err == nil {
+		h.fileMTime = written.ModTime()
+		h.fileSize = written.Size()
+	}
+
+	h.pushHistory(previous, fileInfo)
+	return nil
+}
+
+// checkEditBase fails with ErrConflict unless the file is as LoadJSONForEdit found it,
+// returning its current stat; the caller must hold h.mutex
+func (h *JSONHandler) checkEditBase() (os.FileInfo, error) {
+	fileInfo, err := os.Stat(h.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: Failed to stat %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	if err != nil || !fileInfo.ModTime().Equal(h.editMTime) || fileInfo.Size() != h.editSize {
+		return nil, fmt.Errorf("%w: File %s was modified since it was loaded", ErrConflict, h.filePath)
+	}
+	return fileInfo, nil
+}
+
+// pushHistory makes previous, the document an edit replaced, the newest undo step.
+// fileInfo is the file's stat before the edit; the caller must hold h.mutex.
+func (h *JSONHandler) pushHistory(previous map[string]interface{}, fileInfo os.FileInfo) {
+	if previous == nil || !h.historyMatches(fileInfo) {
+		h.undoStack = nil
+	}
+	if previous != nil {
+		h.undoStack = append(h.undoStack, previous)
+		if len(h.undoStack) > MaxHistory {
+			h.undoStack = h.undoStack[len(h.undoStack)-MaxHistory:]
+		}
+	}
+	h.redoStack = nil
+	h.historyMTime = h.fileMTime
+	h.historySize = h.fileSize
+}
+
+// Undo restores the document as it was before the last edit saved through
+// SaveJSONForEdit. It reports false if there is nothing to undo; history is
+// discarded when the file was modified by anything else since that edit.
+// Must be called between BeginEdit and EndEdit.
+func (h *JSONHandler) Undo(indent int) (bool, error) {
+	return h.stepHistory(&h.undoStack, &h.redoStack, indent)
+}
+
+// Redo reapplies the last edit reverted by Undo, reporting false if there is none.
+// Must be called between BeginEdit and EndEdit.
+func (h *JSONHandler) Redo(indent int) (bool, error) {
+	return h.stepHistory(&h.redoStack, &h.undoStack, indent)
+}
+
+// HistoryLen returns the number of edits currently available to undo and redo
+func (h *JSONHandler) HistoryLen() (undo, redo int) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return len(h.undoStack), len(h.redoStack)
+}
+
+// stepHistory saves the top document of from and pushes the current one onto to
+func (h *JSONHandler) stepHistory(from, to *[]map[string]interface{}, indent int) (bool, error) {
+	current, fileInfo, err := h.loadJSON(true)
+	if err != nil {
+		return false, err
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if !h.historyMatches(fileInfo) {
+		h.undoStack = nil
+		h.redoStack = nil
+		return false, nil
+	}
+	if len(*from) == 0 {
+		return false, nil
+	}
+
+	target := (*from)[len(*from)-1]
+	if err := h.saveJSON(target, indent); err != nil {
+		return false, err
+	}
+
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, current)
+	h.historyMTime = h.fileMTime
+	h.historySize = h.fileSize
+	return true, nil
+}
+
+// historyMatches reports whether fileInfo still describes the file as we last wrote it;
+// the caller must hold h.mutex
+func (h *JSONHandler) historyMatches(fileInfo os.FileInfo) bool {
+	return fileInfo.ModTime().Equal(h.historyMTime) && fileInfo.Size() == h.historySize
 }
 
 // SaveJSON saves JSON data to file with atomic write
@@ -80,6 +426,11 @@ func (h *JSONHandler) SaveJSON(data map[string]interface{}, indent int) error {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
+	return h.saveJSON(data, indent)
+}
+
+// saveJSON performs the atomic write; the caller must hold h.mutex
+func (h *JSONHandler) saveJSON(data map[string]interface{}, indent int) error {
 	// Use atomic write - write to temp file then rename
 	dir := filepath.Dir(h.filePath)
 	tempFile, err := os.CreateTemp(dir, "*.tmp")
@@ -94,28 +445,181 @@ func (h *JSONHandler) SaveJSON(data map[string]interface{}, indent int) error {
 		os.Remove(tempPath)
 	}()
 
-	// Encode JSON with indentation
-	encoder := json.NewEncoder(tempFile)
-	encoder.SetIndent("", getIndentString(indent))
-	encoder.SetEscapeHTML(false)
-	
-	if err := encoder.Encode(data); err != nil {
-		return fmt.Errorf("%w: Failed to encode JSON: %v", ErrFileWriteError, err)
+	// An edit may have stored the same object or array at several paths. Saving writes a
+	// copy of each, but the cache must not share them either, or editing one would edit all.
+	shared, err := findAliasing(data)
+	if err != nil {
+		return err
+	}
+	if shared {
+		data = deepCopy(data).(map[string]interface{})
+	}
+
+	content, err := h.encodeJSON(data, indent)
+	if err != nil {
+		return err
+	}
+	if _, err := tempFile.Write(content); err != nil {
+		return fmt.Errorf("%w: Failed to write temp file: %v", ErrFileWriteError, err)
 	}
 
 	if err := tempFile.Close(); err != nil {
 		return fmt.Errorf("%w: Failed to close temp file: %v", ErrFileWriteError, err)
 	}
 
+	if h.options.verify.Load() {
+		if err := verifyWritten(tempPath, data); err != nil {
+			return fmt.Errorf("%w: %s was not saved: %v", ErrVerifyFailed, h.filePath, err)
+		}
+	}
+
 	// Atomic rename
+	previous := h.capturedContent()
 	if err := os.Rename(tempPath, h.filePath); err != nil {
 		return fmt.Errorf("%w: Failed to rename temp file: %v", ErrFileWriteError, err)
 	}
+	h.captureWrite(previous, content)
+
+	var changed []string
+	if h.cachedData != nil {
+		changed = h.recordChanges(h.cachedData, data)
+	}
+	h.auditWrite(changed)
 
 	// Update cache
 	h.cachedData = data
 	if fileInfo, err := os.Stat(h.filePath); err == nil {
 		h.fileMTime = fileInfo.ModTime()
+		h.fileSize = fileInfo.Size()
+	}
+
+	return nil
+}
+
+// EncodeJSON returns the exact bytes SaveJSON would write for data
+func (h *JSONHandler) EncodeJSON(data map[string]interface{}, indent int) ([]byte, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.encodeJSON(data, indent)
+}
+
+// encodeJSON encodes data with the file's save options; the caller must hold h.mutex
+func (h *JSONHandler) encodeJSON(data map[string]interface{}, indent int) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", getIndentString(indent))
+	encoder.SetEscapeHTML(h.options.escapeHTML.Load())
+
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("%w: Failed to encode JSON: %v", ErrFileWriteError, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// ReadContents returns the raw file content transcoded to UTF-8, subject to the file size limit
+func (h *JSONHandler) ReadContents() ([]byte, error) {
+	fileInfo, err := os.Stat(h.filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: File %s not found", ErrFileNotFound, h.filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to stat %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	if err := checkRegularFile(h.filePath, fileInfo); err != nil {
+		return nil, err
+	}
+	if err := checkFileSize(h.filePath, fileInfo.Size()); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(h.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to read %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	data, err = DecodeToUTF8(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: File %s could not be decoded: %v", ErrInvalidJSON, h.filePath, err)
+	}
+	h.RecordAccess("", AuditRead)
+	return data, nil
+}
+
+// ReplaceContents atomically overwrites the file with raw content and drops the cached data.
+// With write verification on, the temp file must read back byte for byte before it replaces
+// the file.
+func (h *JSONHandler) ReplaceContents(content []byte) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.writeContents(content); err != nil {
+		return err
+	}
+	h.RecordAccess("", AuditWrite)
+
+	h.cachedData = nil
+	h.fileMTime = time.Time{}
+	h.fileSize = 0
+	return nil
+}
+
+// writeContents atomically overwrites the file with content, verifying it when asked to;
+// the caller must hold h.mutex
+func (h *JSONHandler) writeContents(content []byte) error {
+	var check func(tempPath string) error
+	if h.options.verify.Load() {
+		check = func(tempPath string) error {
+			if err := verifyContent(tempPath, content); err != nil {
+				return fmt.Errorf("%w: %s was not saved: %v", ErrVerifyFailed, h.filePath, err)
+			}
+			return nil
+		}
+	}
+
+	previous := h.capturedContent()
+	if err := writeFileAtomic(h.filePath, content, check); err != nil {
+		return err
+	}
+	h.captureWrite(previous, content)
+	return nil
+}
+
+// WriteFileAtomic writes content to a temp file next to filePath and renames it into place
+func WriteFileAtomic(filePath string, content []byte) error {
+	return writeFileAtomic(filePath, content, nil)
+}
+
+// writeFileAtomic is WriteFileAtomic with an optional check of the temp file, run after it
+// is closed; an error from check leaves filePath untouched
+func writeFileAtomic(filePath string, content []byte, check func(tempPath string) error) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), "*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: Failed to create temp file: %v", ErrFileWriteError, err)
+	}
+	tempPath := tempFile.Name()
+
+	defer func() {
+		tempFile.Close()
+		// Clean up temp file if it still exists
+		os.Remove(tempPath)
+	}()
+
+	if _, err := tempFile.Write(content); err != nil {
+		return fmt.Errorf("%w: Failed to write temp file: %v", ErrFileWriteError, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("%w: Failed to close temp file: %v", ErrFileWriteError, err)
+	}
+
+	if check != nil {
+		if err := check(tempPath); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tempPath, filePath); err != nil {
+		return fmt.Errorf("%w: Failed to rename temp file: %v", ErrFileWriteError, err)
 	}
 
 	return nil
@@ -128,6 +632,7 @@ type ValidationResult struct {
 	Error       *ValidationError       `json:"error,omitempty"`
 	ErrorType   string                 `json:"error_type,omitempty"`
 	Performance *PerformanceMetrics    `json:"performance,omitempty"`
+	Errors      []ValidationError      `json:"errors,omitempty"`
 }
 
 // ValidationError represents a JSON validation error
@@ -145,6 +650,16 @@ type PerformanceMetrics struct {
 
 // ValidateJSONSyntax validates JSON file syntax without loading into memory completely
 func (h *JSONHandler) ValidateJSONSyntax() *ValidationResult {
+	return h.validateJSONSyntax(false)
+}
+
+// ValidateJSONSyntaxAll validates like ValidateJSONSyntax, but on a syntax error keeps
+// scanning and lists every error it can find (up to MaxSyntaxErrors) in Errors
+func (h *JSONHandler) ValidateJSONSyntaxAll() *ValidationResult {
+	return h.validateJSONSyntax(true)
+}
+
+func (h *JSONHandler) validateJSONSyntax(collectAll bool) *ValidationResult {
 	result := &ValidationResult{
 		File: h.filePath,
 	}
@@ -161,9 +676,41 @@ func (h *JSONHandler) ValidateJSONSyntax() *ValidationResult {
 		}
 		return result
 	}
+	if err != nil {
+		result.Valid = false
+		result.ErrorType = "FILE_READ_ERROR"
+		result.Error = &ValidationError{
+			Message: fmt.Sprintf("Failed to stat %s: %v", h.filePath, err),
+			Line:    0,
+			Column:  0,
+		}
+		return result
+	}
+
+	if err := checkRegularFile(h.filePath, fileInfo); err != nil {
+		result.Valid = false
+		result.ErrorType = "NOT_A_REGULAR_FILE"
+		result.Error = &ValidationError{
+			Message: err.Error(),
+			Line:    0,
+			Column:  0,
+		}
+		return result
+	}
 
 	fileSize := fileInfo.Size()
 
+	if err := checkFileSize(h.filePath, fileSize); err != nil {
+		result.Valid = false
+		result.ErrorType = "FILE_TOO_LARGE"
+		result.Error = &ValidationError{
+			Message: err.Error(),
+			Line:    0,
+			Column:  0,
+		}
+		return result
+	}
+
 	if fileSize == 0 {
 		result.Valid = false
 		result.ErrorType = "PARSE_ERROR"
@@ -189,6 +736,19 @@ func (h *JSONHandler) ValidateJSONSyntax() *ValidationResult {
 		return result
 	}
 
+	// Transcode UTF-16 content so positions refer to the decoded text
+	data, err = DecodeToUTF8(data)
+	if err != nil {
+		result.Valid = false
+		result.ErrorType = "PARSE_ERROR"
+		result.Error = &ValidationError{
+			Message: fmt.Sprintf("Failed to decode file: %v", err),
+			Line:    0,
+			Column:  0,
+		}
+		return result
+	}
+
 	// Check for empty content after reading
 	if len(data) == 0 {
 		result.Valid = false
@@ -222,6 +782,13 @@ func (h *JSONHandler) ValidateJSONSyntax() *ValidationResult {
 				Column:  0,
 			}
 		}
+
+		if collectAll {
+			result.Errors = FindSyntaxErrors(data, MaxSyntaxErrors)
+			if len(result.Errors) == 0 {
+				result.Errors = []ValidationError{*result.Error}
+			}
+		}
 		return result
 	}
 
@@ -243,6 +810,16 @@ func (h *JSONHandler) ClearCache() {
 	
 	h.cachedData = nil
 	h.fileMTime = time.Time{}
+	h.fileSize = 0
+}
+
+// CacheStats returns the cache hit, miss and reload counts since the handler was created.
+// A reload is a read that found the cached data stale because the file changed.
+func (h *JSONHandler) CacheStats() CacheStats {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.stats
 }
 
 // FileInfo represents file information
@@ -275,6 +852,339 @@ func (h *JSONHandler) GetFileInfo() *FileInfo {
 	return info
 }
 
+// KeyLocation represents where a key appears in the source text
+type KeyLocation struct {
+	Offset int64 `json:"offset"`
+	Line   int   `json:"line"`
+	Column int   `json:"column"`
+}
+
+// locateFrame tracks the container currently being scanned by LocateKey
+type locateFrame struct {
+	path      string
+	isObject  bool
+	expectKey bool
+	key       string
+	index     int
+}
+
+// childPath returns the path of the value about to be read in this container
+func (f *locateFrame) childPath() string {
+	if f.isObject {
+		return joinPath(f.path, f.key)
+	}
+	return joinPath(f.path, strconv.Itoa(f.index))
+}
+
+// valueDone advances the container past a completed child value
+func (f *locateFrame) valueDone() {
+	if f.isObject {
+		f.expectKey = true
+	} else {
+		f.index++
+	}
+}
+
+// LocateKey scans the file's tokens to find where the key at keyPath is defined.
+// Array elements are addressed by index. Returns nil if the key does not occur.
+// Positions in UTF-16 files or files with a byte order mark refer to the decoded UTF-8 text.
+func (h *JSONHandler) LocateKey(keyPath string) (*KeyLocation, error) {
+	fileInfo, err := os.Stat(h.filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: File %s not found", ErrFileNotFound, h.filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to stat %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	if err := checkRegularFile(h.filePath, fileInfo); err != nil {
+		return nil, err
+	}
+	if err := checkFileSize(h.filePath, fileInfo.Size()); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(h.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to read %s: %v", ErrFileReadError, h.filePath, err)
+	}
+	data, err = DecodeToUTF8(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: File %s could not be decoded: %v", ErrInvalidJSON, h.filePath, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var stack []*locateFrame
+
+	for {
+		before := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, h.filePath, err)
+		}
+
+		var top *locateFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch tok := token.(type) {
+		case json.Delim:
+			if tok == '{' || tok == '[' {
+				path := ""
+				if top != nil {
+					path = top.childPath()
+				}
+				stack = append(stack, &locateFrame{path: path, isObject: tok == '{', expectKey: tok == '{'})
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+		case string:
+			if top != nil && top.isObject && top.expectKey {
+				top.key = tok
+				top.expectKey = false
+				if top.childPath() == keyPath {
+					// Skip the separator consumed along with the key to land on its opening quote
+					start := before
+					for start < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,"), data[start]) >= 0 {
+						start++
+					}
+					line, col := getLineColumn(data, start)
+					return &KeyLocation{Offset: start, Line: line, Column: col}, nil
+				}
+				continue
+			}
+			if top != nil {
+				top.valueDone()
+			}
+		default:
+			if top != nil {
+				top.valueDone()
+			}
+		}
+	}
+}
+
+// TextSpan is the range of source text a value occupies. End is exclusive, and its line and
+// column are those of the first character after the value.
+type TextSpan struct {
+	Start       int64 `json:"start_offset"`
+	End         int64 `json:"end_offset"`
+	StartLine   int   `json:"start_line"`
+	StartColumn int   `json:"start_column"`
+	EndLine     int   `json:"end_line"`
+	EndColumn   int   `json:"end_column"`
+}
+
+// SubtreeSpan scans the file's tokens to find the text range of the value at keyPath; an empty
+// keyPath spans the whole document. Array elements are addressed by index. Returns nil if the
+// path does not occur. Offsets in UTF-16 files or files with a byte order mark refer to the
+// decoded UTF-8 text.
+func (h *JSONHandler) SubtreeSpan(keyPath string) (*TextSpan, error) {
+	data, err := h.ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var stack []*locateFrame
+
+	for {
+		before := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, h.filePath, err)
+		}
+
+		var top *locateFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+			continue
+		}
+		if key, ok := token.(string); ok && top != nil && top.isObject && top.expectKey {
+			top.key = key
+			top.expectKey = false
+			continue
+		}
+
+		// The token starts a value
+		path := ""
+		if top != nil {
+			path = top.childPath()
+		}
+		if path == keyPath {
+			// Skip the separators consumed along with the value to land on its first character
+			start := before
+			for start < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,:"), data[start]) >= 0 {
+				start++
+			}
+			if delim, ok := token.(json.Delim); ok {
+				if err := skipContainer(decoder, delim); err != nil {
+					return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, h.filePath, err)
+				}
+			}
+			end := decoder.InputOffset()
+
+			span := &TextSpan{Start: start, End: end}
+			span.StartLine, span.StartColumn = getLineColumn(data, start)
+			span.EndLine, span.EndColumn = getLineColumn(data, end)
+			return span, nil
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			stack = append(stack, &locateFrame{path: path, isObject: delim == '{', expectKey: delim == '{'})
+			continue
+		}
+		if top != nil {
+			top.valueDone()
+		}
+	}
+}
+
+// skipContainer consumes tokens up to the delimiter closing the container opened by delim
+func skipContainer(decoder *json.Decoder, delim json.Delim) error {
+	depth := 1
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// containerID identifies an object by its map, or an array by its first element and length
+type containerID struct {
+	pointer uintptr
+	length  int
+}
+
+// findAliasing reports whether any object or array is reachable through more than one
+// path in data, which JSON can't express. Arrays count as shared when their backing arrays
+// overlap at all, as with s and s[1:]. A container nested inside itself can't be saved at
+// all and fails with ErrCircularStructure.
+func findAliasing(data map[string]interface{}) (bool, error) {
+	seen := map[containerID]bool{}
+	inPath := map[containerID]bool{}
+	shared := false
+
+	// The [start, end) byte range of every array's backing array visited
+	type span struct{ start, end uintptr }
+	var spans []span
+	elementSize := reflect.TypeOf((*interface{})(nil)).Elem().Size()
+
+	var visit func(value interface{}, path string) error
+	visit = func(value interface{}, path string) error {
+		var id containerID
+		switch v := value.(type) {
+		case map[string]interface{}:
+			id = containerID{pointer: reflect.ValueOf(v).Pointer()}
+		case []interface{}:
+			if cap(v) == 0 {
+				return nil
+			}
+			id = containerID{pointer: reflect.ValueOf(v).Pointer(), length: len(v)}
+		default:
+			return nil
+		}
+
+		if inPath[id] {
+			if path == "" {
+				path = "root"
+			}
+			return fmt.Errorf("%w: Value at '%s' contains itself and cannot be saved as JSON", ErrCircularStructure, path)
+		}
+		if seen[id] {
+			shared = true
+			return nil
+		}
+		seen[id] = true
+		inPath[id] = true
+		defer delete(inPath, id)
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if err := visit(child, joinPath(path, key)); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			spans = append(spans, span{id.pointer, id.pointer + uintptr(cap(v))*elementSize})
+			for i, child := range v {
+				if err := visit(child, joinPath(path, strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := visit(data, ""); err != nil {
+		return false, err
+	}
+	if shared {
+		return true, nil
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	for i := 1; i < len(spans); i++ {
+		if spans[i].start < spans[i-1].end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Helper function to deep copy decoded JSON data
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = deepCopy(child)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// Helper function to join a key onto a dot-notation path
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
 // Helper function to get indent string
 func getIndentString(indent int) string {
 	result := ""
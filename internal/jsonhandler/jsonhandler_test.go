@@ -2,7 +2,12 @@ package jsonhandler
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -63,6 +68,44 @@ func TestLoadJSONFileNotFound(t *testing.T) {
 	}
 }
 
+func TestLoadJSONNotRegularFile(t *testing.T) {
+	dir := t.TempDir()
+	handler := NewJSONHandler(dir)
+
+	if _, err := handler.LoadJSON(false); !errors.Is(err, ErrNotRegularFile) {
+		t.Errorf("LoadJSON() on a directory error = %v, want NOT_A_REGULAR_FILE", err)
+	} else if !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("LoadJSON() error = %v, should say it is a directory", err)
+	}
+	if _, err := handler.ReadContents(); !errors.Is(err, ErrNotRegularFile) {
+		t.Errorf("ReadContents() on a directory error = %v, want NOT_A_REGULAR_FILE", err)
+	}
+
+	result := handler.ValidateJSONSyntax()
+	if result.Valid || result.ErrorType != "NOT_A_REGULAR_FILE" {
+		t.Errorf("ValidateJSONSyntax() on a directory = %+v, want NOT_A_REGULAR_FILE", result)
+	}
+
+	// Symlinks are followed and judged by their target
+	target := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(target)
+	link := filepath.Join(dir, "link.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	if data, err := NewJSONHandler(link).LoadJSON(false); err != nil || data["key"] != "value" {
+		t.Errorf("LoadJSON() through a symlink = %v, %v", data, err)
+	}
+
+	dirLink := filepath.Join(dir, "dir-link")
+	if err := os.Symlink(dir, dirLink); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJSONHandler(dirLink).LoadJSON(false); !errors.Is(err, ErrNotRegularFile) {
+		t.Errorf("LoadJSON() through a symlink to a directory error = %v, want NOT_A_REGULAR_FILE", err)
+	}
+}
+
 func TestLoadJSONInvalidJSON(t *testing.T) {
 	// Create temp file with invalid JSON
 	tempFile, err := os.CreateTemp("", "invalid_*.json")
@@ -202,6 +245,69 @@ func TestValidateJSONSyntaxFileNotFound(t *testing.T) {
 	}
 }
 
+func TestValidateJSONStream(t *testing.T) {
+	utf16 := []byte{0xFF, 0xFE}
+	for _, r := range "{\n\"k\": \"\U0001F600\"\n1}" {
+		for _, unit := range utf16Units(r) {
+			utf16 = append(utf16, byte(unit), byte(unit>>8))
+		}
+	}
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"valid object", "{\"a\": [1, 2, {\"b\": null}], \"c\": \"x\"}\n"},
+		{"valid scalar", " 42 "},
+		{"invalid value", "{\n  \"a\": [1, 2, tru]\n}"},
+		{"missing colon", "{\n  \"a\" 1\n}"},
+		{"truncated", "{\n  \"a\": [1, 2"},
+		{"trailing garbage", "{\"a\": 1}\n  x"},
+		{"whitespace only", "  \n "},
+		{"empty", ""},
+		{"utf-16 with bom", string(utf16)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile, err := os.CreateTemp("", "validate_*.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tempFile.Name())
+			tempFile.WriteString(tt.content)
+			tempFile.Close()
+
+			handler := NewJSONHandler(tempFile.Name())
+			want := handler.ValidateJSONSyntax()
+
+			var lastProcessed, lastTotal int64
+			got := handler.ValidateJSONStream(func(processed, total int64) {
+				lastProcessed, lastTotal = processed, total
+			})
+
+			if got.Valid != want.Valid || got.ErrorType != want.ErrorType {
+				t.Fatalf("ValidateJSONStream() = %v/%s, want %v/%s", got.Valid, got.ErrorType, want.Valid, want.ErrorType)
+			}
+			if want.Error != nil && (got.Error.Line != want.Error.Line || got.Error.Column != want.Error.Column) {
+				t.Errorf("ValidateJSONStream() error at (%d, %d), want (%d, %d)", got.Error.Line, got.Error.Column, want.Error.Line, want.Error.Column)
+			}
+			if tt.content != "" && (lastProcessed != int64(len(tt.content)) || lastTotal != int64(len(tt.content))) {
+				t.Errorf("ValidateJSONStream() last progress = %d/%d, want %d/%d", lastProcessed, lastTotal, len(tt.content), len(tt.content))
+			}
+		})
+	}
+}
+
+// utf16Units encodes r as UTF-16 code units
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+	r -= 0x10000
+	return []uint16{uint16(0xD800 + (r >> 10)), uint16(0xDC00 + (r & 0x3FF))}
+}
+
 func TestClearCache(t *testing.T) {
 	testData := map[string]interface{}{
 		"key": "value",
@@ -321,6 +427,847 @@ func TestCacheInvalidation(t *testing.T) {
 	}
 }
 
+func TestLocateKey(t *testing.T) {
+	content := "{\n  \"auth\": {\n    \"login\": {\"title\": \"Sign In\"},\n    \"list\": [1, {\"name\": \"x\"}]\n  },\n  \"key.with.dots\": true\n}"
+	tempFile, err := os.CreateTemp("", "test_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.WriteString(content)
+	tempFile.Close()
+
+	handler := NewJSONHandler(tempFile.Name())
+
+	tests := []struct {
+		path     string
+		wantLine int
+		wantCol  int
+	}{
+		{"auth", 2, 3},
+		{"auth.login", 3, 5},
+		{"auth.login.title", 3, 15},
+		{"auth.list.1.name", 4, 18},
+		{"key.with.dots", 6, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			location, err := handler.LocateKey(tt.path)
+			if err != nil {
+				t.Fatalf("LocateKey() error = %v", err)
+			}
+			if location == nil {
+				t.Fatal("LocateKey() returned nil location")
+			}
+			if location.Line != tt.wantLine || location.Column != tt.wantCol {
+				t.Errorf("LocateKey(%s) = (%d, %d), want (%d, %d)", tt.path, location.Line, location.Column, tt.wantLine, tt.wantCol)
+			}
+			if content[location.Offset] != '"' {
+				t.Errorf("LocateKey(%s) offset %d does not point at a key", tt.path, location.Offset)
+			}
+		})
+	}
+
+	location, err := handler.LocateKey("auth.missing")
+	if err != nil || location != nil {
+		t.Errorf("LocateKey() for missing key = %v, %v, want nil, nil", location, err)
+	}
+}
+
+func TestSubtreeSpan(t *testing.T) {
+	content := "{\n  \"auth\": {\n    \"login\": {\"title\": \"Sign In\"},\n    \"list\": [1, {\"name\": \"x\"}]\n  },\n  \"key.with.dots\": true\n}\n"
+	tempFile, err := os.CreateTemp("", "test_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tempFile.Name())
+	tempFile.WriteString(content)
+	tempFile.Close()
+
+	handler := NewJSONHandler(tempFile.Name())
+
+	tests := []struct {
+		path     string
+		wantText string
+		wantLine int
+		wantCol  int
+	}{
+		{"", content[:len(content)-1], 1, 1},
+		{"auth.login", `{"title": "Sign In"}`, 3, 14},
+		{"auth.login.title", `"Sign In"`, 3, 24},
+		{"auth.list.0", "1", 4, 14},
+		{"auth.list.1", `{"name": "x"}`, 4, 17},
+		{"key.with.dots", "true", 6, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			span, err := handler.SubtreeSpan(tt.path)
+			if err != nil {
+				t.Fatalf("SubtreeSpan() error = %v", err)
+			}
+			if span == nil {
+				t.Fatal("SubtreeSpan() returned nil span")
+			}
+			if text := content[span.Start:span.End]; text != tt.wantText {
+				t.Errorf("SubtreeSpan(%s) covers %q, want %q", tt.path, text, tt.wantText)
+			}
+			if span.StartLine != tt.wantLine || span.StartColumn != tt.wantCol {
+				t.Errorf("SubtreeSpan(%s) starts at (%d, %d), want (%d, %d)", tt.path, span.StartLine, span.StartColumn, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+
+	span, err := handler.SubtreeSpan("auth.missing")
+	if err != nil || span != nil {
+		t.Errorf("SubtreeSpan() for missing key = %v, %v, want nil, nil", span, err)
+	}
+}
+
+func TestMaxFileSizeGuard(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "a value long enough to exceed the limit"})
+	defer os.Remove(tempFile)
+
+	defer SetMaxFileSize(MaxFileSize())
+	SetMaxFileSize(16)
+
+	handler := NewJSONHandler(tempFile)
+	if _, err := handler.LoadJSON(false); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("LoadJSON() error = %v, want FILE_TOO_LARGE", err)
+	}
+
+	result := handler.ValidateJSONSyntax()
+	if result.Valid || result.ErrorType != "FILE_TOO_LARGE" {
+		t.Errorf("ValidateJSONSyntax() = %+v, want FILE_TOO_LARGE", result)
+	}
+
+	// Disabling the guard allows loading again
+	SetMaxFileSize(0)
+	if _, err := handler.LoadJSON(false); err != nil {
+		t.Errorf("LoadJSON() with guard disabled error = %v", err)
+	}
+}
+
+func TestSaveJSONForEditConflict(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	data, err := handler.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+
+	// Another process rewrites the file in the meantime
+	if err := os.WriteFile(tempFile, []byte(`{"key": "external change"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data["key"] = "our change"
+	if err := handler.SaveJSONForEdit(data, 2); !errors.Is(err, ErrConflict) {
+		t.Errorf("SaveJSONForEdit() error = %v, want CONFLICT", err)
+	}
+
+	// Reloading picks up the external change and saving succeeds
+	data, err = handler.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	if data["key"] != "external change" {
+		t.Errorf("Reloaded key = %v, want external change", data["key"])
+	}
+	data["other"] = "added"
+	if err := handler.SaveJSONForEdit(data, 2); err != nil {
+		t.Errorf("SaveJSONForEdit() error = %v", err)
+	}
+}
+
+func TestReplaceContentsForEdit(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"count": 1.0})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	if _, err := handler.LoadJSONForEdit(); err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	if err := os.WriteFile(tempFile, []byte(`{"count": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.ReplaceContentsForEdit([]byte(`{"count": 3.0}`)); !errors.Is(err, ErrConflict) {
+		t.Errorf("ReplaceContentsForEdit() error = %v, want CONFLICT", err)
+	}
+
+	if _, err := handler.LoadJSONForEdit(); err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	if err := handler.ReplaceContentsForEdit([]byte(`{"count": 3.0}`)); err != nil {
+		t.Fatalf("ReplaceContentsForEdit() error = %v", err)
+	}
+
+	saved, err := os.ReadFile(tempFile)
+	if err != nil || string(saved) != `{"count": 3.0}` {
+		t.Errorf("Saved file = %s (%v), want the content as given", saved, err)
+	}
+	changes := handler.RecentChanges(0)
+	if len(changes) != 1 || !reflect.DeepEqual(changes[0].Paths, []string{"count"}) {
+		t.Errorf("RecentChanges() = %+v, want one change to count", changes)
+	}
+
+	if ok, err := handler.Undo(2); !ok || err != nil {
+		t.Fatalf("Undo() = %v, %v, want the replaced document restored", ok, err)
+	}
+	data, err := handler.LoadJSON(true)
+	if err != nil || data["count"] != 2.0 {
+		t.Errorf("count after undo = %v (%v), want 2", data["count"], err)
+	}
+}
+
+func TestSaveJSONEscapeHTML(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{})
+	defer os.Remove(tempFile)
+
+	data := map[string]interface{}{"html": "<b>Tom & Jerry</b>"}
+	handler := NewJSONHandler(tempFile)
+
+	// Default output keeps characters as-is
+	if err := handler.SaveJSON(data, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	content, _ := os.ReadFile(tempFile)
+	if !strings.Contains(string(content), "<b>Tom & Jerry</b>") {
+		t.Errorf("SaveJSON() should not escape HTML by default, got %s", content)
+	}
+
+	handler.SetEscapeHTML(true)
+	if err := handler.SaveJSON(data, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	content, _ = os.ReadFile(tempFile)
+	if !strings.Contains(string(content), `\u003cb\u003eTom \u0026 Jerry\u003c/b\u003e`) {
+		t.Errorf("SaveJSON() should escape HTML when enabled, got %s", content)
+	}
+}
+
+func TestEditHistoryIsBounded(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"count": 0.0})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	for i := 1; i <= MaxHistory+5; i++ {
+		data, err := handler.LoadJSONForEdit()
+		if err != nil {
+			t.Fatalf("LoadJSONForEdit() error = %v", err)
+		}
+		data["count"] = float64(i)
+		if err := handler.SaveJSONForEdit(data, 2); err != nil {
+			t.Fatalf("SaveJSONForEdit() error = %v", err)
+		}
+	}
+
+	if undo, redo := handler.HistoryLen(); undo != MaxHistory || redo != 0 {
+		t.Fatalf("HistoryLen() = %d, %d, want %d, 0", undo, redo, MaxHistory)
+	}
+
+	for i := 0; i < MaxHistory; i++ {
+		if ok, err := handler.Undo(2); !ok || err != nil {
+			t.Fatalf("Undo() #%d = %v, %v", i+1, ok, err)
+		}
+	}
+	if ok, err := handler.Undo(2); ok || err != nil {
+		t.Errorf("Undo() past history = %v, %v, want false, nil", ok, err)
+	}
+
+	data, _ := handler.LoadJSON(true)
+	if data["count"] != 5.0 {
+		t.Errorf("Oldest undoable count = %v, want 5", data["count"])
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantBOM bool
+	}{
+		{"plain UTF-8", []byte(`{"a":1}`), EncodingUTF8, false},
+		{"UTF-8 with BOM", []byte("\xEF\xBB\xBF{}"), EncodingUTF8, true},
+		{"UTF-16LE with BOM", []byte("\xFF\xFE{\x00}\x00"), EncodingUTF16LE, true},
+		{"UTF-16BE with BOM", []byte("\xFE\xFF\x00{\x00}"), EncodingUTF16BE, true},
+		{"UTF-16LE without BOM", []byte("{\x00}\x00"), EncodingUTF16LE, false},
+		{"UTF-16BE without BOM", []byte("\x00{\x00}"), EncodingUTF16BE, false},
+		{"empty", []byte{}, EncodingUTF8, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := DetectEncoding(tt.data)
+			if info.Encoding != tt.want || info.BOM != tt.wantBOM {
+				t.Errorf("DetectEncoding() = %+v, want %s (BOM %v)", info, tt.want, tt.wantBOM)
+			}
+		})
+	}
+}
+
+func TestLoadJSONUTF16(t *testing.T) {
+	// {"greeting": "héllo €"} as UTF-16LE with BOM
+	text := `{"greeting": "héllo €"}`
+	content := []byte{0xFF, 0xFE}
+	for _, r := range text {
+		content = append(content, byte(r), byte(r>>8))
+	}
+
+	tempFile, err := os.CreateTemp("", "utf16_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempFile.Close()
+	defer os.Remove(tempFile.Name())
+	if err := os.WriteFile(tempFile.Name(), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewJSONHandler(tempFile.Name())
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if data["greeting"] != "héllo €" {
+		t.Errorf("greeting = %v, want héllo €", data["greeting"])
+	}
+
+	if result := handler.ValidateJSONSyntax(); !result.Valid {
+		t.Errorf("ValidateJSONSyntax() = %+v, want valid", result.Error)
+	}
+
+	// Odd byte counts cannot be UTF-16
+	if err := os.WriteFile(tempFile.Name(), append(content, 0x00), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handler.LoadJSON(false); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("LoadJSON() with truncated UTF-16 error = %v, want INVALID_JSON", err)
+	}
+}
+
+func TestFindSyntaxErrorsMatchesUnmarshal(t *testing.T) {
+	inputs := []string{
+		`{"a":}`,
+		`{"a":1 "b":2}`,
+		`[1,]`,
+		`{"a":1,}`,
+		`{"a":tru}`,
+		"{\"a\":\"x\n\"}",
+		`{"a":1}x`,
+		`{"a":01}`,
+		`{"a"`,
+		`{"a" 1}`,
+		`{a: 1}`,
+		`{"a":-}`,
+		`{"a":1.}`,
+		`{"a":1e}`,
+		`['x']`,
+		``,
+	}
+
+	for _, input := range inputs {
+		var v interface{}
+		err := json.Unmarshal([]byte(input), &v)
+		syntaxErr, ok := err.(*json.SyntaxError)
+		if !ok {
+			t.Fatalf("json.Unmarshal(%q) error = %v, want syntax error", input, err)
+		}
+
+		errs := FindSyntaxErrors([]byte(input), MaxSyntaxErrors)
+		if len(errs) == 0 {
+			t.Errorf("FindSyntaxErrors(%q) found no errors, want %q", input, syntaxErr.Error())
+			continue
+		}
+		// Wording varies between Go releases, so only positions are compared
+		line, col := getLineColumn([]byte(input), syntaxErr.Offset)
+		if errs[0].Line != line || errs[0].Column != col {
+			t.Errorf("FindSyntaxErrors(%q)[0] = %+v, want %q at %d:%d", input, errs[0], syntaxErr.Error(), line, col)
+		}
+	}
+}
+
+func TestFindSyntaxErrorsCollectsSeveral(t *testing.T) {
+	input := `{
+  "a": 1
+  "b": tru,
+  "c": [1, 2,],
+  "d": 'x',
+  "e": {"f": "\q"}
+}`
+	errs := FindSyntaxErrors([]byte(input), MaxSyntaxErrors)
+
+	wantLines := []int{3, 3, 4, 5, 6}
+	if len(errs) != len(wantLines) {
+		t.Fatalf("FindSyntaxErrors() = %+v, want errors on lines %v", errs, wantLines)
+	}
+	for i, line := range wantLines {
+		if errs[i].Line != line {
+			t.Errorf("FindSyntaxErrors()[%d] = %+v, want line %d", i, errs[i], line)
+		}
+	}
+
+	if errs := FindSyntaxErrors([]byte(input), 2); len(errs) != 2 {
+		t.Errorf("FindSyntaxErrors() with limit 2 returned %d errors", len(errs))
+	}
+	if errs := FindSyntaxErrors([]byte(`{"valid": [1, {"x": null}]}`), MaxSyntaxErrors); len(errs) != 0 {
+		t.Errorf("FindSyntaxErrors() on valid JSON = %+v, want none", errs)
+	}
+}
+
+func TestDiagnoseSyntax(t *testing.T) {
+	data := []byte("{\n  name: 'it\\'s \"ok\"',\n  \"list\": [1, 2,],\n  \"note\": \"a, } 'quoted' b:\",\n}")
+
+	issues := DiagnoseSyntax(data)
+
+	type found struct {
+		Kind       string
+		Line       int
+		Column     int
+		Text       string
+		Suggestion string
+	}
+	want := []found{
+		{IssueUnquotedKey, 2, 3, "name", `Quote the key: "name"`},
+		{IssueSingleQuoted, 2, 9, `'it\'s "ok"'`, `Use double quotes: "it's \"ok\""`},
+		{IssueTrailingComma, 3, 16, ",", "Remove the comma"},
+		{IssueTrailingComma, 4, 29, ",", "Remove the comma"},
+	}
+
+	got := make([]found, len(issues))
+	for i, issue := range issues {
+		got[i] = found{issue.Kind, issue.Line, issue.Column, issue.Text, issue.Suggestion}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiagnoseSyntax() = %+v, want %+v", got, want)
+	}
+
+	if issues := DiagnoseSyntax([]byte(`{"valid": [true, "it's"]}`)); len(issues) != 0 {
+		t.Errorf("DiagnoseSyntax() on valid JSON = %+v, want none", issues)
+	}
+}
+
+func TestRepairSyntax(t *testing.T) {
+	data := []byte("{\n  name: 'it\\'s \"ok\"',\n  \"list\": [1, 2,],\n}")
+
+	repaired, issues := RepairSyntax(data)
+
+	want := "{\n  \"name\": \"it's \\\"ok\\\"\",\n  \"list\": [1, 2]\n}"
+	if string(repaired) != want {
+		t.Errorf("RepairSyntax() = %s, want %s", repaired, want)
+	}
+	if len(issues) != 4 {
+		t.Errorf("RepairSyntax() fixed %d issue(s), want 4", len(issues))
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(repaired, &value); err != nil {
+		t.Fatalf("RepairSyntax() output is not valid JSON: %v", err)
+	}
+	if value["name"] != `it's "ok"` {
+		t.Errorf("RepairSyntax() name = %q, want %q", value["name"], `it's "ok"`)
+	}
+
+	valid := []byte(`{"a": [1, 2]}`)
+	if repaired, issues := RepairSyntax(valid); string(repaired) != string(valid) || len(issues) != 0 {
+		t.Errorf("RepairSyntax() changed valid JSON: %s", repaired)
+	}
+}
+
+func TestValidateJSONSyntaxAll(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "invalid_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempFile.WriteString("{\n  \"a\": ,\n  \"b\": [1,]\n}")
+	tempFile.Close()
+	defer os.Remove(tempFile.Name())
+
+	handler := NewJSONHandler(tempFile.Name())
+	if result := handler.ValidateJSONSyntax(); len(result.Errors) != 0 {
+		t.Errorf("ValidateJSONSyntax() should not collect errors, got %+v", result.Errors)
+	}
+
+	result := handler.ValidateJSONSyntaxAll()
+	if result.Valid || result.ErrorType != "PARSE_ERROR" {
+		t.Fatalf("ValidateJSONSyntaxAll() = %+v, want PARSE_ERROR", result)
+	}
+	if len(result.Errors) != 2 || result.Errors[0] != *result.Error || result.Errors[1].Line != 3 {
+		t.Errorf("ValidateJSONSyntaxAll() Errors = %+v, want 2 errors starting with %+v", result.Errors, *result.Error)
+	}
+}
+
+func TestSaveJSONSharedValues(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	shared := map[string]interface{}{"enabled": true}
+	data := map[string]interface{}{"a": shared, "b": shared}
+	if err := handler.SaveJSON(data, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+
+	// Editing one copy through the cache must leave the other alone
+	handler.BeginEdit()
+	edited, err := handler.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	edited["a"].(map[string]interface{})["enabled"] = false
+	handler.EndEdit()
+
+	cached, err := handler.LoadJSON(true)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	cached["a"].(map[string]interface{})["enabled"] = false
+	if cached["b"].(map[string]interface{})["enabled"] != true {
+		t.Error("SaveJSON() cached values shared between paths")
+	}
+	if shared["enabled"] != true {
+		t.Error("SaveJSON() cached the caller's shared value")
+	}
+}
+
+func TestSaveJSONSharedSubslices(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	backing := []interface{}{"a", "b", "c"}
+	data := map[string]interface{}{"all": backing, "tail": backing[1:]}
+	if err := handler.SaveJSON(data, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+
+	cached, err := handler.LoadJSON(true)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	cached["tail"].([]interface{})[0] = "changed"
+	if cached["all"].([]interface{})[1] != "b" {
+		t.Error("SaveJSON() cached arrays sharing a backing array")
+	}
+	if backing[1] != "b" {
+		t.Error("SaveJSON() cached the caller's array")
+	}
+}
+
+func TestSaveJSONCircular(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	inner := map[string]interface{}{}
+	data := map[string]interface{}{"outer": map[string]interface{}{"inner": inner}}
+	inner["loop"] = data["outer"]
+
+	err := handler.SaveJSON(data, 2)
+	if !errors.Is(err, ErrCircularStructure) {
+		t.Fatalf("SaveJSON() error = %v, want %v", err, ErrCircularStructure)
+	}
+	if !strings.Contains(err.Error(), "outer.inner.loop") {
+		t.Errorf("SaveJSON() error = %v, want it to name outer.inner.loop", err)
+	}
+
+	content, _ := os.ReadFile(tempFile)
+	if !strings.Contains(string(content), `"key"`) {
+		t.Errorf("SaveJSON() overwrote the file despite failing: %s", content)
+	}
+}
+
+func TestSaveJSONVerifyWrites(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.SetVerifyWrites(true)
+
+	data := map[string]interface{}{
+		"ratio":  0.1,
+		"count":  int64(9007199254740993),
+		"exact":  json.Number("12345678901234567890"),
+		"nested": map[string]interface{}{"list": []interface{}{true, nil, "text"}},
+	}
+	if err := handler.SaveJSON(data, 2); err != nil {
+		t.Fatalf("SaveJSON() with verification error = %v", err)
+	}
+
+	// A value that can't be written faithfully fails and leaves the file alone
+	before, _ := os.ReadFile(tempFile)
+	err := handler.SaveJSON(map[string]interface{}{"bad": "caf\xe9"}, 2)
+	if !errors.Is(err, ErrVerifyFailed) {
+		t.Fatalf("SaveJSON() error = %v, want %v", err, ErrVerifyFailed)
+	}
+	if !strings.Contains(err.Error(), "'bad'") {
+		t.Errorf("SaveJSON() error = %v, want it to name the path", err)
+	}
+	after, _ := os.ReadFile(tempFile)
+	if string(after) != string(before) {
+		t.Error("SaveJSON() replaced the file despite failing verification")
+	}
+}
+
+func TestReplaceContentsVerifyWrites(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.SetVerifyWrites(true)
+
+	content := []byte("{\n  \"big\": 12345678901234567890\n}\n")
+	if err := handler.ReplaceContents(content); err != nil {
+		t.Fatalf("ReplaceContents() with verification error = %v", err)
+	}
+	if written, _ := os.ReadFile(tempFile); string(written) != string(content) {
+		t.Errorf("ReplaceContents() wrote %q, want %q", written, content)
+	}
+
+	if err := verifyContent(tempFile, []byte("{\n  \"big\": 12345678901234567000\n}\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("verifyContent() error = %v, want a mismatch on line 2", err)
+	}
+
+	// A failed check leaves the file alone
+	err := writeFileAtomic(tempFile, []byte("{}"), func(string) error { return ErrVerifyFailed })
+	if !errors.Is(err, ErrVerifyFailed) {
+		t.Fatalf("writeFileAtomic() error = %v, want %v", err, ErrVerifyFailed)
+	}
+	if written, _ := os.ReadFile(tempFile); string(written) != string(content) {
+		t.Error("writeFileAtomic() replaced the file despite failing its check")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	if err := SetAuditLog(logPath); err != nil {
+		t.Fatalf("SetAuditLog() error = %v", err)
+	}
+	defer SetAuditLog("")
+
+	testFile := createTempJSONFile(t, map[string]interface{}{
+		"db":  map[string]interface{}{"user": "admin", "password": "secret"},
+		"app": "demo",
+	})
+	defer os.Remove(testFile)
+
+	handler := NewJSONHandler(testFile)
+	handler.BeginEdit()
+	data, err := handler.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	handler.RecordAccess("db.password", AuditRead)
+
+	data["db"].(map[string]interface{})["password"] = "rotated"
+	data["app"] = "prod"
+	err = handler.SaveJSONForEdit(data, 2)
+	handler.EndEdit()
+	if err != nil {
+		t.Fatalf("SaveJSONForEdit() error = %v", err)
+	}
+
+	if err := SetAuditLog(""); err != nil {
+		t.Fatalf("SetAuditLog() error = %v", err)
+	}
+	handler.RecordAccess("app", AuditRead)
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+
+	var got []AccessRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record AccessRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Audit line %q is not JSON: %v", line, err)
+		}
+		if record.Timestamp.IsZero() {
+			t.Errorf("Audit record %q has no timestamp", line)
+		}
+		record.Timestamp = time.Time{}
+		got = append(got, record)
+	}
+
+	want := []AccessRecord{
+		{File: testFile, KeyPath: "db.password", Operation: AuditRead},
+		{File: testFile, KeyPath: "app", Operation: AuditWrite},
+		{File: testFile, KeyPath: "db.password", Operation: AuditWrite},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Audit log = %+v, want %+v", got, want)
+	}
+}
+
+func TestCaptureWrites(t *testing.T) {
+	testFile := createTempJSONFile(t, map[string]interface{}{"step": 0})
+	defer os.Remove(testFile)
+
+	original, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+
+	handler := NewJSONHandler(testFile)
+	capture := handler.CaptureWrites()
+	if err := handler.SaveJSON(map[string]interface{}{"step": 1}, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	if err := handler.ReplaceContents([]byte(`{"step": 2}`)); err != nil {
+		t.Fatalf("ReplaceContents() error = %v", err)
+	}
+	before, after, wrote := capture.Stop()
+
+	if !wrote {
+		t.Fatal("Stop() wrote = false, want true")
+	}
+	if string(before) != string(original) {
+		t.Errorf("Stop() before = %q, want %q", before, original)
+	}
+	if string(after) != `{"step": 2}` {
+		t.Errorf("Stop() after = %q, want the last write", after)
+	}
+
+	// Writes after Stop are not captured
+	if err := handler.SaveJSON(map[string]interface{}{"step": 3}, 2); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	if _, later, _ := capture.Stop(); string(later) != `{"step": 2}` {
+		t.Errorf("Stopped capture changed to %q", later)
+	}
+
+	capture = handler.CaptureWrites()
+	if _, _, wrote := capture.Stop(); wrote {
+		t.Error("Stop() wrote = true without any write")
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.LoadJSON(true)  // miss
+	handler.LoadJSON(true)  // hit
+	handler.LoadJSON(false) // uncached
+
+	// Change the file so the cache goes stale
+	time.Sleep(10 * time.Millisecond)
+	if err := os.WriteFile(tempFile, []byte(`{"key": "changed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	handler.LoadJSON(true) // reload
+	handler.LoadJSON(true) // hit
+
+	want := CacheStats{Hits: 2, Misses: 1, Reloads: 1, UncachedReads: 1}
+	if got := handler.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous map[string]interface{}
+		current  map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "no change",
+			previous: map[string]interface{}{"a": map[string]interface{}{"b": 1.0}},
+			current:  map[string]interface{}{"a": map[string]interface{}{"b": 1.0}},
+			want:     nil,
+		},
+		{
+			name:     "nested update, add and remove",
+			previous: map[string]interface{}{"a": map[string]interface{}{"b": 1.0, "c": "x"}, "old": true},
+			current:  map[string]interface{}{"a": map[string]interface{}{"b": 2.0, "c": "x"}, "new": true},
+			want:     []string{"a.b", "new", "old"},
+		},
+		{
+			name:     "array element",
+			previous: map[string]interface{}{"items": []interface{}{"a", "b"}},
+			current:  map[string]interface{}{"items": []interface{}{"a", "c"}},
+			want:     []string{"items.1"},
+		},
+		{
+			name:     "array length",
+			previous: map[string]interface{}{"items": []interface{}{"a"}},
+			current:  map[string]interface{}{"items": []interface{}{"a", "b"}},
+			want:     []string{"items"},
+		},
+		{
+			name:     "type change",
+			previous: map[string]interface{}{"a": map[string]interface{}{"b": 1.0}},
+			current:  map[string]interface{}{"a": "flat"},
+			want:     []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			changedPaths(tt.previous, tt.current, "", &got)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentChanges(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"count": 0.0})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	for i := 1; i <= MaxChangeRecords+5; i++ {
+		data, err := handler.LoadJSONForEdit()
+		if err != nil {
+			t.Fatalf("LoadJSONForEdit() error = %v", err)
+		}
+		data["count"] = float64(i)
+		if i == MaxChangeRecords+5 {
+			data["last"] = true
+		}
+		if err := handler.SaveJSONForEdit(data, 2); err != nil {
+			t.Fatalf("SaveJSONForEdit() error = %v", err)
+		}
+	}
+
+	if records := handler.RecentChanges(0); len(records) != MaxChangeRecords {
+		t.Errorf("RecentChanges(0) returned %d records, want %d", len(records), MaxChangeRecords)
+	}
+
+	records := handler.RecentChanges(2)
+	if len(records) != 2 {
+		t.Fatalf("RecentChanges(2) returned %d records", len(records))
+	}
+	if !reflect.DeepEqual(records[0].Paths, []string{"count", "last"}) || !reflect.DeepEqual(records[1].Paths, []string{"count"}) {
+		t.Errorf("RecentChanges(2) = %+v, want newest first", records)
+	}
+	if records[0].Time.Before(records[1].Time) {
+		t.Error("RecentChanges() should list newer records first")
+	}
+}
+
 // Helper function to create temporary JSON file
 func createTempJSONFile(t *testing.T, data map[string]interface{}) string {
 	tempFile, err := os.CreateTemp("", "test_*.json")
@@ -0,0 +1,214 @@
+package jsonhandler
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Common hand-editing mistakes recognized by DiagnoseSyntax
+const (
+	IssueTrailingComma = "trailing_comma"
+	IssueSingleQuoted  = "single_quoted_string"
+	IssueUnquotedKey   = "unquoted_key"
+)
+
+// SyntaxIssue is a common JSON mistake found in raw text, with a suggested fix. Offset is the
+// byte index where the mistake starts.
+type SyntaxIssue struct {
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Offset     int    `json:"offset"`
+	Text       string `json:"text"`
+	Suggestion string `json:"suggestion"`
+
+	// The fix replaces data[Offset:end] with replacement
+	end         int
+	replacement string
+}
+
+// lenientScanner walks text that may not be valid JSON, tracking just enough structure to
+// recognize trailing commas, single-quoted strings and bareword object keys
+type lenientScanner struct {
+	data   []byte
+	pos    int
+	stack  []byte
+	issues []SyntaxIssue
+}
+
+// DiagnoseSyntax reports, in file order, the trailing commas, single-quoted strings and
+// unquoted object keys in data. It only looks for these mistakes: other syntax errors are
+// skipped over rather than reported, so valid-looking output doesn't mean valid JSON.
+func DiagnoseSyntax(data []byte) []SyntaxIssue {
+	s := &lenientScanner{data: data}
+	for s.pos < len(s.data) {
+		switch c := s.data[s.pos]; {
+		case c == '{' || c == '[':
+			s.stack = append(s.stack, c)
+			s.pos++
+		case c == '}' || c == ']':
+			if len(s.stack) > 0 {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+			s.pos++
+		case c == ',':
+			s.comma()
+		case c == '"':
+			s.skipString()
+		case c == '\'':
+			s.singleQuoted()
+		case isWordStart(c):
+			s.word()
+		default:
+			s.pos++
+		}
+	}
+	return s.issues
+}
+
+// add records an issue whose fix replaces data[start:end] with replacement
+func (s *lenientScanner) add(kind, message, suggestion string, start, end int, replacement string) {
+	line, col := getLineColumn(s.data, int64(start))
+	s.issues = append(s.issues, SyntaxIssue{
+		Kind:        kind,
+		Message:     message,
+		Line:        line,
+		Column:      col,
+		Offset:      start,
+		Text:        string(s.data[start:end]),
+		Suggestion:  suggestion,
+		end:         end,
+		replacement: replacement,
+	})
+}
+
+// nextNonSpace returns the index of the first non-whitespace byte at or after i
+func (s *lenientScanner) nextNonSpace(i int) int {
+	for i < len(s.data) {
+		switch s.data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func (s *lenientScanner) comma() {
+	start := s.pos
+	s.pos++
+	next := s.nextNonSpace(s.pos)
+	if next < len(s.data) && (s.data[next] == '}' || s.data[next] == ']') {
+		s.add(IssueTrailingComma,
+			fmt.Sprintf("trailing comma before '%c'", s.data[next]),
+			"Remove the comma",
+			start, start+1, "")
+	}
+}
+
+// skipString skips a double-quoted string, stopping at a raw newline
+func (s *lenientScanner) skipString() {
+	s.pos++
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			s.pos++
+			return
+		case '\\':
+			s.pos++
+		case '\n':
+			return
+		}
+		s.pos++
+	}
+}
+
+// singleQuoted recognizes a string in single quotes on one line and suggests the same
+// string in double quotes. A quote that isn't closed on its line is skipped.
+func (s *lenientScanner) singleQuoted() {
+	start := s.pos
+	var converted bytes.Buffer
+	converted.WriteByte('"')
+	for i := start + 1; i < len(s.data); i++ {
+		switch c := s.data[i]; c {
+		case '\'':
+			converted.WriteByte('"')
+			s.pos = i + 1
+			replacement := converted.String()
+			s.add(IssueSingleQuoted,
+				"string in single quotes",
+				"Use double quotes: "+replacement,
+				start, s.pos, replacement)
+			return
+		case '\\':
+			if i+1 < len(s.data) && s.data[i+1] == '\'' {
+				// \' needs no escaping once the string is double-quoted
+				converted.WriteByte('\'')
+				i++
+				continue
+			}
+			converted.WriteByte(c)
+			if i+1 < len(s.data) && s.data[i+1] != '\n' {
+				converted.WriteByte(s.data[i+1])
+				i++
+			}
+		case '"':
+			converted.WriteString(`\"`)
+		case '\n':
+			s.pos++
+			return
+		default:
+			converted.WriteByte(c)
+		}
+	}
+	s.pos++
+}
+
+// word recognizes a bareword followed by ':' inside an object as an unquoted key; any other
+// word, such as true or a stray identifier, is skipped
+func (s *lenientScanner) word() {
+	start := s.pos
+	for s.pos < len(s.data) && isWordChar(s.data[s.pos]) {
+		s.pos++
+	}
+
+	inObject := len(s.stack) > 0 && s.stack[len(s.stack)-1] == '{'
+	next := s.nextNonSpace(s.pos)
+	if inObject && next < len(s.data) && s.data[next] == ':' {
+		key := string(s.data[start:s.pos])
+		s.add(IssueUnquotedKey,
+			fmt.Sprintf("object key %s is not quoted", key),
+			fmt.Sprintf("Quote the key: \"%s\"", key),
+			start, s.pos, `"`+key+`"`)
+	}
+}
+
+func isWordStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == '$'
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9')
+}
+
+// RepairSyntax applies the suggested fix of every issue DiagnoseSyntax finds in data and
+// returns the repaired text along with the issues fixed. Everything else, including
+// formatting and any other errors, is left as it was.
+func RepairSyntax(data []byte) ([]byte, []SyntaxIssue) {
+	issues := DiagnoseSyntax(data)
+	if len(issues) == 0 {
+		return data, issues
+	}
+
+	var repaired bytes.Buffer
+	last := 0
+	for _, issue := range issues {
+		repaired.Write(data[last:issue.Offset])
+		repaired.WriteString(issue.replacement)
+		last = issue.end
+	}
+	repaired.Write(data[last:])
+	return repaired.Bytes(), issues
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package jsonhandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OrderedObject is a JSON object that remembers the order of its keys as written in the
+// source text. The regular load path decodes objects into maps, which lose that order.
+type OrderedObject struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// DecodeOrdered decodes JSON content keeping key order: objects become *OrderedObject,
+// arrays []interface{} and numbers json.Number, so values round-trip with their original text.
+// A key that appears twice keeps its first position and its last value, as in encoding/json.
+func DecodeOrdered(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	value, err := decodeOrderedValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return value, nil
+}
+
+// decodeOrderedValue reads one complete value from decoder
+func decodeOrderedValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &OrderedObject{Values: make(map[string]interface{})}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := object.Values[key]; !exists {
+				object.Keys = append(object.Keys, key)
+			}
+			object.Values[key] = value
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+	return token, nil
+}
+
+// EncodeOrdered encodes a value produced by DecodeOrdered in the same layout SaveJSON uses,
+// indenting by indent spaces per level, but with object keys in their recorded order
+func EncodeOrdered(value interface{}, indent int, escapeHTML bool) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := encodeOrderedValue(&buffer, value, getIndentString(indent), "", escapeHTML); err != nil {
+		return nil, fmt.Errorf("%w: Failed to encode JSON: %v", ErrFileWriteError, err)
+	}
+	buffer.WriteByte('\n')
+	return buffer.Bytes(), nil
+}
+
+// encodeOrderedValue writes value at the nesting given by prefix
+func encodeOrderedValue(buffer *bytes.Buffer, value interface{}, indent, prefix string, escapeHTML bool) error {
+	// Without an indent everything goes on one line, like json.Encoder
+	newline := func(level string) {
+		if indent != "" {
+			buffer.WriteByte('\n')
+			buffer.WriteString(level)
+		}
+	}
+	inner := prefix + indent
+
+	switch v := value.(type) {
+	case *OrderedObject:
+		if len(v.Keys) == 0 {
+			buffer.WriteString("{}")
+			return nil
+		}
+		buffer.WriteByte('{')
+		for i, key := range v.Keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			newline(inner)
+			if err := encodeScalar(buffer, key, escapeHTML); err != nil {
+				return err
+			}
+			buffer.WriteByte(':')
+			if indent != "" {
+				buffer.WriteByte(' ')
+			}
+			if err := encodeOrderedValue(buffer, v.Values[key], indent, inner, escapeHTML); err != nil {
+				return err
+			}
+		}
+		newline(prefix)
+		buffer.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			buffer.WriteString("[]")
+			return nil
+		}
+		buffer.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			newline(inner)
+			if err := encodeOrderedValue(buffer, element, indent, inner, escapeHTML); err != nil {
+				return err
+			}
+		}
+		newline(prefix)
+		buffer.WriteByte(']')
+	default:
+		return encodeScalar(buffer, v, escapeHTML)
+	}
+	return nil
+}
+
+// encodeScalar writes a string, number, boolean or null in compact form
+func encodeScalar(buffer *bytes.Buffer, value interface{}, escapeHTML bool) error {
+	var scalar strings.Builder
+	encoder := json.NewEncoder(&scalar)
+	encoder.SetEscapeHTML(escapeHTML)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buffer.WriteString(strings.TrimSuffix(scalar.String(), "\n"))
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package jsonhandler
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxRegistryEntries is how many handlers the shared registry keeps alive before it drops idle ones
+const MaxRegistryEntries = 64
+
+// registryEntry pairs a shared handler with its last access tick for eviction
+type registryEntry struct {
+	handler  *JSONHandler
+	lastUsed uint64
+}
+
+// fileOptions holds the save options chosen for a file
+type fileOptions struct {
+	escapeHTML atomic.Bool
+	verify     atomic.Bool
+}
+
+// handlerRegistry shares one handler per absolute file path across calls. Save options are
+// kept per path separately, so a file keeps them when its handler is evicted.
+type handlerRegistry struct {
+	mutex   sync.Mutex
+	entries map[string]*registryEntry
+	options map[string]*fileOptions
+	tick    uint64
+}
+
+var registry = &handlerRegistry{
+	entries: make(map[string]*registryEntry),
+	options: make(map[string]*fileOptions),
+}
+
+// GetHandler returns the process-wide handler for a file, creating it on first use.
+// Repeated calls for the same file share its cache and locks.
+func GetHandler(filePath string) *JSONHandler {
+	key := registryKey(filePath)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.tick++
+	if entry, exists := registry.entries[key]; exists {
+		entry.lastUsed = registry.tick
+		return entry.handler
+	}
+
+	if len(registry.entries) >= MaxRegistryEntries {
+		registry.makeRoom()
+	}
+
+	handler := NewJSONHandler(filePath)
+	if options, exists := registry.options[key]; exists {
+		handler.options = options
+	} else {
+		registry.options[key] = handler.options
+	}
+	registry.entries[key] = &registryEntry{handler: handler, lastUsed: registry.tick}
+	return handler
+}
+
+// makeRoom drops the least recently used handler that is idle and holds no history. Handlers
+// that are being edited or hold history are kept, since replacing them would split the file's
+// edit lock or lose its undo steps; idle ones passed over release their cached document
+// instead. The registry grows past MaxRegistryEntries when no handler can be dropped.
+// The caller must hold r.mutex.
+func (r *handlerRegistry) makeRoom() {
+	keys := make([]string, 0, len(r.entries))
+	for key := range r.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return r.entries[keys[i]].lastUsed < r.entries[keys[j]].lastUsed
+	})
+
+	for _, key := range keys {
+		handler := r.entries[key].handler
+		if handler.editing() {
+			continue
+		}
+		if !handler.hasHistory() {
+			delete(r.entries, key)
+			return
+		}
+		handler.ClearCache()
+	}
+}
+
+// EvictHandler drops the shared handler for a file, releasing its cached data
+func EvictHandler(filePath string) {
+	key := registryKey(filePath)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	delete(registry.entries, key)
+}
+
+// EvictAllHandlers drops every shared handler
+func EvictAllHandlers() {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.entries = make(map[string]*registryEntry)
+}
+
+// RegistrySize returns the number of handlers currently held by the registry
+func RegistrySize() int {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	return len(registry.entries)
+}
+
+// registryKey normalizes a file path so different spellings share one handler
+func registryKey(filePath string) string {
+	if absPath, err := filepath.Abs(filePath); err == nil {
+		return absPath
+	}
+	return filepath.Clean(filePath)
+}
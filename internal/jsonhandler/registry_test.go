@@ -0,0 +1,156 @@
+package jsonhandler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetHandlerSharesInstance(t *testing.T) {
+	EvictAllHandlers()
+	defer EvictAllHandlers()
+
+	tempFile := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(tempFile)
+
+	first := GetHandler(tempFile)
+	second := GetHandler(filepath.Join(filepath.Dir(tempFile), ".", filepath.Base(tempFile)))
+	if first != second {
+		t.Error("GetHandler() should return the same handler for equivalent paths")
+	}
+
+	// Loading through one reference should warm the shared cache
+	if _, err := first.LoadJSON(true); err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if !second.GetFileInfo().IsCached {
+		t.Error("Shared handler should report cached data")
+	}
+
+	EvictHandler(tempFile)
+	if GetHandler(tempFile) == first {
+		t.Error("GetHandler() should create a new handler after eviction")
+	}
+}
+
+func TestGetHandlerBoundsRegistry(t *testing.T) {
+	EvictAllHandlers()
+	defer EvictAllHandlers()
+
+	dir := t.TempDir()
+	first := GetHandler(filepath.Join(dir, "0.json"))
+	for i := 1; i <= MaxRegistryEntries; i++ {
+		GetHandler(filepath.Join(dir, fmt.Sprintf("%d.json", i)))
+	}
+
+	if size := RegistrySize(); size != MaxRegistryEntries {
+		t.Errorf("RegistrySize() = %d, want %d", size, MaxRegistryEntries)
+	}
+	if GetHandler(filepath.Join(dir, "0.json")) == first {
+		t.Error("Least recently used handler should have been evicted")
+	}
+}
+
+func TestGetHandlerKeepsBusyHandlers(t *testing.T) {
+	EvictAllHandlers()
+	defer EvictAllHandlers()
+
+	dir := t.TempDir()
+	editedPath := filepath.Join(dir, "edited.json")
+	historyPath := createTempJSONFile(t, map[string]interface{}{"key": "value"})
+	defer os.Remove(historyPath)
+
+	// One handler is mid-edit, another holds undo history
+	edited := GetHandler(editedPath)
+	edited.BeginEdit()
+	withHistory := GetHandler(historyPath)
+	withHistory.BeginEdit()
+	data, err := withHistory.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	data["key"] = "changed"
+	if err := withHistory.SaveJSONForEdit(data, 2); err != nil {
+		t.Fatalf("SaveJSONForEdit() error = %v", err)
+	}
+	withHistory.EndEdit()
+
+	for i := 0; i < MaxRegistryEntries; i++ {
+		GetHandler(filepath.Join(dir, fmt.Sprintf("%d.json", i)))
+	}
+
+	if GetHandler(editedPath) != edited {
+		t.Error("A handler being edited should not be evicted")
+	}
+	if GetHandler(historyPath) != withHistory {
+		t.Error("A handler holding undo history should not be evicted")
+	}
+	if undo, _ := withHistory.HistoryLen(); undo != 1 {
+		t.Errorf("HistoryLen() undo = %d, want 1", undo)
+	}
+	if size := RegistrySize(); size != MaxRegistryEntries {
+		t.Errorf("RegistrySize() = %d, want %d", size, MaxRegistryEntries)
+	}
+
+	// Once the edit ends the handler is an ordinary eviction candidate again
+	edited.EndEdit()
+	for i := MaxRegistryEntries; i < 2*MaxRegistryEntries; i++ {
+		GetHandler(filepath.Join(dir, fmt.Sprintf("%d.json", i)))
+	}
+	if GetHandler(editedPath) == edited {
+		t.Error("An idle handler without history should be evicted")
+	}
+}
+
+func TestGetHandlerKeepsOptionsAcrossEviction(t *testing.T) {
+	EvictAllHandlers()
+	defer EvictAllHandlers()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "options.json")
+	first := GetHandler(path)
+	first.SetEscapeHTML(true)
+	first.SetVerifyWrites(true)
+
+	for i := 0; i < MaxRegistryEntries; i++ {
+		GetHandler(filepath.Join(dir, fmt.Sprintf("%d.json", i)))
+	}
+
+	handler := GetHandler(path)
+	if handler == first {
+		t.Fatal("Idle handler should have been evicted")
+	}
+	if !handler.EscapeHTML() {
+		t.Error("EscapeHTML() after eviction = false, want the file's setting kept")
+	}
+	if !handler.options.verify.Load() {
+		t.Error("Write verification was dropped along with the evicted handler")
+	}
+	if NewJSONHandler(path).EscapeHTML() {
+		t.Error("A handler outside the registry should start with default options")
+	}
+}
+
+func TestLoadJSONForEditReturnsCopy(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"nested": map[string]interface{}{"key": "value"},
+	})
+	defer os.Remove(tempFile)
+
+	handler := NewJSONHandler(tempFile)
+	cached, err := handler.LoadJSON(true)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+
+	editable, err := handler.LoadJSONForEdit()
+	if err != nil {
+		t.Fatalf("LoadJSONForEdit() error = %v", err)
+	}
+	editable["nested"].(map[string]interface{})["key"] = "changed"
+
+	if cached["nested"].(map[string]interface{})["key"] != "value" {
+		t.Error("Modifying editable data should not affect cached data")
+	}
+}
@@ -0,0 +1,183 @@
+package jsonhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// StreamProgressInterval is how many bytes ValidateJSONStream reads between progress reports
+const StreamProgressInterval = 4 << 20
+
+// ProgressFunc receives the number of bytes of a file processed so far and the file's size
+type ProgressFunc func(processed, total int64)
+
+// progressReader counts the bytes read through it and reports them every
+// StreamProgressInterval bytes
+type progressReader struct {
+	src        io.Reader
+	total      int64
+	processed  int64
+	reportedAt int64
+	progress   ProgressFunc
+}
+
+// Read implements io.Reader
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.src.Read(p)
+	r.processed += int64(n)
+	if r.progress != nil && r.processed-r.reportedAt >= StreamProgressInterval {
+		r.reportedAt = r.processed
+		r.progress(r.processed, r.total)
+	}
+	return n, err
+}
+
+// ValidateJSONStream validates the file's syntax by scanning its tokens as they are read,
+// without building the document, so memory use stays flat however large the file is.
+// The file size limit does not apply. progress, if not nil, is called every
+// StreamProgressInterval bytes and once at the end. The first error is located by
+// line and column in a second pass over the file; its message comes from json.Decoder
+// and may word some errors differently from ValidateJSONSyntax.
+func (h *JSONHandler) ValidateJSONStream(progress ProgressFunc) *ValidationResult {
+	result := &ValidationResult{
+		File: h.filePath,
+	}
+	fail := func(errorType, message string, line, column int) *ValidationResult {
+		result.Valid = false
+		result.ErrorType = errorType
+		result.Error = &ValidationError{Message: message, Line: line, Column: column}
+		return result
+	}
+
+	fileInfo, err := os.Stat(h.filePath)
+	if os.IsNotExist(err) {
+		return fail("FILE_NOT_FOUND", fmt.Sprintf("File %s not found", h.filePath), 0, 0)
+	}
+	if err != nil {
+		return fail("FILE_READ_ERROR", fmt.Sprintf("Failed to stat %s: %v", h.filePath, err), 0, 0)
+	}
+	if err := checkRegularFile(h.filePath, fileInfo); err != nil {
+		return fail("NOT_A_REGULAR_FILE", err.Error(), 0, 0)
+	}
+	if fileInfo.Size() == 0 {
+		return fail("PARSE_ERROR", "File is empty", 1, 1)
+	}
+
+	file, err := os.Open(h.filePath)
+	if err != nil {
+		return fail("FILE_READ_ERROR", fmt.Sprintf("Failed to read file: %v", err), 0, 0)
+	}
+	defer file.Close()
+
+	startTime := time.Now()
+	counter := &progressReader{src: file, total: fileInfo.Size(), progress: progress}
+	offset, syntaxMessage, err := scanTokens(NewUTF8Reader(counter))
+	if progress != nil {
+		progress(counter.processed, counter.total)
+	}
+
+	if errors.Is(err, ErrParseError) {
+		return fail("PARSE_ERROR", fmt.Sprintf("Failed to decode file: %v", err), 0, 0)
+	}
+	if err != nil {
+		return fail("FILE_READ_ERROR", fmt.Sprintf("Failed to read file: %v", err), 0, 0)
+	}
+	if syntaxMessage != "" {
+		line, column, err := h.streamLineColumn(offset)
+		if err != nil {
+			return fail("FILE_READ_ERROR", fmt.Sprintf("Failed to read file: %v", err), 0, 0)
+		}
+		return fail("PARSE_ERROR", syntaxMessage, line, column)
+	}
+
+	result.Valid = true
+	result.Performance = &PerformanceMetrics{
+		ParseTime: time.Since(startTime).Seconds(),
+		FileSize:  fileInfo.Size(),
+	}
+	return result
+}
+
+// scanTokens reads one complete JSON value from r and checks that nothing but whitespace
+// follows it. A syntax error is returned as a message along with the offset of the
+// offending byte in r's content, or -1 if the content ended too early. An error from
+// reading r is returned as is.
+func scanTokens(r io.Reader) (int64, string, error) {
+	decoder := json.NewDecoder(r)
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		var syntaxErr *json.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return syntaxErr.Offset, syntaxErr.Error(), nil
+		case err == io.ErrUnexpectedEOF || err == io.EOF:
+			return -1, "unexpected end of JSON input", nil
+		case err != nil:
+			return 0, "", err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+
+	// Anything after the top-level value other than whitespace is an error
+	before := decoder.InputOffset()
+	_, err := decoder.Token()
+	var syntaxErr *json.SyntaxError
+	switch {
+	case err == io.EOF:
+		return 0, "", nil
+	case err == nil:
+		return before, "invalid character after top-level value", nil
+	case errors.As(err, &syntaxErr):
+		return syntaxErr.Offset, syntaxErr.Error(), nil
+	}
+	return 0, "", err
+}
+
+// streamLineColumn reads the file again up to offset in its decoded content, or to the end
+// if offset is negative, and returns the line and column there, counted as getLineColumn does
+func (h *JSONHandler) streamLineColumn(offset int64) (int, int, error) {
+	file, err := os.Open(h.filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	line, column := 1, 1
+	buf := make([]byte, 64<<10)
+	reader := NewUTF8Reader(file)
+	if offset >= 0 {
+		reader = io.LimitReader(reader, offset)
+	}
+	for {
+		n, err := reader.Read(buf)
+		for _, b := range buf[:n] {
+			if b == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+		if err == io.EOF {
+			return line, column, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+}
\ No newline at end of file
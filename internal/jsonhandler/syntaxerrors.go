@@ -0,0 +1,431 @@
+package jsonhandler
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MaxSyntaxErrors bounds how many errors a multi-error validation reports
+const MaxSyntaxErrors = 50
+
+// maxNestingDepth matches the nesting limit of encoding/json
+const maxNestingDepth = 10000
+
+// syntaxScanner is a best-effort JSON checker that keeps going after an error by
+// skipping to the next separator or closing bracket at the current nesting level.
+// Messages and offsets follow encoding/json, so the first error is reported where
+// json.Unmarshal reports it.
+type syntaxScanner struct {
+	data      []byte
+	pos       int
+	errors    []ValidationError
+	maxErrors int
+	stopped   bool
+}
+
+// FindSyntaxErrors reports up to maxErrors syntax errors in data, in file order.
+// Errors after the first are best-effort and may include follow-on errors.
+func FindSyntaxErrors(data []byte, maxErrors int) []ValidationError {
+	s := &syntaxScanner{data: data, maxErrors: maxErrors}
+
+	s.value(0)
+	s.skipSpace()
+	if !s.stopped && s.pos < len(s.data) {
+		s.fail(s.pos, "after top-level value")
+	}
+
+	return s.errors
+}
+
+// fail records an error about the byte at index i, or end of input when i is past the data
+func (s *syntaxScanner) fail(i int, context string) {
+	if s.stopped {
+		return
+	}
+
+	if i >= len(s.data) {
+		s.record(len(s.data), "unexpected end of JSON input")
+		s.stopped = true
+		return
+	}
+	s.record(i+1, fmt.Sprintf("invalid character %s %s", quoteChar(s.data[i]), context))
+}
+
+// record adds an error at offset, counted like json.SyntaxError.Offset
+func (s *syntaxScanner) record(offset int, message string) {
+	if s.stopped {
+		return
+	}
+
+	line, col := getLineColumn(s.data, int64(offset))
+	s.errors = append(s.errors, ValidationError{
+		Message: message,
+		Line:    line,
+		Column:  col,
+	})
+	if len(s.errors) >= s.maxErrors {
+		s.stopped = true
+	}
+}
+
+// peek returns the next byte, or 0 at end of input
+func (s *syntaxScanner) peek() byte {
+	if s.pos >= len(s.data) {
+		return 0
+	}
+	return s.data[s.pos]
+}
+
+func (s *syntaxScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// skipWord skips an unrecognized run of characters up to the next delimiter
+func (s *syntaxScanner) skipWord() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r', ',', ':', '{', '}', '[', ']', '"':
+			return
+		}
+		s.pos++
+	}
+}
+
+// skipToSync skips to the next ',' or closing bracket at the current nesting level
+func (s *syntaxScanner) skipToSync() {
+	depth := 0
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			s.skipString()
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return
+			}
+		}
+		s.pos++
+	}
+}
+
+// skipString skips a string without reporting errors, stopping at a raw newline
+func (s *syntaxScanner) skipString() {
+	s.pos++
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			s.pos++
+			return
+		case '\\':
+			s.pos++
+		case '\n':
+			return
+		}
+		s.pos++
+	}
+}
+
+// resync recovers inside a container after an error. It reports whether the
+// container continues with another element.
+func (s *syntaxScanner) resync(closer byte) bool {
+	s.skipToSync()
+	switch s.peek() {
+	case ',':
+		s.pos++
+		return true
+	case closer:
+		s.pos++
+	case 0:
+		s.fail(s.pos, "")
+	}
+	return false
+}
+
+func isValueStart(c byte) bool {
+	switch c {
+	case '{', '[', '"', '-', 't', 'f', 'n':
+		return true
+	}
+	return c >= '0' && c <= '9'
+}
+
+func (s *syntaxScanner) value(depth int) {
+	s.skipSpace()
+	if s.stopped {
+		return
+	}
+
+	switch c := s.peek(); {
+	case c == '{' || c == '[':
+		if depth >= maxNestingDepth {
+			s.errors = append(s.errors, ValidationError{Message: "exceeded max depth"})
+			s.stopped = true
+			return
+		}
+		if c == '{' {
+			s.object(depth + 1)
+		} else {
+			s.array(depth + 1)
+		}
+	case c == '"':
+		s.str()
+	case c == '-' || (c >= '0' && c <= '9'):
+		s.number()
+	case c == 't':
+		s.literal("true")
+	case c == 'f':
+		s.literal("false")
+	case c == 'n':
+		s.literal("null")
+	default:
+		s.fail(s.pos, "looking for beginning of value")
+		// Leave separators and closers for the enclosing container
+		switch c {
+		case ',', ':', '}', ']':
+			return
+		}
+		s.pos++
+		s.skipWord()
+	}
+}
+
+func (s *syntaxScanner) object(depth int) {
+	s.pos++
+	s.skipSpace()
+	if s.peek() == '}' {
+		s.pos++
+		return
+	}
+
+	for !s.stopped {
+		s.skipSpace()
+		if s.peek() != '"' {
+			s.fail(s.pos, "looking for beginning of object key string")
+			if s.peek() == '}' {
+				// Trailing comma
+				s.pos++
+				return
+			}
+			if !s.resync('}') {
+				return
+			}
+			continue
+		}
+		s.str()
+
+		s.skipSpace()
+		if s.peek() == ':' {
+			s.pos++
+		} else {
+			s.fail(s.pos, "after object key")
+			if !isValueStart(s.peek()) {
+				if !s.resync('}') {
+					return
+				}
+				continue
+			}
+		}
+
+		s.value(depth)
+
+		s.skipSpace()
+		switch c := s.peek(); {
+		case c == ',':
+			s.pos++
+		case c == '}':
+			s.pos++
+			return
+		case c == '"':
+			// Missing comma; carry on with the next member
+			s.fail(s.pos, "after object key:value pair")
+		default:
+			s.fail(s.pos, "after object key:value pair")
+			if !s.resync('}') {
+				return
+			}
+		}
+	}
+}
+
+func (s *syntaxScanner) array(depth int) {
+	s.pos++
+	s.skipSpace()
+	if s.peek() == ']' {
+		s.pos++
+		return
+	}
+
+	for !s.stopped {
+		s.skipSpace()
+		if s.peek() == ']' {
+			// Trailing comma
+			s.fail(s.pos, "looking for beginning of value")
+			s.pos++
+			return
+		}
+
+		s.value(depth)
+
+		s.skipSpace()
+		switch c := s.peek(); {
+		case c == ',':
+			s.pos++
+		case c == ']':
+			s.pos++
+			return
+		case isValueStart(c):
+			// Missing comma; carry on with the next element
+			s.fail(s.pos, "after array element")
+		default:
+			s.fail(s.pos, "after array element")
+			if !s.resync(']') {
+				return
+			}
+		}
+	}
+}
+
+func (s *syntaxScanner) str() {
+	s.pos++
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		switch {
+		case c == '"':
+			s.pos++
+			return
+		case c == '\\':
+			s.pos++
+			s.escape()
+			continue
+		case c == '\n':
+			// Treat a raw newline as the end of an unterminated string
+			s.fail(s.pos, "in string")
+			return
+		case c < 0x20:
+			s.fail(s.pos, "in string")
+		}
+		s.pos++
+	}
+	s.fail(s.pos, "")
+}
+
+// escape checks the escape sequence after a backslash
+func (s *syntaxScanner) escape() {
+	start := s.pos - 1
+	switch s.peek() {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		s.pos++
+		return
+	case 'u':
+		s.pos++
+		valid := true
+		for i := 0; i < 4 && s.pos < len(s.data); i++ {
+			if s.data[s.pos] == '"' {
+				break
+			}
+			valid = valid && isHexDigit(s.data[s.pos])
+			s.pos++
+		}
+		if valid && s.pos-start == 6 {
+			return
+		}
+	case 0:
+		s.fail(s.pos, "")
+		return
+	default:
+		s.pos++
+	}
+
+	if s.pos >= len(s.data) {
+		s.fail(s.pos, "")
+		return
+	}
+	s.record(s.pos, fmt.Sprintf("invalid escape sequence `%s` in string", s.data[start:s.pos]))
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func (s *syntaxScanner) number() {
+	if s.peek() == '-' {
+		s.pos++
+	}
+
+	switch c := s.peek(); {
+	case c == '0':
+		s.pos++
+	case c >= '1' && c <= '9':
+		s.digits()
+	default:
+		s.fail(s.pos, "in numeric literal")
+		s.skipWord()
+		return
+	}
+
+	if s.peek() == '.' {
+		s.pos++
+		if c := s.peek(); c < '0' || c > '9' {
+			s.fail(s.pos, "in numeric literal")
+			s.skipWord()
+			return
+		}
+		s.digits()
+	}
+
+	if c := s.peek(); c == 'e' || c == 'E' {
+		s.pos++
+		if c := s.peek(); c == '+' || c == '-' {
+			s.pos++
+		}
+		if c := s.peek(); c < '0' || c > '9' {
+			s.fail(s.pos, "in numeric literal")
+			s.skipWord()
+			return
+		}
+		s.digits()
+	}
+}
+
+func (s *syntaxScanner) digits() {
+	for c := s.peek(); c >= '0' && c <= '9'; c = s.peek() {
+		s.pos++
+	}
+}
+
+func (s *syntaxScanner) literal(word string) {
+	for i := 0; i < len(word); i++ {
+		if s.peek() != word[i] {
+			s.fail(s.pos, fmt.Sprintf("in literal %s (expecting %s)", word, quoteChar(word[i])))
+			s.skipWord()
+			return
+		}
+		s.pos++
+	}
+}
+
+// quoteChar formats c the way encoding/json does in syntax errors
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	q := strconv.Quote(string(rune(c)))
+	return "'" + q[1:len(q)-1] + "'"
+}
\ No newline at end of file
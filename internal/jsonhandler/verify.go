@@ -0,0 +1,128 @@
+package jsonhandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+)
+
+// verifyWritten reads the file at path back and checks that it holds exactly data. Numbers are
+// compared by value, so a number that lost precision when written is caught.
+func verifyWritten(path string, data map[string]interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read back %s: %v", path, err)
+	}
+	content, err = DecodeToUTF8(content)
+	if err != nil {
+		return fmt.Errorf("written content could not be decoded: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var written interface{}
+	if err := decoder.Decode(&written); err != nil {
+		return fmt.Errorf("written content is not valid JSON: %v", err)
+	}
+
+	if path, differs := firstDifference(data, written, ""); differs {
+		if path == "" {
+			path = "root"
+		}
+		return fmt.Errorf("value at '%s' reads back differently", path)
+	}
+	return nil
+}
+
+// verifyContent reads the file at path back and checks that it holds exactly content
+func verifyContent(path string, content []byte) error {
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read back %s: %v", path, err)
+	}
+	if bytes.Equal(written, content) {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(written) && offset < len(content) && written[offset] == content[offset] {
+		offset++
+	}
+	line, column := getLineColumn(content, int64(offset))
+	return fmt.Errorf("content reads back differently from line %d, column %d", line, column)
+}
+
+// firstDifference returns the path of the first value in intended that written, decoded with
+// UseNumber, doesn't reproduce
+func firstDifference(intended, written interface{}, path string) (string, bool) {
+	switch v := intended.(type) {
+	case nil:
+		return path, written != nil
+	case bool, string:
+		return path, v != written
+	case json.Number:
+		n, ok := written.(json.Number)
+		return path, !ok || !sameNumber(string(v), string(n))
+	case map[string]interface{}:
+		w, ok := written.(map[string]interface{})
+		if !ok || len(w) != len(v) {
+			return path, true
+		}
+		for key, child := range v {
+			if _, exists := w[key]; !exists {
+				return joinPath(path, key), true
+			}
+			if childPath, differs := firstDifference(child, w[key], joinPath(path, key)); differs {
+				return childPath, true
+			}
+		}
+		return "", false
+	case []interface{}:
+		w, ok := written.([]interface{})
+		if !ok || len(w) != len(v) {
+			return path, true
+		}
+		for i, child := range v {
+			if childPath, differs := firstDifference(child, w[i], joinPath(path, strconv.Itoa(i))); differs {
+				return childPath, true
+			}
+		}
+		return "", false
+	}
+
+	n, isNumber := written.(json.Number)
+	value := reflect.ValueOf(intended)
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(n), value.Type().Bits())
+		return path, !isNumber || err != nil || f != value.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return path, !isNumber || string(n) != strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return path, !isNumber || string(n) != strconv.FormatUint(value.Uint(), 10)
+	}
+
+	// Any other Go value is checked against its own JSON encoding
+	encoded, err := json.Marshal(intended)
+	if err != nil {
+		return path, true
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var normalized interface{}
+	if err := decoder.Decode(&normalized); err != nil {
+		return path, true
+	}
+	return firstDifference(normalized, written, path)
+}
+
+// sameNumber reports whether two JSON number literals have exactly the same value
+func sameNumber(a, b string) bool {
+	x, okX := new(big.Rat).SetString(a)
+	y, okY := new(big.Rat).SetString(b)
+	return okX && okY && x.Cmp(y) == 0
+}
\ No newline at end of file
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"jsonmcptool/internal/jsonhandler"
 	"jsonmcptool/internal/operations"
 )
 
@@ -26,10 +29,190 @@ func NewJSONMcpServer() *server.MCPServer {
 	addListKeysTool(s)
 	addKeyExistsTool(s)
 	addValidateJSONTool(s)
+	addClearKeyTool(s)
+	addGlobGetTool(s)
+	addRemoveMatchingTool(s)
+	addCountValueTool(s)
+	addGeneratePatchTool(s)
+	addApplyTransactionTool(s)
+	addLocateKeyTool(s)
+	addLargestSubtreesTool(s)
+	addRenderTreeTool(s)
+	addInsertArrayElementTool(s)
+	addUpdateArrayElementTool(s)
+	addFindInArrayTool(s)
+	addPathsEqualTool(s)
+	addSnapshotFileTool(s)
+	addRestoreFileTool(s)
+	addListSnapshotsTool(s)
+	addUndoTool(s)
+	addRedoTool(s)
+	addDetectEncodingTool(s)
+	addWrapKeyTool(s)
+	addPromoteChildrenTool(s)
+	addShowSubtreeTool(s)
+	addCompareAndSetTool(s)
+	addGetKeyExpandedTool(s)
+	addListPlaceholdersTool(s)
+	addWithoutKeyTool(s)
+	addRedactJSONTool(s)
+	addPickKeysTool(s)
+	addOmitKeysTool(s)
+	addRequireKeysTool(s)
+	addAssertValuesMatchTool(s)
+	addCacheStatsTool(s)
+	addClearCacheTool(s)
+	addNormalizeNumbersTool(s)
+	addTransformStringsTool(s)
+	addCheckKeyNamingTool(s)
+	addNormalizeKeyNamesTool(s)
+	addGetKeyDepthTool(s)
+	addSplitByTopKeyTool(s)
+	addCombineFilesTool(s)
+	addRecentChangesTool(s)
+	addValidateShapeTool(s)
+	addResolveRefsTool(s)
+	addListByTypeTool(s)
+	addPruneEmptyTool(s)
+	addSectionHashesTool(s)
+	addImportNDJSONTool(s)
+	addExportNDJSONTool(s)
+	addEffectiveValueTool(s)
+	addMissingKeysTool(s)
+	addUnusedKeysTool(s)
+	addPreviewSaveTool(s)
+	addApplyTemplateTool(s)
+	addPathsOfValueTool(s)
+	addReorderToMatchTool(s)
+	addSubtreeSpanTool(s)
+	addNestFlatKeysTool(s)
+	addGetAncestorsTool(s)
+	addAssertNumericRangeTool(s)
+	addListKeysWithValuesTool(s)
+	addCreateFileTool(s)
+	addFilesEqualTool(s)
+	addTypeDiffTool(s)
+	addGenerateTSInterfaceTool(s)
+	addDeepestPathTool(s)
+	addSetMatchingTool(s)
+	addCompletenessTool(s)
+	addGraftSubtreeTool(s)
+	addCheckStringHealthTool(s)
+	addAssertArrayUniqueTool(s)
+	addProbeKeyTool(s)
+	addFillMissingTool(s)
+	addRenameKeyEverywhereTool(s)
+	addLongestStringsTool(s)
+	addSetWhereTool(s)
+	addIsUsableTool(s)
+	addBatchEditTool(s)
+	addDiagnoseJSONTool(s)
+	addRepairJSONTool(s)
+	addGroupPathsByTypeTool(s)
+	addCheckPluralFormsTool(s)
+	addKeyMatrixTool(s)
+	addMapValuesTool(s)
+	addStrictKeyParityTool(s)
+	addGenerateMockTool(s)
+	addBenchmarkFileTool(s)
+	addCopyWithinTool(s)
+	addFindKeysByPatternTool(s)
+	addCoerceBooleansTool(s)
 
 	return s
 }
 
+// withEscapeHTML declares the escape_html option shared by tools that save the file
+func withEscapeHTML() mcp.ToolOption {
+	return mcp.WithBoolean("escape_html",
+		mcp.Description("HTML-escape <, > and & when saving this file; the choice is remembered for the file (optional, defaults to false)"),
+	)
+}
+
+// withVerifyAfterWrite declares the verify_after_write option shared by tools that save the file
+func withVerifyAfterWrite() mcp.ToolOption {
+	return mcp.WithBoolean("verify_after_write",
+		mcp.Description("Read the written content back and compare it with the intended data before replacing the file, failing without saving on a mismatch; the choice is remembered for the file (optional, defaults to false)"),
+	)
+}
+
+// withShowDiff declares the show_diff option shared by tools that modify the file
+func withShowDiff() mcp.ToolOption {
+	return mcp.WithBoolean("show_diff",
+		mcp.Description("Append a unified diff of the file's on-disk content before and after the change (optional, defaults to false)"),
+	)
+}
+
+// showingDiff wraps the handler of a tool that modifies file_path so that, when show_diff is
+// set, a successful result is followed by a unified diff of the file as it is on disk. The
+// content is captured by the writes themselves, so changes made by others don't show up.
+func showingDiff(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" || !mcp.ParseBoolean(request, "show_diff", false) {
+			return handler(ctx, request)
+		}
+
+		capture := jsonhandler.GetHandler(filePath).CaptureWrites()
+		result, err := handler(ctx, request)
+		before, after, wrote := capture.Stop()
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		diff := ""
+		if wrote {
+			diff = operations.UnifiedDiff("a/"+filePath, "b/"+filePath, before, after)
+		}
+		if diff == "" {
+			diff = "No changes on disk"
+		}
+		result.Content = append(result.Content, mcp.NewTextContent(diff))
+		return result, nil
+	}
+}
+
+// progressNotifier returns a function sending MCP progress notifications for request, or
+// nil if the client did not ask for progress
+func progressNotifier(ctx context.Context, request mcp.CallToolRequest) jsonhandler.ProgressFunc {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return nil
+	}
+
+	token := request.Params.Meta.ProgressToken
+	return func(processed, total int64) {
+		// Progress is best-effort; a failed notification must not fail the tool
+		_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      processed,
+			"total":         total,
+			"message":       fmt.Sprintf("Processed %d of %d bytes", processed, total),
+		})
+	}
+}
+
+// withCreateDirs declares the create_dirs option shared by tools that write an output file
+func withCreateDirs() mcp.ToolOption {
+	return mcp.WithBoolean("create_dirs",
+		mcp.Description("Create the output file's missing parent directories (optional, defaults to false)"),
+	)
+}
+
+// applySaveOptions applies save options passed to a tool to the target file. Call it once the
+// arguments are validated, so a rejected call leaves the file's options as they were.
+func applySaveOptions(request mcp.CallToolRequest, filePath string) {
+	if mcp.ParseArgument(request, "escape_html", nil) != nil {
+		operations.SetEscapeHTML(filePath, mcp.ParseBoolean(request, "escape_html", false))
+	}
+	if mcp.ParseArgument(request, "verify_after_write", nil) != nil {
+		operations.SetVerifyWrites(filePath, mcp.ParseBoolean(request, "verify_after_write", false))
+	}
+}
+
 // addGetKeyTool adds the get_key tool
 func addGetKeyTool(s *server.MCPServer) {
 	getTool := mcp.NewTool("get_key",
@@ -85,9 +268,12 @@ func addAddKeyTool(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("Value to add (can be string, object, array, etc.)"),
 		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
 	)
 
-	s.AddTool(addTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.AddTool(addTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		filePath := mcp.ParseString(request, "file_path", "")
 		if filePath == "" {
 			return mcp.NewToolResultError("Missing file_path"), nil
@@ -103,13 +289,14 @@ func addAddKeyTool(s *server.MCPServer) {
 			return mcp.NewToolResultError("Missing value"), nil
 		}
 
+		applySaveOptions(request, filePath)
 		err := operations.AddKey(filePath, keyPath, value)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("✅ Added key '%s' to %s", keyPath, filePath)), nil
-	})
+	}))
 }
 
 // addUpdateKeyTool adds the update_key tool
@@ -128,9 +315,15 @@ func addUpdateKeyTool(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("New value (can be string, object, array, etc.)"),
 		),
+		mcp.WithBoolean("enforce_type",
+			mcp.Description("Fail with TYPE_MISMATCH if the new value's JSON type differs from the existing value's (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
 	)
 
-	s.AddTool(updateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.AddTool(updateTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		filePath := mcp.ParseString(request, "file_path", "")
 		if filePath == "" {
 			return mcp.NewToolResultError("Missing file_path"), nil
@@ -146,13 +339,16 @@ func addUpdateKeyTool(s *server.MCPServer) {
 			return mcp.NewToolResultError("Missing value"), nil
 		}
 
-		err := operations.UpdateKey(filePath, keyPath, value)
+		enforceType := mcp.ParseBoolean(request, "enforce_type", false)
+
+		applySaveOptions(request, filePath)
+		err := operations.UpdateKey(filePath, keyPath, value, enforceType)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("✅ Updated key '%s' in %s", keyPath, filePath)), nil
-	})
+	}))
 }
 
 // addRenameKeyTool adds the rename_key tool
@@ -171,9 +367,12 @@ func addRenameKeyTool(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("New dot-notation path for the key"),
 		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
 	)
 
-	s.AddTool(renameTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.AddTool(renameTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		filePath := mcp.ParseString(request, "file_path", "")
 		if filePath == "" {
 			return mcp.NewToolResultError("Missing file_path"), nil
@@ -189,13 +388,14 @@ func addRenameKeyTool(s *server.MCPServer) {
 			return mcp.NewToolResultError("Missing new_path"), nil
 		}
 
+		applySaveOptions(request, filePath)
 		err := operations.RenameKey(filePath, oldPath, newPath)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("✅ Renamed '%s' → '%s' in %s", oldPath, newPath, filePath)), nil
-	})
+	}))
 }
 
 // addRemoveKeyTool adds the remove_key tool
@@ -210,9 +410,12 @@ func addRemoveKeyTool(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("Dot-notation path to the key to remove"),
 		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
 	)
 
-	s.AddTool(removeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.AddTool(removeTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		filePath := mcp.ParseString(request, "file_path", "")
 		if filePath == "" {
 			return mcp.NewToolResultError("Missing file_path"), nil
@@ -223,6 +426,7 @@ func addRemoveKeyTool(s *server.MCPServer) {
 			return mcp.NewToolResultError("Missing key_path"), nil
 		}
 
+		applySaveOptions(request, filePath)
 		removedValue, err := operations.RemoveKey(filePath, keyPath)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
@@ -234,7 +438,7 @@ func addRemoveKeyTool(s *server.MCPServer) {
 		}
 
 		return mcp.NewToolResultText(fmt.Sprintf("✅ Removed key '%s' from %s\nRemoved value: %s", keyPath, filePath, string(jsonValue))), nil
-	})
+	}))
 }
 
 // addListKeysTool adds the list_keys tool
@@ -248,6 +452,12 @@ func addListKeysTool(s *server.MCPServer) {
 		mcp.WithString("key_path",
 			mcp.Description("Dot-notation path to list keys from (optional, defaults to root)"),
 		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of keys, in sorted order, to skip (optional, defaults to 0)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of keys to return (optional, defaults to 0 for all)"),
+		),
 	)
 
 	s.AddTool(listTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -262,7 +472,10 @@ func addListKeysTool(s *server.MCPServer) {
 			keyPath = &keyPathStr
 		}
 
-		keys, err := operations.ListKeys(filePath, keyPath)
+		offset := mcp.ParseInt(request, "offset", 0)
+		limit := mcp.ParseInt(request, "limit", 0)
+
+		page, err := operations.ListKeysPage(filePath, keyPath, offset, limit)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
 		}
@@ -273,9 +486,18 @@ func addListKeysTool(s *server.MCPServer) {
 		}
 
 		result := fmt.Sprintf("Keys %s in %s:\n", pathDesc, filePath)
-		for _, key := range keys {
+		switch {
+		case len(page.Keys) == 0 && offset > 0:
+			result = fmt.Sprintf("No keys %s in %s from offset %d (%d in total)\n", pathDesc, filePath, offset, page.Total)
+		case offset > 0 || limit > 0:
+			result = fmt.Sprintf("Keys %s in %s (%d-%d of %d):\n", pathDesc, filePath, offset+1, offset+len(page.Keys), page.Total)
+		}
+		for _, key := range page.Keys {
 			result += fmt.Sprintf("• %s\n", key)
 		}
+		if page.HasMore {
+			result += fmt.Sprintf("More keys remain; continue with offset %d\n", offset+len(page.Keys))
+		}
 
 		return mcp.NewToolResultText(result), nil
 	})
@@ -328,6 +550,12 @@ func addValidateJSONTool(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("Path to the JSON file to validate"),
 		),
+		mcp.WithBoolean("collect_all_errors",
+			mcp.Description("Keep scanning after the first syntax error and report every error found, best-effort (default: false)"),
+		),
+		mcp.WithBoolean("stream_validate",
+			mcp.Description("Scan the file as it is read instead of loading it, for very large files; sends progress notifications when the request carries a progress token. Not combinable with collect_all_errors (default: false)"),
+		),
 	)
 
 	s.AddTool(validateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -336,7 +564,19 @@ func addValidateJSONTool(s *server.MCPServer) {
 			return mcp.NewToolResultError("Missing file_path"), nil
 		}
 
-		result, err := operations.ValidateJSON(filePath)
+		collectAll := mcp.ParseBoolean(request, "collect_all_errors", false)
+		stream := mcp.ParseBoolean(request, "stream_validate", false)
+		if collectAll && stream {
+			return mcp.NewToolResultError("collect_all_errors cannot be combined with stream_validate"), nil
+		}
+
+		var result *operations.ValidationResult
+		var err error
+		if stream {
+			result, err = operations.ValidateJSONStream(filePath, progressNotifier(ctx, request))
+		} else {
+			result, err = operations.ValidateJSONWithOptions(filePath, collectAll)
+		}
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
 		}
@@ -354,7 +594,3842 @@ func addValidateJSONTool(s *server.MCPServer) {
 				errorMsg = result.Error.Message
 				line = result.Error.Line
 			}
+			if len(result.Errors) > 1 {
+				errorList := fmt.Sprintf("❌ %s contains invalid JSON (%d errors found):\n", filePath, len(result.Errors))
+				for _, validationErr := range result.Errors {
+					errorList += fmt.Sprintf("• Line %d, column %d: %s\n", validationErr.Line, validationErr.Column, validationErr.Message)
+				}
+				return mcp.NewToolResultText(errorList), nil
+			}
 			return mcp.NewToolResultText(fmt.Sprintf("❌ %s contains invalid JSON\nError: %s\nLine: %d", filePath, errorMsg, line)), nil
 		}
 	})
+}
+
+// addClearKeyTool adds the clear_key tool
+func addClearKeyTool(s *server.MCPServer) {
+	clearTool := mcp.NewTool("clear_key",
+		mcp.WithDescription("Empty an object or array in JSON file without removing the key"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the object or array to clear"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(clearTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		oldValue, err := operations.ClearKey(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonValue, err := json.MarshalIndent(oldValue, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing old value: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Cleared key '%s' in %s\nPrevious contents: %s", keyPath, filePath, string(jsonValue))), nil
+	}))
+}
+
+// addGlobGetTool adds the glob_get tool
+func addGlobGetTool(s *server.MCPServer) {
+	globTool := mcp.NewTool("glob_get",
+		mcp.WithDescription("Get all values from JSON file matching a dot-notation pattern with '*' wildcard segments"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Dot-notation pattern where '*' matches any key (e.g., 'auth.*.title')"),
+		),
+	)
+
+	s.AddTool(globTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pattern := mcp.ParseString(request, "pattern", "")
+		if pattern == "" {
+			return mcp.NewToolResultError("Missing pattern"), nil
+		}
+
+		matches, err := operations.GlobGet(filePath, pattern)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addRemoveMatchingTool adds the remove_matching tool
+func addRemoveMatchingTool(s *server.MCPServer) {
+	removeMatchingTool := mcp.NewTool("remove_matching",
+		mcp.WithDescription("Remove all keys from JSON file matching a dot-notation pattern with '*' wildcard segments"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Dot-notation pattern where '*' matches any key (e.g., 'legacy.*')"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Preview matching keys without removing them (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(removeMatchingTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pattern := mcp.ParseString(request, "pattern", "")
+		if pattern == "" {
+			return mcp.NewToolResultError("Missing pattern"), nil
+		}
+
+		dryRun := mcp.ParseBoolean(request, "dry_run", false)
+
+		applySaveOptions(request, filePath)
+		removed, err := operations.RemoveMatching(filePath, pattern, dryRun)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonValue, err := json.MarshalIndent(removed, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing removed values: %v", err)), nil
+		}
+
+		if dryRun {
+			return mcp.NewToolResultText(fmt.Sprintf("Dry run: %d key(s) matching '%s' would be removed from %s\n%s", len(removed), pattern, filePath, string(jsonValue))), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Removed %d key(s) matching '%s' from %s\nRemoved values: %s", len(removed), pattern, filePath, string(jsonValue))), nil
+	}))
+}
+
+// addCountValueTool adds the count_value tool
+func addCountValueTool(s *server.MCPServer) {
+	countTool := mcp.NewTool("count_value",
+		mcp.WithDescription("Count how many values in JSON file deep-equal the given value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("Value to look for (can be string, object, array, etc.)"),
+		),
+	)
+
+	s.AddTool(countTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		value := mcp.ParseArgument(request, "value", nil)
+		if value == nil {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		result, err := operations.CountValue(filePath, value)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addGeneratePatchTool adds the generate_patch tool
+func addGeneratePatchTool(s *server.MCPServer) {
+	patchTool := mcp.NewTool("generate_patch",
+		mcp.WithDescription("Generate an RFC 6902 JSON Patch that transforms one JSON file into another"),
+		mcp.WithString("file_a",
+			mcp.Required(),
+			mcp.Description("Path to the source JSON file"),
+		),
+		mcp.WithString("file_b",
+			mcp.Required(),
+			mcp.Description("Path to the target JSON file"),
+		),
+	)
+
+	s.AddTool(patchTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fileA := mcp.ParseString(request, "file_a", "")
+		if fileA == "" {
+			return mcp.NewToolResultError("Missing file_a"), nil
+		}
+
+		fileB := mcp.ParseString(request, "file_b", "")
+		if fileB == "" {
+			return mcp.NewToolResultError("Missing file_b"), nil
+		}
+
+		patch, err := operations.GeneratePatch(fileA, fileB)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(patch, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing patch: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addApplyTransactionTool adds the apply_transaction tool
+func addApplyTransactionTool(s *server.MCPServer) {
+	transactionTool := mcp.NewTool("apply_transaction",
+		mcp.WithDescription("Apply a sequence of add/update/remove/rename/move operations to JSON file atomically"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithArray("operations",
+			mcp.Required(),
+			mcp.Description("Ordered list of operations, each with 'action', 'key_path', and 'value' (add/update) or 'new_path' (rename/move)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(transactionTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		rawOps := mcp.ParseArgument(request, "operations", nil)
+		if rawOps == nil {
+			return mcp.NewToolResultError("Missing operations"), nil
+		}
+
+		// Round-trip through JSON to decode the generic argument into typed operations
+		var ops []operations.Operation
+		encoded, err := json.Marshal(rawOps)
+		if err == nil {
+			err = json.Unmarshal(encoded, &ops)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: Invalid operations: %v", err)), nil
+		}
+
+		applySaveOptions(request, filePath)
+		if err := operations.ApplyTransaction(filePath, ops); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Applied %d operation(s) to %s", len(ops), filePath)), nil
+	}))
+}
+
+// addLocateKeyTool adds the locate_key tool
+func addLocateKeyTool(s *server.MCPServer) {
+	locateTool := mcp.NewTool("locate_key",
+		mcp.WithDescription("Get value from JSON file together with the line and column where its key is defined"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the key (e.g., 'auth.login.title')"),
+		),
+	)
+
+	s.AddTool(locateTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		location, err := operations.LocateKey(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(location, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addLargestSubtreesTool adds the largest_subtrees tool
+func addLargestSubtreesTool(s *server.MCPServer) {
+	largestTool := mcp.NewTool("largest_subtrees",
+		mcp.WithDescription("List the largest values in JSON file by serialized size"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithNumber("top_n",
+			mcp.Description("Number of entries to return (optional, defaults to 10)"),
+		),
+		mcp.WithNumber("depth",
+			mcp.Description("Maximum path depth to consider (optional, defaults to 1 for top-level keys)"),
+		),
+	)
+
+	s.AddTool(largestTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		topN := mcp.ParseInt(request, "top_n", 10)
+		depth := mcp.ParseInt(request, "depth", 1)
+
+		sizes, err := operations.LargestSubtrees(filePath, topN, depth)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		result := fmt.Sprintf("Largest subtrees in %s:\n", filePath)
+		for _, entry := range sizes {
+			result += fmt.Sprintf("• %s: %d bytes\n", entry.Path, entry.Size)
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addRenderTreeTool adds the render_tree tool
+func addRenderTreeTool(s *server.MCPServer) {
+	treeTool := mcp.NewTool("render_tree",
+		mcp.WithDescription("Render the structure of JSON file as an indented ASCII tree"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Description("Dot-notation path to render from (optional, defaults to root)"),
+		),
+		mcp.WithNumber("max_depth",
+			mcp.Description("Maximum depth to expand (optional, defaults to unlimited)"),
+		),
+		mcp.WithNumber("max_value_length",
+			mcp.Description("Truncate leaf values longer than this many characters (optional, defaults to 40)"),
+		),
+	)
+
+	s.AddTool(treeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		var keyPath *string
+		keyPathStr := mcp.ParseString(request, "key_path", "")
+		if keyPathStr != "" {
+			keyPath = &keyPathStr
+		}
+
+		maxDepth := mcp.ParseInt(request, "max_depth", 0)
+		maxValueLen := mcp.ParseInt(request, "max_value_length", 40)
+
+		tree, err := operations.RenderTree(filePath, keyPath, maxDepth, maxValueLen)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(tree), nil
+	})
+}
+
+// addInsertArrayElementTool adds the insert_array_element tool
+func addInsertArrayElementTool(s *server.MCPServer) {
+	insertTool := mcp.NewTool("insert_array_element",
+		mcp.WithDescription("Insert a value into an array in JSON file at a specific index"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the array"),
+		),
+		mcp.WithNumber("index",
+			mcp.Required(),
+			mcp.Description("Position to insert at; the array length appends and negative values count from the end"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("Value to insert (can be string, object, array, etc.)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(insertTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		if mcp.ParseArgument(request, "index", nil) == nil {
+			return mcp.NewToolResultError("Missing index"), nil
+		}
+		index := mcp.ParseInt(request, "index", 0)
+
+		value := mcp.ParseArgument(request, "value", nil)
+		if value == nil {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		if err := operations.InsertArrayElement(filePath, keyPath, index, value); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Inserted element at index %d of '%s' in %s", index, keyPath, filePath)), nil
+	}))
+}
+
+// addUpdateArrayElementTool adds the update_array_element tool
+func addUpdateArrayElementTool(s *server.MCPServer) {
+	updateElementTool := mcp.NewTool("update_array_element",
+		mcp.WithDescription("Replace the element at a specific index of an array in JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the array"),
+		),
+		mcp.WithNumber("index",
+			mcp.Required(),
+			mcp.Description("Index of the element to replace; negative values count from the end"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("New value (can be string, object, array, etc.)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(updateElementTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		if mcp.ParseArgument(request, "index", nil) == nil {
+			return mcp.NewToolResultError("Missing index"), nil
+		}
+		index := mcp.ParseInt(request, "index", 0)
+
+		value := mcp.ParseArgument(request, "value", nil)
+		if value == nil {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		if err := operations.UpdateArrayElement(filePath, keyPath, index, value); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Updated element at index %d of '%s' in %s", index, keyPath, filePath)), nil
+	}))
+}
+
+// addFindInArrayTool adds the find_in_array tool
+func addFindInArrayTool(s *server.MCPServer) {
+	findTool := mcp.NewTool("find_in_array",
+		mcp.WithDescription("Find the indices of array elements in JSON file matching a value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the array"),
+		),
+		mcp.WithString("match_field",
+			mcp.Description("Dot-notation field within each element to compare (optional, defaults to comparing whole elements)"),
+		),
+		mcp.WithObject("match_value",
+			mcp.Required(),
+			mcp.Description("Value to match (can be string, object, array, etc.)"),
+		),
+	)
+
+	s.AddTool(findTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		matchField := mcp.ParseString(request, "match_field", "")
+
+		matchValue := mcp.ParseArgument(request, "match_value", nil)
+		if matchValue == nil {
+			return mcp.NewToolResultError("Missing match_value"), nil
+		}
+
+		indices, err := operations.FindInArray(filePath, keyPath, matchField, matchValue)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.Marshal(indices)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Matching indices in '%s': %s", keyPath, string(jsonResult))), nil
+	})
+}
+
+// addPathsEqualTool adds the paths_equal tool
+func addPathsEqualTool(s *server.MCPServer) {
+	equalTool := mcp.NewTool("paths_equal",
+		mcp.WithDescription("Check whether two paths in JSON file hold deep-equal values"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("path_a",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the first value"),
+		),
+		mcp.WithString("path_b",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the second value"),
+		),
+	)
+
+	s.AddTool(equalTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pathA := mcp.ParseString(request, "path_a", "")
+		if pathA == "" {
+			return mcp.NewToolResultError("Missing path_a"), nil
+		}
+
+		pathB := mcp.ParseString(request, "path_b", "")
+		if pathB == "" {
+			return mcp.NewToolResultError("Missing path_b"), nil
+		}
+
+		result, err := operations.PathsEqual(filePath, pathA, pathB)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if result.Equal {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ '%s' and '%s' are equal in %s", pathA, pathB, filePath)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("❌ '%s' and '%s' differ in %s\nFirst difference: %s", pathA, pathB, filePath, result.Difference)), nil
+	})
+}
+
+// addSnapshotFileTool adds the snapshot_file tool
+func addSnapshotFileTool(s *server.MCPServer) {
+	snapshotTool := mcp.NewTool("snapshot_file",
+		mcp.WithDescription("Save a named snapshot of JSON file that can be restored later"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("label",
+			mcp.Required(),
+			mcp.Description("Snapshot name (letters, digits, '.', '_' and '-')"),
+		),
+	)
+
+	s.AddTool(snapshotTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		label := mcp.ParseString(request, "label", "")
+		if label == "" {
+			return mcp.NewToolResultError("Missing label"), nil
+		}
+
+		if err := operations.Snapshot(filePath, label); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Saved snapshot '%s' of %s", label, filePath)), nil
+	})
+}
+
+// addRestoreFileTool adds the restore_file tool
+func addRestoreFileTool(s *server.MCPServer) {
+	restoreTool := mcp.NewTool("restore_file",
+		mcp.WithDescription("Restore JSON file from a named snapshot"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("label",
+			mcp.Required(),
+			mcp.Description("Name of the snapshot to restore"),
+		),
+		withShowDiff(),
+	)
+
+	s.AddTool(restoreTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		label := mcp.ParseString(request, "label", "")
+		if label == "" {
+			return mcp.NewToolResultError("Missing label"), nil
+		}
+
+		if err := operations.Restore(filePath, label); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Restored %s from snapshot '%s'", filePath, label)), nil
+	}))
+}
+
+// addListSnapshotsTool adds the list_snapshots tool
+func addListSnapshotsTool(s *server.MCPServer) {
+	listSnapshotsTool := mcp.NewTool("list_snapshots",
+		mcp.WithDescription("List the named snapshots saved for JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(listSnapshotsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		snapshots, err := operations.ListSnapshots(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(snapshots) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No snapshots for %s", filePath)), nil
+		}
+
+		result := fmt.Sprintf("Snapshots of %s:\n", filePath)
+		for _, snapshot := range snapshots {
+			result += fmt.Sprintf("• %s (%d bytes, %s)\n", snapshot.Label, snapshot.SizeBytes, snapshot.CreatedTime.Format(time.RFC3339))
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addUndoTool adds the undo tool
+func addUndoTool(s *server.MCPServer) {
+	undoTool := mcp.NewTool("undo",
+		mcp.WithDescription(fmt.Sprintf("Revert the last edit made to JSON file (up to %d edits are kept)", jsonhandler.MaxHistory)),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(undoTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		if err := operations.Undo(filePath); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Undid last edit to %s", filePath)), nil
+	}))
+}
+
+// addRedoTool adds the redo tool
+func addRedoTool(s *server.MCPServer) {
+	redoTool := mcp.NewTool("redo",
+		mcp.WithDescription("Reapply the last edit reverted by undo"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(redoTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		if err := operations.Redo(filePath); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Redid last undone edit to %s", filePath)), nil
+	}))
+}
+
+// addDetectEncodingTool adds the detect_encoding tool
+func addDetectEncodingTool(s *server.MCPServer) {
+	detectTool := mcp.NewTool("detect_encoding",
+		mcp.WithDescription("Report the character encoding of JSON file (UTF-8, UTF-16LE or UTF-16BE)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(detectTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		info, err := operations.DetectEncoding(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		bom := "without byte order mark"
+		if info.BOM {
+			bom = "with byte order mark"
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("%s is encoded as %s (%s)", filePath, info.Encoding, bom)), nil
+	})
+}
+
+// addWrapKeyTool adds the wrap_key tool
+func addWrapKeyTool(s *server.MCPServer) {
+	wrapTool := mcp.NewTool("wrap_key",
+		mcp.WithDescription("Nest existing key one level deeper under a new parent object (e.g. title → header.title)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the key to wrap"),
+		),
+		mcp.WithString("new_parent",
+			mcp.Required(),
+			mcp.Description("Name of the parent object to place the key under, created next to the key if missing"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(wrapTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		newParent := mcp.ParseString(request, "new_parent", "")
+		if newParent == "" {
+			return mcp.NewToolResultError("Missing new_parent"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		newPath, err := operations.WrapKey(filePath, keyPath, newParent)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Wrapped '%s' → '%s' in %s", keyPath, newPath, filePath)), nil
+	}))
+}
+
+// addPromoteChildrenTool adds the promote_children tool
+func addPromoteChildrenTool(s *server.MCPServer) {
+	promoteTool := mcp.NewTool("promote_children",
+		mcp.WithDescription("Move the children of an object up into its parent and remove the object (e.g. header.title → title)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the object to flatten"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace existing siblings with the same names instead of failing (default: false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(promoteTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		overwrite := mcp.ParseBoolean(request, "overwrite", false)
+
+		applySaveOptions(request, filePath)
+		promoted, err := operations.PromoteChildren(filePath, keyPath, overwrite)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Promoted %d keys out of '%s' in %s: %s", len(promoted), keyPath, filePath, strings.Join(promoted, ", "))), nil
+	}))
+}
+
+// addShowSubtreeTool adds the show_subtree tool
+func addShowSubtreeTool(s *server.MCPServer) {
+	showTool := mcp.NewTool("show_subtree",
+		mcp.WithDescription("Show the value at a path in JSON file as pretty-printed JSON"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the subtree to show"),
+		),
+		mcp.WithNumber("indent",
+			mcp.Description("Spaces per indentation level, 0 for compact output (optional, defaults to 2, max 8)"),
+		),
+	)
+
+	s.AddTool(showTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		indent := mcp.ParseInt(request, "indent", 2)
+
+		result, err := operations.ShowSubtree(filePath, keyPath, indent)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addCompareAndSetTool adds the compare_and_set tool
+func addCompareAndSetTool(s *server.MCPServer) {
+	casTool := mcp.NewTool("compare_and_set",
+		mcp.WithDescription("Update key in JSON file only if its current value equals an expected value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the key to update"),
+		),
+		mcp.WithObject("expected",
+			mcp.Required(),
+			mcp.Description("Value the key must currently hold (can be null, string, object, array, etc.)"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("New value to write if the current value matches"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(casTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		// null is a legitimate expected or new value, so check presence instead
+		args := request.GetArguments()
+		expected, ok := args["expected"]
+		if !ok {
+			return mcp.NewToolResultError("Missing expected"), nil
+		}
+		value, ok := args["value"]
+		if !ok {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		swapped, err := operations.CompareAndSet(filePath, keyPath, expected, value)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if !swapped {
+			return mcp.NewToolResultText(fmt.Sprintf("Key '%s' in %s does not hold the expected value; not updated", keyPath, filePath)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Updated key '%s' in %s", keyPath, filePath)), nil
+	}))
+}
+
+// addGetKeyExpandedTool adds the get_key_expanded tool
+func addGetKeyExpandedTool(s *server.MCPServer) {
+	getExpandedTool := mcp.NewTool("get_key_expanded",
+		mcp.WithDescription("Get value from JSON file with ${VAR}/$VAR environment references in strings expanded (file is not modified)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the key (e.g., 'database.url')"),
+		),
+		mcp.WithBoolean("strict",
+			mcp.Description("Fail if a referenced variable is not set instead of leaving the reference as written (optional, defaults to false)"),
+		),
+	)
+
+	s.AddTool(getExpandedTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		strict := mcp.ParseBoolean(request, "strict", false)
+
+		result, err := operations.GetKeyExpanded(filePath, keyPath, strict)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addListPlaceholdersTool adds the list_placeholders tool
+func addListPlaceholdersTool(s *server.MCPServer) {
+	placeholdersTool := mcp.NewTool("list_placeholders",
+		mcp.WithDescription("List the unique placeholder variables used in JSON file's strings, with the paths using each"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("style",
+			mcp.Description("Placeholder syntax: handlebars ({{name}}), icu ({name}) or printf (%s, %(name)s) (optional, defaults to handlebars)"),
+			mcp.Enum(operations.PlaceholderHandlebars, operations.PlaceholderICU, operations.PlaceholderPrintf),
+		),
+	)
+
+	s.AddTool(placeholdersTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		style := mcp.ParseString(request, "style", operations.PlaceholderHandlebars)
+
+		placeholders, err := operations.ListPlaceholders(filePath, style)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(placeholders, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addWithoutKeyTool adds the without_key tool
+func addWithoutKeyTool(s *server.MCPServer) {
+	withoutTool := mcp.NewTool("without_key",
+		mcp.WithDescription("Return the whole JSON document with one key removed, without modifying the file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the key to leave out"),
+		),
+	)
+
+	s.AddTool(withoutTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		result, err := operations.WithoutKey(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addRedactJSONTool adds the redact_json tool
+func addRedactJSONTool(s *server.MCPServer) {
+	redactTool := mcp.NewTool("redact_json",
+		mcp.WithDescription("Mask sensitive values (e.g. passwords, tokens) in JSON file for sharing; the source file is never modified"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the redacted copy (optional; the result is only returned if omitted)"),
+		),
+		mcp.WithArray("patterns",
+			mcp.Description("Case-insensitive globs matched against key names (optional, defaults to *password*, *token*, *secret*)"),
+			mcp.WithStringItems(),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(redactTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+		patterns := request.GetStringSlice("patterns", nil)
+
+		result, err := operations.Redact(filePath, outputFile, patterns, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if outputFile != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote %s with %d value(s) redacted", outputFile, len(result.RedactedPaths))), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result.Document, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addPickKeysTool adds the pick_keys tool
+func addPickKeysTool(s *server.MCPServer) {
+	pickTool := mcp.NewTool("pick_keys",
+		mcp.WithDescription("Build a new JSON document containing only the given paths, keeping their nesting"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the source JSON file"),
+		),
+		mcp.WithArray("key_paths",
+			mcp.Required(),
+			mcp.Description("Dot-notation paths to copy into the new document"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the new document (optional; the result is only returned if omitted)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(pickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPaths := request.GetStringSlice("key_paths", nil)
+		if len(keyPaths) == 0 {
+			return mcp.NewToolResultError("Missing key_paths"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		result, err := operations.Pick(filePath, keyPaths, outputFile, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return projectionResult(result, outputFile)
+	})
+}
+
+// projectionResult reports a pick/omit result, either as the written file or the document itself
+func projectionResult(result *operations.ProjectionResult, outputFile string) (*mcp.CallToolResult, error) {
+	missing := ""
+	if len(result.Missing) > 0 {
+		missing = fmt.Sprintf("\nNot found: %s", strings.Join(result.Missing, ", "))
+	}
+
+	if outputFile != "" {
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote %s%s", outputFile, missing)), nil
+	}
+
+	jsonResult, err := json.MarshalIndent(result.Document, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResult) + missing), nil
+}
+
+// addOmitKeysTool adds the omit_keys tool
+func addOmitKeysTool(s *server.MCPServer) {
+	omitTool := mcp.NewTool("omit_keys",
+		mcp.WithDescription("Remove several paths from JSON file in one pass, producing a reduced document"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the source JSON file"),
+		),
+		mcp.WithArray("key_paths",
+			mcp.Required(),
+			mcp.Description("Dot-notation paths to leave out"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the reduced document (optional; the result is only returned if omitted)"),
+		),
+		mcp.WithBoolean("in_place",
+			mcp.Description("Remove the paths from the source file itself (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+		withCreateDirs(),
+	)
+
+	s.AddTool(omitTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPaths := request.GetStringSlice("key_paths", nil)
+		if len(keyPaths) == 0 {
+			return mcp.NewToolResultError("Missing key_paths"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+		inPlace := mcp.ParseBoolean(request, "in_place", false)
+
+		applySaveOptions(request, filePath)
+		result, err := operations.Omit(filePath, keyPaths, outputFile, inPlace, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if inPlace {
+			return projectionResult(result, filePath)
+		}
+		return projectionResult(result, outputFile)
+	}))
+}
+
+// addRequireKeysTool adds the require_keys tool
+func addRequireKeysTool(s *server.MCPServer) {
+	requireTool := mcp.NewTool("require_keys",
+		mcp.WithDescription("Check that all required paths exist in JSON file and report any that are missing"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithArray("required_paths",
+			mcp.Required(),
+			mcp.Description("Dot-notation paths that must be present (e.g., 'database.url')"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithBoolean("null_is_missing",
+			mcp.Description("Treat keys that are present but null as missing (optional, defaults to false)"),
+		),
+	)
+
+	s.AddTool(requireTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		requiredPaths := request.GetStringSlice("required_paths", nil)
+		if len(requiredPaths) == 0 {
+			return mcp.NewToolResultError("Missing required_paths"), nil
+		}
+
+		nullIsMissing := mcp.ParseBoolean(request, "null_is_missing", false)
+
+		report, err := operations.RequireKeys(filePath, requiredPaths, nullIsMissing)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		nulls := ""
+		if len(report.Null) > 0 {
+			nulls = fmt.Sprintf("\nPresent but null: %s", strings.Join(report.Null, ", "))
+		}
+
+		if !report.Passed {
+			return mcp.NewToolResultText(fmt.Sprintf("❌ %s is missing %d of %d required keys: %s%s", filePath, len(report.Missing), len(requiredPaths), strings.Join(report.Missing, ", "), nulls)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ All %d required keys are present in %s%s", len(requiredPaths), filePath, nulls)), nil
+	})
+}
+
+// addAssertValuesMatchTool adds the assert_values_match tool
+func addAssertValuesMatchTool(s *server.MCPServer) {
+	assertTool := mcp.NewTool("assert_values_match",
+		mcp.WithDescription("Check that string values at paths matching a '*' pattern all match a regular expression"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("path_glob",
+			mcp.Required(),
+			mcp.Description("Dot-notation path where '*' matches any key (e.g., 'services.*.url')"),
+		),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Regular expression each value must match (e.g., '^https://')"),
+		),
+	)
+
+	s.AddTool(assertTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pathGlob := mcp.ParseString(request, "path_glob", "")
+		if pathGlob == "" {
+			return mcp.NewToolResultError("Missing path_glob"), nil
+		}
+
+		pattern := mcp.ParseString(request, "pattern", "")
+		if pattern == "" {
+			return mcp.NewToolResultError("Missing pattern"), nil
+		}
+
+		report, err := operations.AssertValuesMatch(filePath, pathGlob, pattern)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(report.Failures) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All %d value(s) matching '%s' satisfy %s", report.Checked, pathGlob, pattern)), nil
+		}
+
+		result := fmt.Sprintf("❌ %d of %d value(s) matching '%s' do not satisfy %s:\n", len(report.Failures), report.Checked, pathGlob, pattern)
+		for _, failure := range report.Failures {
+			result += fmt.Sprintf("• %s: %q\n", failure.Path, failure.Value)
+		}
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addCacheStatsTool adds the cache_stats tool
+func addCacheStatsTool(s *server.MCPServer) {
+	statsTool := mcp.NewTool("cache_stats",
+		mcp.WithDescription("Report cache hits, misses and reloads for JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(statsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		report := operations.CacheStats(filePath)
+
+		jsonResult, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addClearCacheTool adds the clear_cache tool
+func addClearCacheTool(s *server.MCPServer) {
+	clearCacheTool := mcp.NewTool("clear_cache",
+		mcp.WithDescription("Drop cached data for JSON file so the next read reparses it from disk"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(clearCacheTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		if !operations.ClearCache(filePath) {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Nothing was cached for %s", filePath)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Cleared cache for %s", filePath)), nil
+	})
+}
+
+// addNormalizeNumbersTool adds the normalize_numbers tool
+func addNormalizeNumbersTool(s *server.MCPServer) {
+	normalizeTool := mcp.NewTool("normalize_numbers",
+		mcp.WithDescription("Rewrite whole numbers in JSON file consistently, either without a decimal part (1.0 -> 1) or with one (1 -> 1.0)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("integer_policy",
+			mcp.Description("How to write whole numbers: int (1) or float (1.0) (optional, defaults to int)"),
+			mcp.Enum(operations.IntegerPolicyInt, operations.IntegerPolicyFloat),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(normalizeTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		integerPolicy := mcp.ParseString(request, "integer_policy", operations.IntegerPolicyInt)
+
+		applySaveOptions(request, filePath)
+		changed, err := operations.NormalizeNumbers(filePath, integerPolicy)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Reformatted %d number(s) using the %s policy", changed, integerPolicy)), nil
+	}))
+}
+
+// addTransformStringsTool adds the transform_strings tool
+func addTransformStringsTool(s *server.MCPServer) {
+	transformTool := mcp.NewTool("transform_strings",
+		mcp.WithDescription("Lowercase, uppercase or trim string values in JSON file in bulk"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("transform",
+			mcp.Required(),
+			mcp.Description("Transform to apply: lower, upper, trim (spaces only) or trimspace (all whitespace)"),
+			mcp.Enum("lower", "upper", "trim", "trimspace"),
+		),
+		mcp.WithString("path_glob",
+			mcp.Description("Dot-notation pattern where '*' matches any key, limiting which values are transformed (optional, defaults to the whole file)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(transformTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		transform := mcp.ParseString(request, "transform", "")
+		if transform == "" {
+			return mcp.NewToolResultError("Missing transform"), nil
+		}
+
+		pathGlob := mcp.ParseString(request, "path_glob", "")
+
+		applySaveOptions(request, filePath)
+		changed, err := operations.TransformStrings(filePath, transform, pathGlob)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Applied %s to %d string(s)", transform, changed)), nil
+	}))
+}
+
+// addCheckKeyNamingTool adds the check_key_naming tool
+func addCheckKeyNamingTool(s *server.MCPServer) {
+	namingTool := mcp.NewTool("check_key_naming",
+		mcp.WithDescription("List every key path in JSON file whose name doesn't follow a naming convention"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("convention",
+			mcp.Required(),
+			mcp.Description("Naming convention keys must follow: camelCase, snake_case or kebab-case"),
+			mcp.Enum(operations.ConventionCamelCase, operations.ConventionSnakeCase, operations.ConventionKebabCase),
+		),
+	)
+
+	s.AddTool(namingTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		convention := mcp.ParseString(request, "convention", "")
+		if convention == "" {
+			return mcp.NewToolResultError("Missing convention"), nil
+		}
+
+		violations, err := operations.CheckKeyNaming(filePath, convention)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(violations) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All keys follow %s", convention)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(violations, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %d key(s) don't follow %s:\n%s", len(violations), convention, string(jsonResult))), nil
+	})
+}
+
+// addNormalizeKeyNamesTool adds the normalize_key_names tool
+func addNormalizeKeyNamesTool(s *server.MCPServer) {
+	normalizeTool := mcp.NewTool("normalize_key_names",
+		mcp.WithDescription("Rename every key in JSON file to a naming convention and report the renames applied"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("convention",
+			mcp.Required(),
+			mcp.Description("Naming convention to apply: camelCase, snake_case or kebab-case"),
+			mcp.Enum(operations.ConventionCamelCase, operations.ConventionSnakeCase, operations.ConventionKebabCase),
+		),
+		mcp.WithBoolean("suffix_collisions",
+			mcp.Description("Add a numeric suffix when two keys of one object would get the same name, instead of failing (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(normalizeTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		convention := mcp.ParseString(request, "convention", "")
+		if convention == "" {
+			return mcp.NewToolResultError("Missing convention"), nil
+		}
+
+		suffixCollisions := mcp.ParseBoolean(request, "suffix_collisions", false)
+
+		applySaveOptions(request, filePath)
+		renames, err := operations.NormalizeKeyNames(filePath, convention, suffixCollisions)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(renames) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All keys already follow %s", convention)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(renames, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Renamed %d key(s) to %s:\n%s", len(renames), convention, string(jsonResult))), nil
+	}))
+}
+
+// addGetKeyDepthTool adds the get_key_depth tool
+func addGetKeyDepthTool(s *server.MCPServer) {
+	depthTool := mcp.NewTool("get_key_depth",
+		mcp.WithDescription("Get how deeply a key is nested in JSON file, or the deepest nesting level in the whole file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Description("Dot-notation path of the key (optional; omit to get the maximum depth of the file)"),
+		),
+	)
+
+	s.AddTool(depthTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			depth, err := operations.MaxDepth(filePath)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Maximum depth of %s: %d", filePath, depth)), nil
+		}
+
+		depth, err := operations.GetKeyDepth(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Depth of '%s': %d", keyPath, depth)), nil
+	})
+}
+
+// addSplitByTopKeyTool adds the split_by_top_key tool
+func addSplitByTopKeyTool(s *server.MCPServer) {
+	splitTool := mcp.NewTool("split_by_top_key",
+		mcp.WithDescription("Split JSON file into one file per top-level key, written as <output_dir>/<key>.json"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to split"),
+		),
+		mcp.WithString("output_dir",
+			mcp.Required(),
+			mcp.Description("Directory to write the files to; created if missing"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace output files that already exist (optional, defaults to false)"),
+		),
+	)
+
+	s.AddTool(splitTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		outputDir := mcp.ParseString(request, "output_dir", "")
+		if outputDir == "" {
+			return mcp.NewToolResultError("Missing output_dir"), nil
+		}
+
+		overwrite := mcp.ParseBoolean(request, "overwrite", false)
+
+		outputFiles, err := operations.SplitByTopKey(filePath, outputDir, overwrite)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(outputFiles, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Split %s into %d file(s):\n%s", filePath, len(outputFiles), string(jsonResult))), nil
+	})
+}
+
+// addCombineFilesTool adds the combine_files tool
+func addCombineFilesTool(s *server.MCPServer) {
+	combineTool := mcp.NewTool("combine_files",
+		mcp.WithDescription("Combine several JSON files into one, nesting each under its file name or merging them at the root"),
+		mcp.WithArray("input_files",
+			mcp.Required(),
+			mcp.Description("Paths of the JSON files to combine"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("output_file",
+			mcp.Required(),
+			mcp.Description("Path of the combined JSON file to write"),
+		),
+		mcp.WithBoolean("key_from_filename",
+			mcp.Description("Nest each file under its name without extension, e.g. dashboard.json under \"dashboard\"; if false, top-level keys are merged (optional, defaults to true)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(combineTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		inputFiles := request.GetStringSlice("input_files", nil)
+		if len(inputFiles) == 0 {
+			return mcp.NewToolResultError("Missing input_files"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		if outputFile == "" {
+			return mcp.NewToolResultError("Missing output_file"), nil
+		}
+
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		keyFromFilename := mcp.ParseBoolean(request, "key_from_filename", true)
+
+		keys, err := operations.CombineFiles(inputFiles, outputFile, keyFromFilename, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Combined %d file(s) into %s with top-level keys: %s", len(inputFiles), outputFile, strings.Join(keys, ", "))), nil
+	})
+}
+
+// addRecentChangesTool adds the recent_changes tool
+func addRecentChangesTool(s *server.MCPServer) {
+	changesTool := mcp.NewTool("recent_changes",
+		mcp.WithDescription("List the key paths changed by recent edits to JSON file made through this server, newest first"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of edits to list (optional, defaults to 10; 0 lists every recorded edit)"),
+		),
+	)
+
+	s.AddTool(changesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		limit := mcp.ParseInt(request, "limit", 10)
+
+		records := operations.RecentChanges(filePath, limit)
+		if len(records) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No recorded changes for %s", filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addValidateShapeTool adds the validate_shape tool
+func addValidateShapeTool(s *server.MCPServer) {
+	shapeTool := mcp.NewTool("validate_shape",
+		mcp.WithDescription("Check that JSON file has the same structure and value types as a reference file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to check"),
+		),
+		mcp.WithString("shape_file",
+			mcp.Required(),
+			mcp.Description("Path to the reference JSON file whose paths and types must be present"),
+		),
+		mcp.WithBoolean("strict",
+			mcp.Description("Also fail on paths that are not in the reference file (optional, defaults to false)"),
+		),
+	)
+
+	s.AddTool(shapeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		shapeFile := mcp.ParseString(request, "shape_file", "")
+		if shapeFile == "" {
+			return mcp.NewToolResultError("Missing shape_file"), nil
+		}
+
+		strict := mcp.ParseBoolean(request, "strict", false)
+
+		report, err := operations.ValidateShape(filePath, shapeFile, strict)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if report.Passed {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ %s matches the shape of %s", filePath, shapeFile)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %s does not match the shape of %s:\n%s", filePath, shapeFile, string(jsonResult))), nil
+	})
+}
+
+// addResolveRefsTool adds the resolve_refs tool
+func addResolveRefsTool(s *server.MCPServer) {
+	resolveTool := mcp.NewTool("resolve_refs",
+		mcp.WithDescription("Inline local {\"$ref\": \"#/...\"} references in JSON file, producing a fully expanded document; the source file is never modified"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the expanded document (optional; the result is only returned if omitted)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(resolveTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		result, err := operations.ResolveRefs(filePath, outputFile, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if outputFile != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote %s with %d reference(s) resolved", outputFile, result.Resolved)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result.Document, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addListByTypeTool adds the list_by_type tool
+func addListByTypeTool(s *server.MCPServer) {
+	listTool := mcp.NewTool("list_by_type",
+		mcp.WithDescription("List the paths of every value of a given JSON type in JSON file, e.g. all booleans or nulls"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("type",
+			mcp.Required(),
+			mcp.Description("JSON type to look for"),
+			mcp.Enum("string", "number", "boolean", "null", "object", "array"),
+		),
+	)
+
+	s.AddTool(listTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		jsonType := mcp.ParseString(request, "type", "")
+		if jsonType == "" {
+			return mcp.NewToolResultError("Missing type"), nil
+		}
+
+		paths, err := operations.ListByType(filePath, jsonType)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(paths, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Found %d %s value(s):\n%s", len(paths), jsonType, string(jsonResult))), nil
+	})
+}
+
+// addPruneEmptyTool adds the prune_empty tool
+func addPruneEmptyTool(s *server.MCPServer) {
+	pruneTool := mcp.NewTool("prune_empty",
+		mcp.WithDescription("Remove empty objects and arrays from JSON file, including ones that only become empty after pruning"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithBoolean("remove_null",
+			mcp.Description("Also remove null values (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(pruneTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		removeNull := mcp.ParseBoolean(request, "remove_null", false)
+
+		applySaveOptions(request, filePath)
+		removed, err := operations.PruneEmpty(filePath, removeNull)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(removed) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Nothing to prune in %s", filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(removed, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Removed %d path(s) from %s:\n%s", len(removed), filePath, string(jsonResult))), nil
+	}))
+}
+
+// addSectionHashesTool adds the section_hashes tool
+func addSectionHashesTool(s *server.MCPServer) {
+	hashesTool := mcp.NewTool("section_hashes",
+		mcp.WithDescription("Get a SHA-256 hash of each top-level section of JSON file, independent of formatting and key order"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(hashesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		hashes, err := operations.SectionHashes(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(hashes, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addImportNDJSONTool adds the import_ndjson tool
+func addImportNDJSONTool(s *server.MCPServer) {
+	importTool := mcp.NewTool("import_ndjson",
+		mcp.WithDescription("Convert a newline-delimited JSON (NDJSON) file, one value per line, into a JSON array"),
+		mcp.WithString("ndjson_file",
+			mcp.Required(),
+			mcp.Description("Path to the NDJSON file to read"),
+		),
+		mcp.WithString("json_file",
+			mcp.Required(),
+			mcp.Description("Path of the JSON file to write; replaced if it exists"),
+		),
+		mcp.WithString("key",
+			mcp.Description("Key to nest the array under (optional; if omitted the array is the whole document, which most other tools can't read)"),
+		),
+		mcp.WithBoolean("skip_invalid",
+			mcp.Description("Skip and report malformed lines instead of aborting (optional, defaults to false)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(importTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ndjsonFile := mcp.ParseString(request, "ndjson_file", "")
+		if ndjsonFile == "" {
+			return mcp.NewToolResultError("Missing ndjson_file"), nil
+		}
+
+		jsonFile := mcp.ParseString(request, "json_file", "")
+		if jsonFile == "" {
+			return mcp.NewToolResultError("Missing json_file"), nil
+		}
+
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		key := mcp.ParseString(request, "key", "")
+		skipInvalid := mcp.ParseBoolean(request, "skip_invalid", false)
+
+		result, err := operations.ImportNDJSON(ndjsonFile, jsonFile, key, skipInvalid, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		message := fmt.Sprintf("✅ Imported %d record(s) from %s into %s", result.Records, ndjsonFile, jsonFile)
+		if len(result.Skipped) > 0 {
+			jsonResult, err := json.MarshalIndent(result.Skipped, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+			}
+			message += fmt.Sprintf("\nSkipped %d malformed line(s):\n%s", len(result.Skipped), string(jsonResult))
+		}
+
+		return mcp.NewToolResultText(message), nil
+	})
+}
+
+// addExportNDJSONTool adds the export_ndjson tool
+func addExportNDJSONTool(s *server.MCPServer) {
+	exportTool := mcp.NewTool("export_ndjson",
+		mcp.WithDescription("Write the elements of an array in JSON file as newline-delimited JSON (NDJSON), one minified element per line"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Description("Dot-notation path of the array (optional; if omitted the whole document must be an array)"),
+		),
+		mcp.WithString("output_file",
+			mcp.Required(),
+			mcp.Description("Path of the NDJSON file to write; replaced if it exists"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(exportTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		if outputFile == "" {
+			return mcp.NewToolResultError("Missing output_file"), nil
+		}
+
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		lines, err := operations.ExportNDJSON(filePath, keyPath, outputFile, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote %d line(s) to %s", lines, outputFile)), nil
+	})
+}
+
+// addEffectiveValueTool adds the effective_value tool
+func addEffectiveValueTool(s *server.MCPServer) {
+	effectiveTool := mcp.NewTool("effective_value",
+		mcp.WithDescription("Get the value a key resolves to when layered JSON files are deep-merged in order (later files win), and which file provides it"),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the key"),
+		),
+		mcp.WithArray("files",
+			mcp.Required(),
+			mcp.Description("JSON files from lowest to highest precedence, e.g. defaults.json then overrides.json"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(effectiveTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		files := request.GetStringSlice("files", nil)
+		if len(files) == 0 {
+			return mcp.NewToolResultError("Missing files"), nil
+		}
+
+		result, err := operations.EffectiveValue(keyPath, files)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addMissingKeysTool adds the missing_keys tool
+func addMissingKeysTool(s *server.MCPServer) {
+	missingTool := mcp.NewTool("missing_keys",
+		mcp.WithDescription("List which of the given key paths (e.g. translation keys used in source code) are absent from JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithArray("expected_paths",
+			mcp.Required(),
+			mcp.Description("Dot-notation paths that should exist"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(missingTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		expectedPaths := request.GetStringSlice("expected_paths", nil)
+		if len(expectedPaths) == 0 {
+			return mcp.NewToolResultError("Missing expected_paths"), nil
+		}
+
+		missing, err := operations.MissingKeys(filePath, expectedPaths)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(missing) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All %d expected key(s) exist in %s", len(expectedPaths), filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(missing, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %d key(s) missing from %s:\n%s", len(missing), filePath, string(jsonResult))), nil
+	})
+}
+
+// addUnusedKeysTool adds the unused_keys tool
+func addUnusedKeysTool(s *server.MCPServer) {
+	unusedTool := mcp.NewTool("unused_keys",
+		mcp.WithDescription("List leaf keys in JSON file that are not among the given used paths, e.g. translations no code refers to"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithArray("used_paths",
+			mcp.Required(),
+			mcp.Description("Dot-notation paths in use; a path naming an object covers every key under it"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("always_used",
+			mcp.Description("Path prefixes whose keys always count as used (optional)"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(unusedTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		usedPaths := request.GetStringSlice("used_paths", nil)
+		if usedPaths == nil {
+			return mcp.NewToolResultError("Missing used_paths"), nil
+		}
+
+		alwaysUsed := request.GetStringSlice("always_used", nil)
+
+		unused, err := operations.UnusedKeys(filePath, usedPaths, alwaysUsed)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(unused) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Every key in %s is used", filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(unused, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Found %d unused key(s) in %s:\n%s", len(unused), filePath, string(jsonResult))), nil
+	})
+}
+
+// addPreviewSaveTool adds the preview_save tool
+func addPreviewSaveTool(s *server.MCPServer) {
+	previewTool := mcp.NewTool("preview_save",
+		mcp.WithDescription("Show exactly what saving JSON file would write (indentation, HTML escaping, trailing newline) without writing it"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithNumber("indent",
+			mcp.Description("Spaces per indentation level (optional, defaults to 2 as used by edits; 0 gives compact output)"),
+		),
+	)
+
+	s.AddTool(previewTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		indent := mcp.ParseInt(request, "indent", 2)
+
+		preview, err := operations.PreviewSave(filePath, indent)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(preview), nil
+	})
+}
+
+// addApplyTemplateTool adds the apply_template tool
+func addApplyTemplateTool(s *server.MCPServer) {
+	templateTool := mcp.NewTool("apply_template",
+		mcp.WithDescription("Fill ${var} placeholders in a JSON template's strings and set the result at a path in JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to set the filled-in template at"),
+		),
+		mcp.WithObject("template",
+			mcp.Required(),
+			mcp.Description("JSON object whose string values may contain ${var} placeholders"),
+		),
+		mcp.WithObject("vars",
+			mcp.Description("Object mapping variable names to string values (optional; every placeholder must be defined)"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Replace a non-empty existing value at key_path (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(templateTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		template, ok := mcp.ParseArgument(request, "template", nil).(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Missing template (must be a JSON object)"), nil
+		}
+
+		vars := map[string]string{}
+		if rawVars, ok := mcp.ParseArgument(request, "vars", nil).(map[string]interface{}); ok {
+			for name, value := range rawVars {
+				str, ok := value.(string)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("Variable '%s' must be a string", name)), nil
+				}
+				vars[name] = str
+			}
+		}
+
+		force := mcp.ParseBoolean(request, "force", false)
+
+		applySaveOptions(request, filePath)
+		if err := operations.ApplyTemplate(filePath, keyPath, template, vars, force); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Applied template at '%s' in %s", keyPath, filePath)), nil
+	}))
+}
+
+// addPathsOfValueTool adds the paths_of_value tool
+func addPathsOfValueTool(s *server.MCPServer) {
+	pathsTool := mcp.NewTool("paths_of_value",
+		mcp.WithDescription("Find every path in JSON file whose value exactly equals the given value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("Value to look for (can be null, string, number, object, array, etc.)"),
+		),
+	)
+
+	s.AddTool(pathsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		// null is a legitimate value to look for, so check presence instead
+		value, ok := request.GetArguments()["value"]
+		if !ok {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		paths, err := operations.PathsOfValue(filePath, value)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(paths) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No paths in %s hold that value", filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(paths, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Found %d path(s):\n%s", len(paths), string(jsonResult))), nil
+	})
+}
+
+// addReorderToMatchTool adds the reorder_to_match tool
+func addReorderToMatchTool(s *server.MCPServer) {
+	reorderTool := mcp.NewTool("reorder_to_match",
+		mcp.WithDescription("Reorder the keys of a JSON file to follow the key order of a reference file, recursively. Keys missing from the reference stay at the end; values are not changed"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to reorder"),
+		),
+		mcp.WithString("reference_file",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file whose key order to follow"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(reorderTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		referenceFile := mcp.ParseString(request, "reference_file", "")
+		if referenceFile == "" {
+			return mcp.NewToolResultError("Missing reference_file"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		reordered, err := operations.ReorderToMatch(filePath, referenceFile)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if reordered == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Keys in %s already follow %s", filePath, referenceFile)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Reordered %d object(s) in %s to match %s", reordered, filePath, referenceFile)), nil
+	}))
+}
+
+// addSubtreeSpanTool adds the subtree_span tool
+func addSubtreeSpanTool(s *server.MCPServer) {
+	spanTool := mcp.NewTool("subtree_span",
+		mcp.WithDescription("Get the byte offset and line/column range of the source text holding a value in JSON file, for replacing exactly that span"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Description("Dot-notation path to the value, with array elements addressed by index (optional, defaults to the whole document)"),
+		),
+	)
+
+	s.AddTool(spanTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+
+		span, err := operations.SubtreeSpan(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(span, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addNestFlatKeysTool adds the nest_flat_keys tool
+func addNestFlatKeysTool(s *server.MCPServer) {
+	nestTool := mcp.NewTool("nest_flat_keys",
+		mcp.WithDescription("Expand top-level keys containing a delimiter (e.g. 'a.b.c') into nested objects, merging objects that meet and failing on any other collision"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("delimiter",
+			mcp.Description("Separator between the levels of a flat key (optional, defaults to '.')"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(nestTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		delimiter := mcp.ParseString(request, "delimiter", ".")
+
+		applySaveOptions(request, filePath)
+		nested, err := operations.NestFlatKeys(filePath, delimiter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(nested) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ No flat keys to nest in %s", filePath)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Nested %d flat key(s) in %s: %s", len(nested), filePath, strings.Join(nested, ", "))), nil
+	}))
+}
+
+// addGetAncestorsTool adds the get_ancestors tool
+func addGetAncestorsTool(s *server.MCPServer) {
+	ancestorsTool := mcp.NewTool("get_ancestors",
+		mcp.WithDescription("Get the value at every level of a path in JSON file, from the top-level key down to the key itself"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the key (e.g., 'auth.login.title')"),
+		),
+	)
+
+	s.AddTool(ancestorsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		ancestors, err := operations.GetAncestors(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(ancestors, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addAssertNumericRangeTool adds the assert_numeric_range tool
+func addAssertNumericRangeTool(s *server.MCPServer) {
+	assertTool := mcp.NewTool("assert_numeric_range",
+		mcp.WithDescription("Check that values at paths matching a '*' pattern are all numbers within a range, reporting out-of-range numbers and non-numeric values separately"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("path_glob",
+			mcp.Required(),
+			mcp.Description("Dot-notation path where '*' matches any key (e.g., 'services.*.timeout')"),
+		),
+		mcp.WithNumber("min",
+			mcp.Required(),
+			mcp.Description("Smallest allowed value (inclusive)"),
+		),
+		mcp.WithNumber("max",
+			mcp.Required(),
+			mcp.Description("Largest allowed value (inclusive)"),
+		),
+	)
+
+	s.AddTool(assertTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pathGlob := mcp.ParseString(request, "path_glob", "")
+		if pathGlob == "" {
+			return mcp.NewToolResultError("Missing path_glob"), nil
+		}
+
+		if mcp.ParseArgument(request, "min", nil) == nil {
+			return mcp.NewToolResultError("Missing min"), nil
+		}
+		if mcp.ParseArgument(request, "max", nil) == nil {
+			return mcp.NewToolResultError("Missing max"), nil
+		}
+		min := mcp.ParseFloat64(request, "min", 0)
+		max := mcp.ParseFloat64(request, "max", 0)
+
+		report, err := operations.AssertNumericRange(filePath, pathGlob, min, max)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(report.OutOfRange) == 0 && len(report.WrongType) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All %d value(s) matching '%s' are numbers between %v and %v", report.Checked, pathGlob, min, max)), nil
+		}
+
+		result := fmt.Sprintf("❌ %d of %d value(s) matching '%s' fail the range check [%v, %v]:\n", len(report.OutOfRange)+len(report.WrongType), report.Checked, pathGlob, min, max)
+		for _, violation := range report.OutOfRange {
+			result += fmt.Sprintf("• %s: %v is out of range\n", violation.Path, violation.Value)
+		}
+		for _, violation := range report.WrongType {
+			result += fmt.Sprintf("• %s: expected a number, found %s\n", violation.Path, violation.Type)
+		}
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addListKeysWithValuesTool adds the list_keys_with_values tool
+func addListKeysWithValuesTool(s *server.MCPServer) {
+	listTool := mcp.NewTool("list_keys_with_values",
+		mcp.WithDescription("List all keys at specified path in JSON file with each value's type and a short preview"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Description("Dot-notation path to list keys from (optional, defaults to root)"),
+		),
+		mcp.WithNumber("preview_length",
+			mcp.Description("Truncate scalar previews to this many characters (optional, defaults to 40; 0 for no limit)"),
+		),
+	)
+
+	s.AddTool(listTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		var keyPath *string
+		keyPathStr := mcp.ParseString(request, "key_path", "")
+		if keyPathStr != "" {
+			keyPath = &keyPathStr
+		}
+
+		previewLen := mcp.ParseInt(request, "preview_length", 40)
+
+		previews, err := operations.ListKeysWithValues(filePath, keyPath, previewLen)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		pathDesc := "at root level"
+		if keyPath != nil {
+			pathDesc = fmt.Sprintf("at '%s'", *keyPath)
+		}
+
+		result := fmt.Sprintf("Keys %s in %s:\n", pathDesc, filePath)
+		for _, preview := range previews {
+			result += fmt.Sprintf("• %s (%s): %s\n", preview.Key, preview.Type, preview.Preview)
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addCreateFileTool adds the create_file tool
+func addCreateFileTool(s *server.MCPServer) {
+	createTool := mcp.NewTool("create_file",
+		mcp.WithDescription("Create a new JSON file, creating parent directories as needed"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path of the JSON file to create"),
+		),
+		mcp.WithObject("initial",
+			mcp.Description("JSON object to write (optional, defaults to an empty object)"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace the file if it already exists (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(createTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		var initial map[string]interface{}
+		if raw := mcp.ParseArgument(request, "initial", nil); raw != nil {
+			object, ok := raw.(map[string]interface{})
+			if !ok {
+				return mcp.NewToolResultError("initial must be a JSON object"), nil
+			}
+			initial = object
+		}
+
+		overwrite := mcp.ParseBoolean(request, "overwrite", false)
+
+		applySaveOptions(request, filePath)
+		if err := operations.CreateFile(filePath, initial, overwrite); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Created %s", filePath)), nil
+	}))
+}
+
+// addFilesEqualTool adds the files_equal tool
+func addFilesEqualTool(s *server.MCPServer) {
+	equalTool := mcp.NewTool("files_equal",
+		mcp.WithDescription("Check whether two JSON files hold the same document, ignoring formatting and key order"),
+		mcp.WithString("file_a",
+			mcp.Required(),
+			mcp.Description("Path to the first JSON file"),
+		),
+		mcp.WithString("file_b",
+			mcp.Required(),
+			mcp.Description("Path to the second JSON file"),
+		),
+	)
+
+	s.AddTool(equalTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fileA := mcp.ParseString(request, "file_a", "")
+		if fileA == "" {
+			return mcp.NewToolResultError("Missing file_a"), nil
+		}
+
+		fileB := mcp.ParseString(request, "file_b", "")
+		if fileB == "" {
+			return mcp.NewToolResultError("Missing file_b"), nil
+		}
+
+		comparison, err := operations.FilesEqual(fileA, fileB)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if comparison.Equal {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ %s and %s are equal", fileA, fileB)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %s and %s differ at %d path(s)", fileA, fileB, comparison.DifferingPaths)), nil
+	})
+}
+
+// addTypeDiffTool adds the type_diff tool
+func addTypeDiffTool(s *server.MCPServer) {
+	typeDiffTool := mcp.NewTool("type_diff",
+		mcp.WithDescription("List paths present in two JSON files whose values have different JSON types (e.g. a number in one, a string in the other)"),
+		mcp.WithString("file_a",
+			mcp.Required(),
+			mcp.Description("Path to the first JSON file"),
+		),
+		mcp.WithString("file_b",
+			mcp.Required(),
+			mcp.Description("Path to the second JSON file"),
+		),
+	)
+
+	s.AddTool(typeDiffTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fileA := mcp.ParseString(request, "file_a", "")
+		if fileA == "" {
+			return mcp.NewToolResultError("Missing file_a"), nil
+		}
+
+		fileB := mcp.ParseString(request, "file_b", "")
+		if fileB == "" {
+			return mcp.NewToolResultError("Missing file_b"), nil
+		}
+
+		differences, err := operations.TypeDiff(fileA, fileB)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(differences) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Shared paths in %s and %s have the same types", fileA, fileB)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(differences, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Found %d type difference(s):\n%s", len(differences), string(jsonResult))), nil
+	})
+}
+
+// addGenerateTSInterfaceTool adds the generate_ts_interface tool
+func addGenerateTSInterfaceTool(s *server.MCPServer) {
+	tsTool := mcp.NewTool("generate_ts_interface",
+		mcp.WithDescription("Generate TypeScript interfaces describing the structure of JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("root_name",
+			mcp.Description("Name of the top-level interface (optional, defaults to 'Root')"),
+		),
+	)
+
+	s.AddTool(tsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		rootName := mcp.ParseString(request, "root_name", "Root")
+
+		source, err := operations.GenerateTSInterface(filePath, rootName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(source), nil
+	})
+}
+
+// addDeepestPathTool adds the deepest_path tool
+func addDeepestPathTool(s *server.MCPServer) {
+	deepestTool := mcp.NewTool("deepest_path",
+		mcp.WithDescription("Find the most deeply nested path in JSON file and its depth"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(deepestTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		deepest, err := operations.DeepestPath(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if deepest.Depth == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("%s is an empty document", filePath)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Deepest path in %s: %s (depth %d)", filePath, deepest.Path, deepest.Depth)), nil
+	})
+}
+
+// addSetMatchingTool adds the set_matching tool
+func addSetMatchingTool(s *server.MCPServer) {
+	setMatchingTool := mcp.NewTool("set_matching",
+		mcp.WithDescription("Set every existing leaf value in JSON file whose path matches a dot-notation pattern with '*' wildcard segments"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Dot-notation pattern where '*' matches any key (e.g., 'features.*.enabled')"),
+		),
+		mcp.WithObject("value",
+			mcp.Required(),
+			mcp.Description("Value to set (can be null, string, number, object, array, etc.)"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Preview the leaves that would change without changing them (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(setMatchingTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pattern := mcp.ParseString(request, "pattern", "")
+		if pattern == "" {
+			return mcp.NewToolResultError("Missing pattern"), nil
+		}
+
+		// null is a legitimate value to set, so check presence instead
+		value, ok := request.GetArguments()["value"]
+		if !ok {
+			return mcp.NewToolResultError("Missing value"), nil
+		}
+
+		dryRun := mcp.ParseBoolean(request, "dry_run", false)
+
+		applySaveOptions(request, filePath)
+		paths, err := operations.SetMatching(filePath, pattern, value, dryRun)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonPaths, err := json.MarshalIndent(paths, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		if dryRun {
+			return mcp.NewToolResultText(fmt.Sprintf("Dry run: %d leaf value(s) matching '%s' would change in %s\n%s", len(paths), pattern, filePath, string(jsonPaths))), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Set %d leaf value(s) matching '%s' in %s\n%s", len(paths), pattern, filePath, string(jsonPaths))), nil
+	}))
+}
+
+// addCompletenessTool adds the completeness tool
+func addCompletenessTool(s *server.MCPServer) {
+	completenessTool := mcp.NewTool("completeness",
+		mcp.WithDescription("Compute the percentage of a reference JSON file's leaf keys that a target file translates with non-empty values"),
+		mcp.WithString("reference_file",
+			mcp.Required(),
+			mcp.Description("Path to the reference JSON file (e.g., the source locale)"),
+		),
+		mcp.WithString("target_file",
+			mcp.Required(),
+			mcp.Description("Path to the target JSON file (e.g., a translated locale)"),
+		),
+	)
+
+	s.AddTool(completenessTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		referenceFile := mcp.ParseString(request, "reference_file", "")
+		if referenceFile == "" {
+			return mcp.NewToolResultError("Missing reference_file"), nil
+		}
+
+		targetFile := mcp.ParseString(request, "target_file", "")
+		if targetFile == "" {
+			return mcp.NewToolResultError("Missing target_file"), nil
+		}
+
+		report, err := operations.Completeness(referenceFile, targetFile)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s is %.2f%% complete against %s:\n%s", targetFile, report.Percentage, referenceFile, string(jsonResult))), nil
+	})
+}
+
+// addGraftSubtreeTool adds the graft_subtree tool
+func addGraftSubtreeTool(s *server.MCPServer) {
+	graftTool := mcp.NewTool("graft_subtree",
+		mcp.WithDescription("Set a path in JSON file to the contents of another JSON file, or a part of it, creating the path if needed"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to graft into"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to set the grafted value at"),
+		),
+		mcp.WithString("source_file",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to graft from"),
+		),
+		mcp.WithString("source_key_path",
+			mcp.Description("Dot-notation path of the part of source_file to graft (optional, defaults to the whole file)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(graftTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		sourceFile := mcp.ParseString(request, "source_file", "")
+		if sourceFile == "" {
+			return mcp.NewToolResultError("Missing source_file"), nil
+		}
+
+		sourceKeyPath := mcp.ParseString(request, "source_key_path", "")
+
+		applySaveOptions(request, filePath)
+		if err := operations.GraftSubtree(filePath, keyPath, sourceFile, sourceKeyPath); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		source := sourceFile
+		if sourceKeyPath != "" {
+			source = fmt.Sprintf("'%s' from %s", sourceKeyPath, sourceFile)
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Grafted %s at '%s' in %s", source, keyPath, filePath)), nil
+	}))
+}
+
+// addCheckStringHealthTool adds the check_string_health tool
+func addCheckStringHealthTool(s *server.MCPServer) {
+	healthTool := mcp.NewTool("check_string_health",
+		mcp.WithDescription("Find string values in JSON file with invalid UTF-8, control characters, or leading/trailing whitespace"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithBoolean("fix",
+			mcp.Description("Strip control characters from every string before checking (optional, defaults to false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(healthTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		applySaveOptions(request, filePath)
+
+		fixed := ""
+		if mcp.ParseBoolean(request, "fix", false) {
+			count, err := operations.StripControlCharacters(filePath)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+			}
+			fixed = fmt.Sprintf("Stripped control characters from %d string(s)\n", count)
+		}
+
+		issues, err := operations.CheckStringHealth(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(issues) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("%s✅ All string values in %s are clean", fixed, filePath)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%sFound %d string value(s) with issues:\n%s", fixed, len(issues), string(jsonResult))), nil
+	}))
+}
+
+// addAssertArrayUniqueTool adds the assert_array_unique tool
+func addAssertArrayUniqueTool(s *server.MCPServer) {
+	uniqueTool := mcp.NewTool("assert_array_unique",
+		mcp.WithDescription("Check that no two elements of an array in JSON file share the same value for a field, or are identical"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the array"),
+		),
+		mcp.WithString("unique_field",
+			mcp.Description("Dot-notation path within each element that must be unique, e.g. 'id' (optional, defaults to comparing whole elements)"),
+		),
+	)
+
+	s.AddTool(uniqueTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		uniqueField := mcp.ParseString(request, "unique_field", "")
+
+		report, err := operations.AssertArrayUnique(filePath, keyPath, uniqueField)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		if report.Passed {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Elements of '%s' are unique\n%s", keyPath, string(jsonResult))), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %d value(s) are repeated in '%s':\n%s", len(report.Duplicates), keyPath, string(jsonResult))), nil
+	})
+}
+
+// addProbeKeyTool adds the probe_key tool
+func addProbeKeyTool(s *server.MCPServer) {
+	probeTool := mcp.NewTool("probe_key",
+		mcp.WithDescription("Look up a key in JSON file, reporting whether it is missing, present and null, or present with a value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to probe"),
+		),
+	)
+
+	s.AddTool(probeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		result, err := operations.Probe(filePath, keyPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		state := "is missing"
+		switch {
+		case result.IsNull:
+			state = "is present and null"
+		case result.Exists:
+			state = "is present with a value"
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Key '%s' %s in %s:\n%s", keyPath, state, filePath, string(jsonResult))), nil
+	})
+}
+
+// addFillMissingTool adds the fill_missing tool
+func addFillMissingTool(s *server.MCPServer) {
+	fillTool := mcp.NewTool("fill_missing",
+		mcp.WithDescription("Add every leaf key present in a reference JSON file but missing from JSON file, copying the reference values as placeholders"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to fill (e.g., a translated locale)"),
+		),
+		mcp.WithString("reference_file",
+			mcp.Required(),
+			mcp.Description("Path to the reference JSON file (e.g., the source locale)"),
+		),
+		mcp.WithString("mark_prefix",
+			mcp.Description("Text prepended to each copied string, e.g. '[TODO] ' (optional, defaults to none)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(fillTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		referenceFile := mcp.ParseString(request, "reference_file", "")
+		if referenceFile == "" {
+			return mcp.NewToolResultError("Missing reference_file"), nil
+		}
+
+		markPrefix := mcp.ParseString(request, "mark_prefix", "")
+
+		applySaveOptions(request, filePath)
+		added, err := operations.FillMissingFromReference(filePath, referenceFile, markPrefix)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(added) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ %s has no keys missing from %s", filePath, referenceFile)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(added, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Added %d missing key(s) to %s:\n%s", len(added), filePath, string(jsonResult))), nil
+	}))
+}
+
+// addRenameKeyEverywhereTool adds the rename_key_everywhere tool
+func addRenameKeyEverywhereTool(s *server.MCPServer) {
+	renameTool := mcp.NewTool("rename_key_everywhere",
+		mcp.WithDescription("Rename every object key with a given name, at any depth, in JSON file"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("old_name",
+			mcp.Required(),
+			mcp.Description("Key name to rename (a single key, not a path)"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("New key name; objects that already have it are skipped and reported as conflicts"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(renameTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		oldName := mcp.ParseString(request, "old_name", "")
+		if oldName == "" {
+			return mcp.NewToolResultError("Missing old_name"), nil
+		}
+
+		newName := mcp.ParseString(request, "new_name", "")
+		if newName == "" {
+			return mcp.NewToolResultError("Missing new_name"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		report, err := operations.RenameKeyEverywhere(filePath, oldName, newName)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Renamed %d '%s' key(s) to '%s' in %s (%d conflict(s)):\n%s", len(report.Renamed), oldName, newName, filePath, len(report.Conflicts), string(jsonResult))), nil
+	}))
+}
+
+// addLongestStringsTool adds the longest_strings tool
+func addLongestStringsTool(s *server.MCPServer) {
+	longestTool := mcp.NewTool("longest_strings",
+		mcp.WithDescription("List the longest string values in JSON file by character count"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithNumber("top_n",
+			mcp.Description("Number of entries to return (optional, defaults to 10)"),
+		),
+	)
+
+	s.AddTool(longestTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		topN := mcp.ParseInt(request, "top_n", 10)
+
+		lengths, err := operations.LongestStrings(filePath, topN)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		result := fmt.Sprintf("Longest strings in %s:\n", filePath)
+		for _, entry := range lengths {
+			result += fmt.Sprintf("• %s: %d characters\n", entry.Path, entry.Length)
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addSetWhereTool adds the set_where tool
+func addSetWhereTool(s *server.MCPServer) {
+	setWhereTool := mcp.NewTool("set_where",
+		mcp.WithDescription("Set a field on every object in an array in JSON file whose match field equals a value"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("key_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to the array of objects"),
+		),
+		mcp.WithString("match_field",
+			mcp.Required(),
+			mcp.Description("Dot-notation field within each element to compare (e.g., 'level')"),
+		),
+		mcp.WithObject("match_value",
+			mcp.Required(),
+			mcp.Description("Value the match field must equal (can be string, number, object, etc.)"),
+		),
+		mcp.WithString("set_field",
+			mcp.Required(),
+			mcp.Description("Dot-notation field within each matching element to set (e.g., 'color')"),
+		),
+		mcp.WithObject("set_value",
+			mcp.Required(),
+			mcp.Description("Value to set (can be null, string, number, object, array, etc.)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(setWhereTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		keyPath := mcp.ParseString(request, "key_path", "")
+		if keyPath == "" {
+			return mcp.NewToolResultError("Missing key_path"), nil
+		}
+
+		matchField := mcp.ParseString(request, "match_field", "")
+		if matchField == "" {
+			return mcp.NewToolResultError("Missing match_field"), nil
+		}
+
+		matchValue := mcp.ParseArgument(request, "match_value", nil)
+		if matchValue == nil {
+			return mcp.NewToolResultError("Missing match_value"), nil
+		}
+
+		setField := mcp.ParseString(request, "set_field", "")
+		if setField == "" {
+			return mcp.NewToolResultError("Missing set_field"), nil
+		}
+
+		// null is a legitimate value to set, so check presence instead
+		setValue, ok := request.GetArguments()["set_value"]
+		if !ok {
+			return mcp.NewToolResultError("Missing set_value"), nil
+		}
+
+		applySaveOptions(request, filePath)
+		updated, err := operations.SetWhere(filePath, keyPath, matchField, matchValue, setField, setValue)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Updated '%s' on %d element(s) of '%s' in %s", setField, updated, keyPath, filePath)), nil
+	}))
+}
+
+// addIsUsableTool adds the is_usable tool
+func addIsUsableTool(s *server.MCPServer) {
+	usableTool := mcp.NewTool("is_usable",
+		mcp.WithDescription("Check in one call whether a file exists, contains valid JSON, and has an object at its root"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(usableTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		usability, err := operations.IsUsable(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(usability, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		status := "✅ usable"
+		switch {
+		case !usability.Exists:
+			status = "❌ not usable: it does not exist"
+		case !usability.ValidJSON:
+			status = "❌ not usable: it is not valid JSON"
+		case !usability.IsObject:
+			status = "❌ not usable: its root is not an object"
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("%s is %s\n%s", filePath, status, string(jsonResult))), nil
+	})
+}
+
+// addBatchEditTool adds the batch_edit tool
+func addBatchEditTool(s *server.MCPServer) {
+	batchTool := mcp.NewTool("batch_edit",
+		mcp.WithDescription("Apply a list of add/update/remove/rename instructions to JSON file with a single save, reporting the outcome of each; nothing is written unless all succeed"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithArray("instructions",
+			mcp.Required(),
+			mcp.Description("Ordered list of instructions, each an object with 'action' (add, update, remove or rename), 'path', and 'value' (add/update) or 'new_path' (rename)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(batchTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		rawInstructions, ok := mcp.ParseArgument(request, "instructions", nil).([]interface{})
+		if !ok {
+			return mcp.NewToolResultError("Missing instructions (must be an array)"), nil
+		}
+
+		// Items that aren't objects are reported as invalid along with the rest
+		instructions := make([]map[string]interface{}, len(rawInstructions))
+		for i, raw := range rawInstructions {
+			instruction, ok := raw.(map[string]interface{})
+			if !ok {
+				instruction = map[string]interface{}{}
+			}
+			instructions[i] = instruction
+		}
+
+		applySaveOptions(request, filePath)
+		results, err := operations.BatchEdit(filePath, instructions)
+		jsonResult, jsonErr := json.MarshalIndent(results, "", "  ")
+		if jsonErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", jsonErr)), nil
+		}
+
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s\n%s", err.Error(), string(jsonResult))), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Applied %d instruction(s) to %s:\n%s", len(results), filePath, string(jsonResult))), nil
+	}))
+}
+
+// addDiagnoseJSONTool adds the diagnose_json tool
+func addDiagnoseJSONTool(s *server.MCPServer) {
+	diagnoseTool := mcp.NewTool("diagnose_json",
+		mcp.WithDescription("Find trailing commas, single-quoted strings and unquoted keys in JSON file, with the location of each and a suggested fix"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(diagnoseTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		diagnosis, err := operations.DiagnoseJSON(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(diagnosis.Issues) == 0 {
+			if diagnosis.Valid {
+				return mcp.NewToolResultText(fmt.Sprintf("✅ %s is valid JSON", filePath)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("No common mistakes found, but %s is not valid JSON; use validate_json for details", filePath)), nil
+		}
+
+		result := fmt.Sprintf("Found %d common mistake(s) in %s:\n", len(diagnosis.Issues), filePath)
+		for _, issue := range diagnosis.Issues {
+			result += fmt.Sprintf("• Line %d, column %d: %s (%s)\n", issue.Line, issue.Column, issue.Message, issue.Suggestion)
+		}
+
+		return mcp.NewToolResultText(result), nil
+	})
+}
+
+// addRepairJSONTool adds the repair_json tool
+func addRepairJSONTool(s *server.MCPServer) {
+	repairTool := mcp.NewTool("repair_json",
+		mcp.WithDescription("Repair trailing commas, single-quoted strings and unquoted keys in JSON file, reporting every change; the source file is never modified"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file to repair"),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the repaired copy (optional; the result is only returned if omitted)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(repairTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		result, err := operations.RepairJSON(filePath, outputFile, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		changes := ""
+		for _, change := range result.Changes {
+			changes += fmt.Sprintf("• Line %d, column %d: %s (%s)\n", change.Line, change.Column, change.Message, change.Suggestion)
+		}
+
+		if outputFile != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote %s with %d repair(s):\n%s", outputFile, len(result.Changes), changes)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Made %d repair(s):\n%s\n%s", len(result.Changes), changes, result.Content)), nil
+	})
+}
+
+// addGroupPathsByTypeTool adds the group_paths_by_type tool
+func addGroupPathsByTypeTool(s *server.MCPServer) {
+	groupTool := mcp.NewTool("group_paths_by_type",
+		mcp.WithDescription("List every path in JSON file grouped by value type (string, number, boolean, null, object, array)"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(groupTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		groups, err := operations.GroupPathsByType(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addCheckPluralFormsTool adds the check_plural_forms tool
+func addCheckPluralFormsTool(s *server.MCPServer) {
+	pluralTool := mcp.NewTool("check_plural_forms",
+		mcp.WithDescription("Check that plural-form objects (keys like one, few, other) in JSON file have every CLDR plural category the locale requires"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("locale",
+			mcp.Required(),
+			mcp.Description("Locale of the file, e.g. 'en', 'pl' or 'pt-BR'; only the language part is used"),
+		),
+	)
+
+	s.AddTool(pluralTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		locale := mcp.ParseString(request, "locale", "")
+		if locale == "" {
+			return mcp.NewToolResultError("Missing locale"), nil
+		}
+
+		issues, err := operations.CheckPluralForms(filePath, locale)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(issues) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All plural forms in %s have the categories %s requires", filePath, locale)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("❌ %d plural form(s) are missing categories required by %s:\n%s", len(issues), locale, string(jsonResult))), nil
+	})
+}
+
+// addKeyMatrixTool adds the key_matrix tool
+func addKeyMatrixTool(s *server.MCPServer) {
+	matrixTool := mcp.NewTool("key_matrix",
+		mcp.WithDescription("Compare the leaf keys of several JSON files (e.g. a set of locales) and list every key missing from some of them"),
+		mcp.WithArray("files",
+			mcp.Required(),
+			mcp.Description("JSON files to compare, e.g. en.json, de.json, fr.json"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(matrixTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		files := request.GetStringSlice("files", nil)
+		if len(files) == 0 {
+			return mcp.NewToolResultError("Missing files"), nil
+		}
+
+		matrix, err := operations.MultiFileKeyMatrix(files)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(matrix.Missing) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All %d file(s) have the same %d key(s)", len(files), matrix.TotalPaths)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(matrix, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%d of %d key(s) are missing from some files:\n%s", len(matrix.Missing), matrix.TotalPaths, string(jsonResult))), nil
+	})
+}
+
+// addMapValuesTool adds the map_values tool
+func addMapValuesTool(s *server.MCPServer) {
+	mapTool := mcp.NewTool("map_values",
+		mcp.WithDescription("Apply a simple operation (add, mul, upper, lower, replace) to every leaf value in JSON file matching a path pattern"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("op",
+			mcp.Required(),
+			mcp.Description("Operation: add or mul (numbers), upper, lower or replace (strings)"),
+			mcp.Enum("add", "mul", "upper", "lower", "replace"),
+		),
+		mcp.WithObject("operand",
+			mcp.Description("Operand: a number for add and mul, an object like {\"old\": \"http:\", \"new\": \"https:\"} for replace, unused for upper and lower"),
+		),
+		mcp.WithString("path_glob",
+			mcp.Description("Dot-notation pattern where '*' matches any key, limiting which values are mapped (optional, defaults to the whole file)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(mapTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		op := mcp.ParseString(request, "op", "")
+		if op == "" {
+			return mcp.NewToolResultError("Missing op"), nil
+		}
+
+		operand := mcp.ParseArgument(request, "operand", nil)
+		pathGlob := mcp.ParseString(request, "path_glob", "")
+
+		applySaveOptions(request, filePath)
+		result, err := operations.MapValues(filePath, pathGlob, op, operand)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		message := fmt.Sprintf("✅ Applied %s to %d value(s)", op, result.Changed)
+		if len(result.Incompatible) == 0 {
+			return mcp.NewToolResultText(message), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(result.Incompatible, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s; skipped %d value(s) of the wrong type:\n%s", message, len(result.Incompatible), string(jsonResult))), nil
+	}))
+}
+
+// addStrictKeyParityTool adds the strict_key_parity tool
+func addStrictKeyParityTool(s *server.MCPServer) {
+	parityTool := mcp.NewTool("strict_key_parity",
+		mcp.WithDescription("Check that several JSON files (e.g. every locale) have exactly the same set of leaf keys, listing each file's missing and extra keys if not"),
+		mcp.WithArray("files",
+			mcp.Required(),
+			mcp.Description("JSON files to compare, e.g. en.json, de.json, fr.json"),
+			mcp.WithStringItems(),
+		),
+	)
+
+	s.AddTool(parityTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		files := request.GetStringSlice("files", nil)
+		if len(files) == 0 {
+			return mcp.NewToolResultError("Missing files"), nil
+		}
+
+		report, err := operations.StrictKeyParity(files)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if report.Identical {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ All %d file(s) have identical keys", len(files))), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(report.Files, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("❌ Key sets differ in %d of %d file(s):\n%s", len(report.Files), len(files), string(jsonResult))), nil
+	})
+}
+
+// addGenerateMockTool adds the generate_mock tool
+func addGenerateMockTool(s *server.MCPServer) {
+	mockTool := mcp.NewTool("generate_mock",
+		mcp.WithDescription("Generate mock data with the same keys and nesting as a JSON file but placeholder values (\"string\", 0, false)"),
+		mcp.WithString("template_file",
+			mcp.Required(),
+			mcp.Description("JSON file whose structure the mock copies"),
+		),
+		mcp.WithString("output_file",
+			mcp.Description("Where to write the mock document (optional; the result is only returned if omitted)"),
+		),
+		withCreateDirs(),
+	)
+
+	s.AddTool(mockTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		templateFile := mcp.ParseString(request, "template_file", "")
+		if templateFile == "" {
+			return mcp.NewToolResultError("Missing template_file"), nil
+		}
+
+		outputFile := mcp.ParseString(request, "output_file", "")
+		createDirs := mcp.ParseBoolean(request, "create_dirs", false)
+
+		mock, err := operations.GenerateMock(templateFile, outputFile, createDirs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if outputFile != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ Wrote mock of %s to %s", templateFile, outputFile)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(mock, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonResult)), nil
+	})
+}
+
+// addBenchmarkFileTool adds the benchmark_file tool
+func addBenchmarkFileTool(s *server.MCPServer) {
+	benchmarkTool := mcp.NewTool("benchmark_file",
+		mcp.WithDescription("Measure how long loading, walking and saving JSON file takes and roughly how much memory it needs, without modifying it"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+	)
+
+	s.AddTool(benchmarkTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		result, err := operations.Benchmark(filePath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s\nFile size: %d bytes\nValues: %d\nParse time: %.3fs\nWalk time: %.3fs\nSerialize time: %.3fs\nWrite time: %.3fs\nApprox. memory: %d bytes",
+			filePath, result.FileSize, result.Values, result.ParseTime, result.WalkTime, result.SerializeTime, result.WriteTime, result.MemoryBytes)), nil
+	})
+}
+
+// addCopyWithinTool adds the copy_within tool
+func addCopyWithinTool(s *server.MCPServer) {
+	copyTool := mcp.NewTool("copy_within",
+		mcp.WithDescription("Copy the value at one path to another path in the same JSON file, keeping the original"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("source_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path of the value to copy"),
+		),
+		mcp.WithString("dest_path",
+			mcp.Required(),
+			mcp.Description("Dot-notation path to copy the value to; missing parent objects are created"),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Replace an existing value at dest_path (default: false)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(copyTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		sourcePath := mcp.ParseString(request, "source_path", "")
+		if sourcePath == "" {
+			return mcp.NewToolResultError("Missing source_path"), nil
+		}
+
+		destPath := mcp.ParseString(request, "dest_path", "")
+		if destPath == "" {
+			return mcp.NewToolResultError("Missing dest_path"), nil
+		}
+
+		overwrite := mcp.ParseBoolean(request, "overwrite", false)
+
+		applySaveOptions(request, filePath)
+		if err := operations.CopyWithin(filePath, sourcePath, destPath, overwrite); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Copied '%s' to '%s' in %s", sourcePath, destPath, filePath)), nil
+	}))
+}
+
+// addFindKeysByPatternTool adds the find_keys_by_pattern tool
+func addFindKeysByPatternTool(s *server.MCPServer) {
+	findTool := mcp.NewTool("find_keys_by_pattern",
+		mcp.WithDescription("Find every key in JSON file, at any depth, whose name matches a regular expression"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Regular expression (Go RE2 syntax) matched against key names, e.g. ^on[A-Z] for event handlers"),
+		),
+	)
+
+	s.AddTool(findTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pattern := mcp.ParseString(request, "pattern", "")
+		if pattern == "" {
+			return mcp.NewToolResultError("Missing pattern"), nil
+		}
+
+		paths, err := operations.FindKeysByPattern(filePath, pattern)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(paths) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No keys in %s match %s", filePath, pattern)), nil
+		}
+
+		jsonResult, err := json.MarshalIndent(paths, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error serializing result: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%d key(s) match %s:\n%s", len(paths), pattern, string(jsonResult))), nil
+	})
+}
+
+// addCoerceBooleansTool adds the coerce_booleans tool
+func addCoerceBooleansTool(s *server.MCPServer) {
+	coerceTool := mcp.NewTool("coerce_booleans",
+		mcp.WithDescription("Convert boolean-like strings in JSON file to real booleans: true/yes/y/on/1 and false/no/n/off/0, ignoring case"),
+		mcp.WithString("file_path",
+			mcp.Required(),
+			mcp.Description("Path to the JSON file"),
+		),
+		mcp.WithString("path_glob",
+			mcp.Description("Dot-notation pattern where '*' matches any key, limiting which values are converted (optional, defaults to the whole file)"),
+		),
+		withEscapeHTML(),
+		withVerifyAfterWrite(),
+		withShowDiff(),
+	)
+
+	s.AddTool(coerceTool, showingDiff(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		filePath := mcp.ParseString(request, "file_path", "")
+		if filePath == "" {
+			return mcp.NewToolResultError("Missing file_path"), nil
+		}
+
+		pathGlob := mcp.ParseString(request, "path_glob", "")
+
+		applySaveOptions(request, filePath)
+		converted, err := operations.CoerceBooleans(filePath, pathGlob)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("❌ Error: %s", err.Error())), nil
+		}
+
+		if len(converted) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("✅ No boolean-like strings found in %s", filePath)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("✅ Converted %d value(s) to booleans: %s", len(converted), strings.Join(converted, ", "))), nil
+	}))
 }
\ No newline at end of file
@@ -1,9 +1,26 @@
 package operations
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"jsonmcptool/internal/jsonhandler"
 	"jsonmcptool/internal/pathresolver"
@@ -20,12 +37,56 @@ var (
 	ErrRemoveKeyError = errors.New("REMOVE_KEY_ERROR")
 	ErrRenameKeyError = errors.New("RENAME_KEY_ERROR")
 	ErrSameKey       = errors.New("SAME_KEY")
+	ErrNotContainer  = errors.New("NOT_CONTAINER")
+	ErrClearKeyError = errors.New("CLEAR_KEY_ERROR")
+	ErrTypeMismatch  = errors.New("TYPE_MISMATCH")
+	ErrTransaction   = errors.New("TRANSACTION_ERROR")
+	ErrNotArray      = errors.New("NOT_ARRAY")
+	ErrIndexOutOfRange = errors.New("INDEX_OUT_OF_RANGE")
+	ErrArrayError    = errors.New("ARRAY_ERROR")
+	ErrInvalidLabel  = errors.New("INVALID_LABEL")
+	ErrSnapshotExists   = errors.New("SNAPSHOT_EXISTS")
+	ErrSnapshotNotFound = errors.New("SNAPSHOT_NOT_FOUND")
+	ErrSnapshotError    = errors.New("SNAPSHOT_ERROR")
+	ErrNothingToUndo    = errors.New("NOTHING_TO_UNDO")
+	ErrNothingToRedo    = errors.New("NOTHING_TO_REDO")
+	ErrHistoryError     = errors.New("HISTORY_ERROR")
+	ErrWrapKeyError     = errors.New("WRAP_KEY_ERROR")
+	ErrPromoteError     = errors.New("PROMOTE_ERROR")
+	ErrUndefinedVar     = errors.New("UNDEFINED_VARIABLE")
+	ErrInvalidStyle     = errors.New("INVALID_STYLE")
+	ErrInvalidPattern   = errors.New("INVALID_PATTERN")
+	ErrRedactError      = errors.New("REDACT_ERROR")
+	ErrPickError        = errors.New("PICK_ERROR")
+	ErrOmitError        = errors.New("OMIT_ERROR")
+	ErrInvalidPolicy    = errors.New("INVALID_POLICY")
+	ErrNormalizeError   = errors.New("NORMALIZE_ERROR")
+	ErrInvalidTransform = errors.New("INVALID_TRANSFORM")
+	ErrTransformError   = errors.New("TRANSFORM_ERROR")
+	ErrInvalidConvention = errors.New("INVALID_CONVENTION")
+	ErrSplitError        = errors.New("SPLIT_ERROR")
+	ErrCombineError      = errors.New("COMBINE_ERROR")
+	ErrRefError          = errors.New("REF_ERROR")
+	ErrCircularRef       = errors.New("CIRCULAR_REFERENCE")
+	ErrInvalidType       = errors.New("INVALID_TYPE")
+	ErrNDJSONError       = errors.New("NDJSON_ERROR")
+	ErrReorderError      = errors.New("REORDER_ERROR")
+	ErrNestError         = errors.New("NEST_ERROR")
+	ErrInvalidRange      = errors.New("INVALID_RANGE")
+	ErrFileExists        = errors.New("FILE_EXISTS")
+	ErrCreateError       = errors.New("CREATE_ERROR")
+	ErrInvalidName       = errors.New("INVALID_NAME")
+	ErrGraftError        = errors.New("GRAFT_ERROR")
+	ErrRepairError       = errors.New("REPAIR_ERROR")
+	ErrInvalidLocale     = errors.New("INVALID_LOCALE")
+	ErrMockError         = errors.New("MOCK_ERROR")
+	ErrCopyError         = errors.New("COPY_ERROR")
 )
 
 // GetKey retrieves value by dot-notation key path
 func GetKey(filePath, keyPath string) (interface{}, error) {
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(keyPath, true)
 	if err != nil {
 		return nil, err
 	}
@@ -44,21 +105,79 @@ func GetKey(filePath, keyPath string) (interface{}, error) {
 	return value, nil
 }
 
-// AddKey adds new key-value pair
-func AddKey(filePath, keyPath string, value interface{}) error {
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
+// GetKeyExpanded retrieves the value at keyPath with ${VAR} and $VAR references in its
+// string leaves expanded from the process environment. Undefined variables are left exactly
+// as written, or reported as UNDEFINED_VARIABLE when strict is set. A $ not followed by a
+// variable name, as in "$5" or "$$", is plain text. The file is not modified.
+func GetKeyExpanded(filePath, keyPath string, strict bool) (interface{}, error) {
+	value, err := GetKey(filePath, keyPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	undefined := map[string]bool{}
+	expanded := expandEnvInValue(value, undefined)
+
+	if strict && len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w: Variables %s are not set", ErrUndefinedVar, strings.Join(names, ", "))
+	}
+
+	return expanded, nil
+}
+
+// envReferencePattern matches ${VAR} and $VAR references to environment variables
+var envReferencePattern = regexp.MustCompile(`\$(?:\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)`)
+
+// expandEnvInValue returns a copy of value with environment references expanded in
+// every string, recording the names of unset variables in undefined
+func expandEnvInValue(value interface{}, undefined map[string]bool) interface{} {
+	switch v := value.(type) {
+	case string:
+		return envReferencePattern.ReplaceAllStringFunc(v, func(reference string) string {
+			name := strings.Trim(reference, "${}")
+			if resolved, ok := os.LookupEnv(name); ok {
+				return resolved
+			}
+			undefined[name] = true
+			return reference
+		})
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			expanded[key] = expandEnvInValue(child, undefined)
+		}
+		return expanded
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, child := range v {
+			expanded[i] = expandEnvInValue(child, undefined)
+		}
+		return expanded
 	}
+	return value
+}
+
+// AddKey adds new key-value pair
+func AddKey(filePath, keyPath string, value interface{}) error {
+	return editFile(filePath, ErrAddKeyError, func(data map[string]interface{}) error {
+		return addKeyInData(data, filePath, keyPath, value)
+	})
+}
 
+// addKeyInData adds a new key-value pair to already loaded data
+func addKeyInData(data map[string]interface{}, filePath, keyPath string, value interface{}) error {
 	// Check if key already exists
 	if pathresolver.KeyExists(data, keyPath) {
 		return fmt.Errorf("%w: Key '%s' already exists in %s", ErrKeyExists, keyPath, filePath)
 	}
 
 	// Create the nested path and set the value
-	err = pathresolver.SetValueAtPath(data, keyPath, value, true)
+	err := pathresolver.SetValueAtPath(data, keyPath, value, true)
 	if err != nil {
 		if errors.Is(err, pathresolver.ErrPathConflict) {
 			return fmt.Errorf("PATH_CONFLICT: %v", err)
@@ -66,22 +185,19 @@ func AddKey(filePath, keyPath string, value interface{}) error {
 		return fmt.Errorf("%w: Failed to add key '%s': %v", ErrAddKeyError, keyPath, err)
 	}
 
-	// Save the updated data
-	if err := handler.SaveJSON(data, 2); err != nil {
-		return fmt.Errorf("%w: Failed to save file: %v", ErrAddKeyError, err)
-	}
-
 	return nil
 }
 
-// UpdateKey updates existing key with new value
-func UpdateKey(filePath, keyPath string, value interface{}) error {
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
-	if err != nil {
-		return err
-	}
+// UpdateKey updates existing key with new value. With enforceType set, the new value
+// must have the same JSON type as the value it replaces.
+func UpdateKey(filePath, keyPath string, value interface{}, enforceType bool) error {
+	return editFile(filePath, ErrUpdateKeyError, func(data map[string]interface{}) error {
+		return updateKeyInData(data, filePath, keyPath, value, enforceType)
+	})
+}
 
+// updateKeyInData updates an existing key in already loaded data
+func updateKeyInData(data map[string]interface{}, filePath, keyPath string, value interface{}, enforceType bool) error {
 	// Validate path first
 	if err := pathresolver.ValidatePath(keyPath); err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidPath, err)
@@ -92,8 +208,19 @@ func UpdateKey(filePath, keyPath string, value interface{}) error {
 		return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
 	}
 
+	// Guard against accidental type changes (e.g. a boolean becoming "true")
+	if enforceType {
+		existing, err := pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			return fmt.Errorf("%w: Failed to read key '%s': %v", ErrUpdateKeyError, keyPath, err)
+		}
+		if oldType, newType := jsonTypeOf(existing), jsonTypeOf(value); oldType != newType {
+			return fmt.Errorf("%w: Key '%s' holds %s, refusing to replace it with %s", ErrTypeMismatch, keyPath, oldType, newType)
+		}
+	}
+
 	// Update the value
-	err = pathresolver.SetValueAtPath(data, keyPath, value, false)
+	err := pathresolver.SetValueAtPath(data, keyPath, value, false)
 	if err != nil {
 		if errors.Is(err, pathresolver.ErrKeyNotFound) {
 			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
@@ -101,16 +228,59 @@ func UpdateKey(filePath, keyPath string, value interface{}) error {
 		return fmt.Errorf("%w: Failed to update key '%s': %v", ErrUpdateKeyError, keyPath, err)
 	}
 
-	// Save the updated data
-	if err := handler.SaveJSON(data, 2); err != nil {
-		return fmt.Errorf("%w: Failed to save file: %v", ErrUpdateKeyError, err)
+	return nil
+}
+
+// CompareAndSet replaces the value at keyPath with newValue only if the current value
+// deep-equals expected, and reports whether the swap happened
+func CompareAndSet(filePath, keyPath string, expected, newValue interface{}) (bool, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPath, err)
 	}
 
-	return nil
+	target, err := normalizeJSON(expected)
+	if err != nil {
+		return false, fmt.Errorf("%w: Expected value is not JSON-serializable: %v", ErrInvalidJSON, err)
+	}
+
+	swapped := false
+	err = editFile(filePath, ErrUpdateKeyError, func(data map[string]interface{}) error {
+		swapped = false
+
+		current, err := pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		if !reflect.DeepEqual(current, target) {
+			return errNoChange
+		}
+
+		if err := updateKeyInData(data, filePath, keyPath, newValue, false); err != nil {
+			return err
+		}
+		swapped = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return swapped, nil
 }
 
 // RenameKey renames existing key (move value from old path to new path)
 func RenameKey(filePath, oldPath, newPath string) error {
+	if err := validateRenamePaths(oldPath, newPath); err != nil {
+		return err
+	}
+
+	return editFile(filePath, ErrRenameKeyError, func(data map[string]interface{}) error {
+		return renameKeyInData(data, filePath, oldPath, newPath)
+	})
+}
+
+// validateRenamePaths checks rename arguments before any data is loaded
+func validateRenamePaths(oldPath, newPath string) error {
 	if oldPath == newPath {
 		return fmt.Errorf("%w: Old and new key paths cannot be the same", ErrSameKey)
 	}
@@ -123,12 +293,11 @@ func RenameKey(filePath, oldPath, newPath string) error {
 		return fmt.Errorf("%w: %v", ErrInvalidPath, err)
 	}
 
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
+// renameKeyInData moves a value from oldPath to newPath in already loaded data
+func renameKeyInData(data map[string]interface{}, filePath, oldPath, newPath string) error {
 	// Check if old key exists
 	if !pathresolver.KeyExists(data, oldPath) {
 		return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, oldPath, filePath)
@@ -157,22 +326,124 @@ func RenameKey(filePath, oldPath, newPath string) error {
 		return fmt.Errorf("%w: Failed to remove old key '%s': %v", ErrRenameKeyError, oldPath, err)
 	}
 
-	// Save the updated data
-	if err := handler.SaveJSON(data, 2); err != nil {
-		return fmt.Errorf("%w: Failed to save file: %v", ErrRenameKeyError, err)
+	return nil
+}
+
+// WrapKey nests the key at keyPath one level deeper under newParent, which is created
+// next to the key if missing, and returns the key's new path. For example wrapping
+// "page.title" in "header" moves it to "page.header.title".
+func WrapKey(filePath, keyPath, newParent string) (string, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	if err := pathresolver.ValidatePath(newParent); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	parts := pathresolver.SplitPath(keyPath)
+	leaf := parts[len(parts)-1]
+	parentPath := strings.Join(parts[:len(parts)-1], ".")
+	newPath := pathresolver.JoinPath(pathresolver.JoinPath(parentPath, newParent), leaf)
+
+	err := editFile(filePath, ErrWrapKeyError, func(data map[string]interface{}) error {
+		value, err := pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+
+		// Remove first so a parent with the key's own name can take its place
+		if _, err := pathresolver.RemoveKeyAtPath(data, keyPath); err != nil {
+			return fmt.Errorf("%w: Failed to remove key '%s': %v", ErrWrapKeyError, keyPath, err)
+		}
+
+		if pathresolver.KeyExists(data, newPath) {
+			return fmt.Errorf("%w: Key '%s' already exists in %s", ErrKeyExists, newPath, filePath)
+		}
+		if err := pathresolver.SetValueAtPath(data, newPath, value, true); err != nil {
+			return fmt.Errorf("%w: Failed to set value at '%s': %v", ErrWrapKeyError, newPath, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return newPath, nil
+}
+
+// PromoteChildren merges the object at keyPath into its parent and removes it, the
+// inverse of WrapKey. Children colliding with existing siblings are an error unless
+// overwrite is set. Returns the promoted keys in sorted order.
+func PromoteChildren(filePath, keyPath string, overwrite bool) ([]string, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
 	}
 
-	return nil
+	var promoted []string
+	err := editFile(filePath, ErrPromoteError, func(data map[string]interface{}) error {
+		parent, leaf, err := pathresolver.NavigateToParent(data, keyPath)
+		if err != nil {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		value, exists := parent[leaf]
+		if !exists {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		children, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: Value at '%s' is not an object", ErrNotContainer, keyPath)
+		}
+
+		promoted = make([]string, 0, len(children))
+		for key := range children {
+			promoted = append(promoted, key)
+		}
+		sort.Strings(promoted)
+
+		if !overwrite {
+			var collisions []string
+			for _, key := range promoted {
+				if _, exists := parent[key]; exists && key != leaf {
+					collisions = append(collisions, key)
+				}
+			}
+			if len(collisions) > 0 {
+				return fmt.Errorf("%w: Keys %s already exist next to '%s' in %s", ErrKeyExists, strings.Join(collisions, ", "), keyPath, filePath)
+			}
+		}
+
+		delete(parent, leaf)
+		for key, child := range children {
+			parent[key] = child
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
 }
 
 // RemoveKey removes key and returns its value
 func RemoveKey(filePath, keyPath string) (interface{}, error) {
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
+	var removedValue interface{}
+	err := editFile(filePath, ErrRemoveKeyError, func(data map[string]interface{}) error {
+		var err error
+		removedValue, err = removeKeyInData(data, filePath, keyPath)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return removedValue, nil
+}
+
+// removeKeyInData removes a key from already loaded data and returns its value
+func removeKeyInData(data map[string]interface{}, filePath, keyPath string) (interface{}, error) {
 	// Validate path first
 	if err := pathresolver.ValidatePath(keyPath); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
@@ -189,86 +460,5325 @@ func RemoveKey(filePath, keyPath string) (interface{}, error) {
 		return nil, fmt.Errorf("%w: Failed to remove key '%s': %v", ErrRemoveKeyError, keyPath, err)
 	}
 
-	// Save the updated data
-	if err := handler.SaveJSON(data, 2); err != nil {
-		return nil, fmt.Errorf("%w: Failed to save file: %v", ErrRemoveKeyError, err)
-	}
-
 	return removedValue, nil
 }
 
-// ListKeys lists all immediate child keys at the specified path
-func ListKeys(filePath string, keyPath *string) ([]string, error) {
-	handler := jsonhandler.NewJSONHandler(filePath)
-	data, err := handler.LoadJSON(true)
+// WithoutKey returns the whole document with keyPath removed. The removal happens on a
+// freshly parsed copy; the file and the shared cache are never modified.
+func WithoutKey(filePath, keyPath string) (map[string]interface{}, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
 	if err != nil {
 		return nil, err
 	}
 
-	keys, err := pathresolver.GetAllKeysAtPath(data, keyPath)
+	if _, err := removeKeyInData(data, filePath, keyPath); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// RedactedValue replaces sensitive values in Redact output
+const RedactedValue = "***REDACTED***"
+
+// DefaultRedactPatterns are used by Redact when no patterns are given
+var DefaultRedactPatterns = []string{"*password*", "*token*", "*secret*"}
+
+// RedactionResult is the redacted document and the paths of the values that were masked
+type RedactionResult struct {
+	Document      map[string]interface{} `json:"document"`
+	RedactedPaths []string               `json:"redacted_paths"`
+}
+
+// Redact masks every scalar value under a key whose name matches one of patterns
+// (case-insensitive globs on the final key segment) with RedactedValue, leaving the
+// structure intact. The result is written to outputFile when given; the source file is
+// never modified.
+func Redact(filePath, outputFile string, patterns []string, createDirs bool) (*RedactionResult, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactPatterns
+	}
+	lowered := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		lowered[i] = strings.ToLower(pattern)
+		if _, err := path.Match(lowered[i], ""); err != nil {
+			return nil, fmt.Errorf("%w: Invalid pattern '%s': %v", ErrInvalidPattern, pattern, err)
+		}
+	}
+
+	// A fresh parse is private, so it can be redacted in place
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
 	if err != nil {
-		if errors.Is(err, pathresolver.ErrKeyNotFound) {
-			pathDesc := "root"
-			if keyPath != nil {
-				pathDesc = fmt.Sprintf("'%s'", *keyPath)
+		return nil, err
+	}
+
+	result := &RedactionResult{Document: data, RedactedPaths: []string{}}
+	redactInValue(data, "", false, lowered, &result.RedactedPaths)
+	sort.Strings(result.RedactedPaths)
+
+	if err := writeDerivedDocument(filePath, outputFile, data, createDirs, ErrRedactError); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// redactInValue replaces scalars in value that sit under a matching key, recording their paths
+func redactInValue(value interface{}, prefix string, matched bool, patterns []string, paths *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := pathresolver.JoinPath(prefix, key)
+			childMatched := matched || matchesAnyPattern(strings.ToLower(key), patterns)
+			if isContainer(child) {
+				redactInValue(child, childPath, childMatched, patterns, paths)
+			} else if childMatched {
+				v[key] = RedactedValue
+				*paths = append(*paths, childPath)
 			}
-			return nil, fmt.Errorf("%w: Key %s not found in %s", ErrKeyNotFound, pathDesc, filePath)
 		}
-		if errors.Is(err, pathresolver.ErrNotObject) {
-			return nil, fmt.Errorf("NOT_OBJECT: %v", err)
+	case []interface{}:
+		for i, child := range v {
+			childPath := pathresolver.JoinPath(prefix, strconv.Itoa(i))
+			if isContainer(child) {
+				redactInValue(child, childPath, matched, patterns, paths)
+			} else if matched {
+				v[i] = RedactedValue
+				*paths = append(*paths, childPath)
+			}
 		}
-		return nil, err
 	}
+}
 
-	return keys, nil
+// matchesAnyPattern reports whether name matches one of the (already validated) glob patterns
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
 }
 
-// KeyExists checks if a key exists at the specified path
-func KeyExists(filePath, keyPath string) (bool, error) {
-	handler := jsonhandler.NewJSONHandler(filePath)
+// writeDerivedDocument saves a document derived from filePath to outputFile, refusing to
+// overwrite the source itself. An empty outputFile means the result is only returned.
+func writeDerivedDocument(filePath, outputFile string, data map[string]interface{}, createDirs bool, writeErr error) error {
+	if outputFile == "" {
+		return nil
+	}
+
+	same, err := sameFile(filePath, outputFile)
+	if err != nil {
+		return err
+	}
+	if same {
+		return fmt.Errorf("%w: Output file must differ from %s so the original is kept", writeErr, filePath)
+	}
+
+	if err := ensureOutputDir(outputFile, createDirs, writeErr); err != nil {
+		return err
+	}
+	if err := jsonhandler.GetHandler(outputFile).SaveJSON(data, 2); err != nil {
+		return fmt.Errorf("%w: Failed to write %s: %w", writeErr, outputFile, err)
+	}
+	return nil
+}
+
+// ensureOutputDir creates the missing parent directories of outputFile when createDirs is set
+func ensureOutputDir(outputFile string, createDirs bool, writeErr error) error {
+	if !createDirs {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("%w: Failed to create %s: %v", writeErr, filepath.Dir(outputFile), err)
+	}
+	return nil
+}
+
+// GenerateMock builds a document with the same keys and nesting as templateFile but
+// placeholder values: "string" for strings, 0 for numbers, false for booleans, null for null.
+// Arrays keep a single element mocked from their first one, and empty arrays stay empty. The
+// mock is written to outputFile when given.
+func GenerateMock(templateFile, outputFile string, createDirs bool) (map[string]interface{}, error) {
+	handler := jsonhandler.GetHandler(templateFile)
 	data, err := handler.LoadJSON(true)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return pathresolver.KeyExists(data, keyPath), nil
+	mock := mockValue(data).(map[string]interface{})
+	if err := writeDerivedDocument(templateFile, outputFile, mock, createDirs, ErrMockError); err != nil {
+		return nil, err
+	}
+
+	return mock, nil
 }
 
-// ValidationResult represents the result of JSON validation
-type ValidationResult struct {
-	Valid       bool                                   `json:"valid"`
-	File        string                                 `json:"file"`
-	Error       *jsonhandler.ValidationError           `json:"error,omitempty"`
-	ErrorType   string                                 `json:"error_type,omitempty"`
-	Performance *jsonhandler.PerformanceMetrics        `json:"performance,omitempty"`
+// mockValue returns a copy of value with every leaf replaced by its type's placeholder
+func mockValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		mock := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			mock[key] = mockValue(child)
+		}
+		return mock
+	case []interface{}:
+		if len(v) == 0 {
+			return []interface{}{}
+		}
+		return []interface{}{mockValue(v[0])}
+	case string:
+		return "string"
+	case float64:
+		return 0.0
+	case bool:
+		return false
+	default:
+		return nil
+	}
 }
 
-// ValidateJSON validates JSON file syntax and structure
-func ValidateJSON(filePath string) (*ValidationResult, error) {
-	startTime := time.Now()
-	handler := jsonhandler.NewJSONHandler(filePath)
+// sameFile reports whether two paths refer to the same file; a missing file matches nothing
+func sameFile(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, nil
+	}
+	infoB, err := os.Stat(b)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: Failed to stat %s: %v", jsonhandler.ErrFileReadError, b, err)
+	}
+	return os.SameFile(infoA, infoB), nil
+}
 
-	// Get basic file info
-	fileInfo := handler.GetFileInfo()
-	
-	result := handler.ValidateJSONSyntax()
+// ProjectionResult is a document derived from a file, with requested paths that did not exist
+type ProjectionResult struct {
+	Document map[string]interface{} `json:"document"`
+	Missing  []string               `json:"missing"`
+}
 
-	// Add performance metrics if valid
-	if result.Valid && result.Performance != nil {
-		result.Performance.ParseTime = time.Since(startTime).Seconds()
-		if fileInfo.Exists {
-			result.Performance.FileSize = fileInfo.SizeBytes
+// Pick builds a new document containing only keyPaths, nested as in the source, and
+// writes it to outputFile when given. Paths that don't exist are listed in Missing.
+func Pick(filePath string, keyPaths []string, outputFile string, createDirs bool) (*ProjectionResult, error) {
+	for _, keyPath := range keyPaths {
+		if err := pathresolver.ValidatePath(keyPath); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
 		}
 	}
 
-	// Convert to our result type
-	validationResult := &ValidationResult{
-		Valid:       result.Valid,
-		File:        result.File,
-		Error:       result.Error,
-		ErrorType:   result.ErrorType,
-		Performance: result.Performance,
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		return nil, err
 	}
 
-	return validationResult, nil
+	result := &ProjectionResult{Document: map[string]interface{}{}, Missing: []string{}}
+	for _, keyPath := range keyPaths {
+		value, err := pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			result.Missing = append(result.Missing, keyPath)
+			continue
+		}
+
+		// Keys containing dots stay literal, as NavigateToKey found them
+		if _, exists := data[keyPath]; exists {
+			result.Document[keyPath] = value
+			continue
+		}
+		if err := pathresolver.SetValueAtPath(result.Document, keyPath, value, true); err != nil {
+			return nil, fmt.Errorf("%w: Failed to place '%s': %v", ErrPickError, keyPath, err)
+		}
+	}
+
+	if err := writeDerivedDocument(filePath, outputFile, result.Document, createDirs, ErrPickError); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Omit removes keyPaths from a copy of the document in one pass and writes the result to
+// outputFile when given. With inPlace set the source file itself is updated instead.
+// Paths that don't exist are listed in Missing.
+func Omit(filePath string, keyPaths []string, outputFile string, inPlace, createDirs bool) (*ProjectionResult, error) {
+	for _, keyPath := range keyPaths {
+		if err := pathresolver.ValidatePath(keyPath); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+	}
+	if inPlace && outputFile != "" {
+		return nil, fmt.Errorf("%w: Give either an output file or in-place, not both", ErrOmitError)
+	}
+
+	var result *ProjectionResult
+	omit := func(data map[string]interface{}) {
+		result = &ProjectionResult{Document: data, Missing: []string{}}
+		for _, keyPath := range keyPaths {
+			if _, err := removeKeyInData(data, filePath, keyPath); err != nil {
+				result.Missing = append(result.Missing, keyPath)
+			}
+		}
+	}
+
+	if inPlace {
+		err := editFile(filePath, ErrOmitError, func(data map[string]interface{}) error {
+			omit(data)
+			if len(result.Missing) == len(keyPaths) {
+				return errNoChange
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		return nil, err
+	}
+	omit(data)
+
+	if err := writeDerivedDocument(filePath, outputFile, data, createDirs, ErrOmitError); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Operation describes a single step of a transaction
+type Operation struct {
+	Action  string      `json:"action"`
+	KeyPath string      `json:"key_path"`
+	NewPath string      `json:"new_path,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// ApplyTransaction applies a sequence of add/update/remove/rename/move operations to a
+// single in-memory copy of the file and saves once. If any operation fails nothing is written.
+func ApplyTransaction(filePath string, ops []Operation) error {
+	return editFile(filePath, ErrTransaction, func(data map[string]interface{}) error {
+		return applyOperationsInData(data, filePath, ops)
+	})
+}
+
+// applyOperationsInData applies transaction operations in order to already loaded data
+func applyOperationsInData(data map[string]interface{}, filePath string, ops []Operation) error {
+	for i, op := range ops {
+		if opErr := applyOperationInData(data, filePath, op); opErr != nil {
+			return fmt.Errorf("%w: Operation %d (%s) failed, no changes written: %w", ErrTransaction, i+1, op.Action, opErr)
+		}
+	}
+
+	return nil
+}
+
+// applyOperationInData applies a single transaction operation to already loaded data
+func applyOperationInData(data map[string]interface{}, filePath string, op Operation) error {
+	switch op.Action {
+	case "add":
+		return addKeyInData(data, filePath, op.KeyPath, op.Value)
+	case "update":
+		return updateKeyInData(data, filePath, op.KeyPath, op.Value, false)
+	case "remove":
+		_, err := removeKeyInData(data, filePath, op.KeyPath)
+		return err
+	case "rename", "move":
+		if err := validateRenamePaths(op.KeyPath, op.NewPath); err != nil {
+			return err
+		}
+		return renameKeyInData(data, filePath, op.KeyPath, op.NewPath)
+	default:
+		return fmt.Errorf("unknown action '%s'", op.Action)
+	}
+}
+
+// BatchResult is the outcome of one BatchEdit instruction
+type BatchResult struct {
+	Index   int    `json:"index"`
+	Action  string `json:"action"`
+	Path    string `json:"path"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchEdit applies loosely typed {action, path, value} instructions, with new_path for
+// rename, to one copy of the file and saves once. Every instruction is checked before any is
+// applied, and each gets a result. If any instruction is malformed or fails, nothing is
+// written, the results say which, and a TRANSACTION_ERROR is returned alongside them.
+func BatchEdit(filePath string, instructions []map[string]interface{}) ([]BatchResult, error) {
+	results := make([]BatchResult, len(instructions))
+	ops := make([]Operation, len(instructions))
+	invalid := 0
+	for i, instruction := range instructions {
+		op, err := batchOperation(instruction)
+		ops[i] = op
+		results[i] = BatchResult{Index: i, Action: op.Action, Path: op.KeyPath}
+		if err != nil {
+			results[i].Error = err.Error()
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return results, fmt.Errorf("%w: %d of %d instruction(s) are invalid, no changes written", ErrTransaction, invalid, len(instructions))
+	}
+
+	err := editFile(filePath, ErrTransaction, func(data map[string]interface{}) error {
+		for i := range results {
+			results[i].Applied = false
+			results[i].Error = ""
+		}
+		for i, op := range ops {
+			if err := applyOperationInData(data, filePath, op); err != nil {
+				results[i].Error = err.Error()
+				return fmt.Errorf("%w: Instruction %d (%s) failed, no changes written: %w", ErrTransaction, i, op.Action, err)
+			}
+			results[i].Applied = true
+		}
+		return nil
+	})
+	if err != nil {
+		// Nothing was saved, so earlier instructions didn't take effect either
+		for i := range results {
+			results[i].Applied = false
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// batchOperation converts a BatchEdit instruction into a transaction operation, checking
+// that it names a known action and carries the fields that action needs
+func batchOperation(instruction map[string]interface{}) (Operation, error) {
+	action, _ := instruction["action"].(string)
+	path, _ := instruction["path"].(string)
+	newPath, _ := instruction["new_path"].(string)
+	value, hasValue := instruction["value"]
+	op := Operation{Action: action, KeyPath: path, NewPath: newPath, Value: value}
+
+	switch action {
+	case "add", "update":
+		if !hasValue {
+			return op, fmt.Errorf("'%s' needs a value", action)
+		}
+	case "remove":
+	case "rename":
+		if newPath == "" {
+			return op, fmt.Errorf("'rename' needs a new_path")
+		}
+	case "":
+		return op, fmt.Errorf("missing action")
+	default:
+		return op, fmt.Errorf("unknown action '%s' (expected add, update, remove or rename)", action)
+	}
+
+	if err := pathresolver.ValidatePath(path); err != nil {
+		return op, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	return op, nil
+}
+
+// SetEscapeHTML sets whether later saves of filePath HTML-escape <, > and &.
+// The setting is kept per file while the process runs and defaults to false.
+func SetEscapeHTML(filePath string, escape bool) {
+	jsonhandler.GetHandler(filePath).SetEscapeHTML(escape)
+}
+
+// SetVerifyWrites sets whether later saves of filePath are read back and compared with the
+// data being saved, leaving the file untouched on a mismatch. The setting is kept per file
+// while the process runs and defaults to false.
+func SetVerifyWrites(filePath string, verify bool) {
+	jsonhandler.GetHandler(filePath).SetVerifyWrites(verify)
+}
+
+// ClearKey empties the object or array at keyPath in place and returns its old contents
+func ClearKey(filePath, keyPath string) (interface{}, error) {
+	// Validate path first
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	var oldValue interface{}
+	err := editFile(filePath, ErrClearKeyError, func(data map[string]interface{}) error {
+		var err error
+		oldValue, err = pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			if errors.Is(err, pathresolver.ErrKeyNotFound) {
+				return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+			}
+			return fmt.Errorf("PATH_ERROR: %v", err)
+		}
+
+		// Only containers can be cleared; a scalar has no "empty" form
+		var emptyValue interface{}
+		switch oldValue.(type) {
+		case map[string]interface{}:
+			emptyValue = map[string]interface{}{}
+		case []interface{}:
+			emptyValue = []interface{}{}
+		default:
+			return fmt.Errorf("%w: Value at '%s' is not an object or array", ErrNotContainer, keyPath)
+		}
+
+		if err := replaceValueInData(data, keyPath, emptyValue); err != nil {
+			return fmt.Errorf("%w: Failed to clear key '%s': %v", ErrClearKeyError, keyPath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oldValue, nil
+}
+
+// InsertArrayElement inserts value into the array at keyPath before position index.
+// An index equal to the array length appends; negative indices count from the end.
+func InsertArrayElement(filePath, keyPath string, index int, value interface{}) error {
+	return editFile(filePath, ErrArrayError, func(data map[string]interface{}) error {
+		array, err := arrayInData(data, filePath, keyPath)
+		if err != nil {
+			return err
+		}
+
+		position, err := resolveArrayIndex(keyPath, index, len(array), true)
+		if err != nil {
+			return err
+		}
+
+		updated := make([]interface{}, 0, len(array)+1)
+		updated = append(updated, array[:position]...)
+		updated = append(updated, value)
+		updated = append(updated, array[position:]...)
+
+		if err := replaceValueInData(data, keyPath, updated); err != nil {
+			return fmt.Errorf("%w: Failed to insert into '%s': %v", ErrArrayError, keyPath, err)
+		}
+		return nil
+	})
+}
+
+// UpdateArrayElement replaces the element at index of the array at keyPath.
+// Negative indices count from the end.
+func UpdateArrayElement(filePath, keyPath string, index int, value interface{}) error {
+	return editFile(filePath, ErrArrayError, func(data map[string]interface{}) error {
+		array, err := arrayInData(data, filePath, keyPath)
+		if err != nil {
+			return err
+		}
+
+		position, err := resolveArrayIndex(keyPath, index, len(array), false)
+		if err != nil {
+			return err
+		}
+
+		// The loaded data is a private copy, so the element can be replaced in place
+		array[position] = value
+		return nil
+	})
+}
+
+// FindInArray returns the indices of elements in the array at keyPath whose matchField
+// deep-equals matchValue. With an empty matchField, whole elements are compared instead.
+func FindInArray(filePath, keyPath, matchField string, matchValue interface{}) ([]int, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	array, err := arrayInData(data, filePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := normalizeJSON(matchValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Value is not JSON-serializable: %v", ErrInvalidJSON, err)
+	}
+
+	indices := []int{}
+	for i, element := range array {
+		candidate := element
+		if matchField != "" {
+			// Elements without the field (or that aren't objects) simply don't match
+			if candidate, err = pathresolver.NavigateToKey(element, matchField); err != nil {
+				continue
+			}
+		}
+		if reflect.DeepEqual(candidate, target) {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices, nil
+}
+
+// SetWhere sets setField to setValue on every object in the array at keyPath whose matchField
+// deep-equals matchValue, creating setField (a dot-notation path within the element) if
+// needed. It returns how many elements changed; elements already holding setValue, and
+// elements that aren't objects, are left alone.
+func SetWhere(filePath, keyPath, matchField string, matchValue interface{}, setField string, setValue interface{}) (int, error) {
+	if err := pathresolver.ValidatePath(matchField); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	if err := pathresolver.ValidatePath(setField); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	target, err := normalizeJSON(matchValue)
+	if err != nil {
+		return 0, fmt.Errorf("%w: Value is not JSON-serializable: %v", ErrInvalidJSON, err)
+	}
+	if _, err := normalizeJSON(setValue); err != nil {
+		return 0, fmt.Errorf("%w: Value is not JSON-serializable: %v", ErrInvalidJSON, err)
+	}
+
+	var updated int
+	err = editFile(filePath, ErrArrayError, func(data map[string]interface{}) error {
+		updated = 0
+		array, err := arrayInData(data, filePath, keyPath)
+		if err != nil {
+			return err
+		}
+
+		for i, element := range array {
+			object, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if candidate, err := pathresolver.NavigateToKey(object, matchField); err != nil || !reflect.DeepEqual(candidate, target) {
+				continue
+			}
+
+			// Each element gets its own copy of the value
+			value, _ := normalizeJSON(setValue)
+			if current, err := pathresolver.NavigateToKey(object, setField); err == nil && reflect.DeepEqual(current, value) {
+				continue
+			}
+			if err := pathresolver.SetValueAtPath(object, setField, value, true); err != nil {
+				return fmt.Errorf("%w: Failed to set '%s' on element %d: %v", ErrArrayError, setField, i, err)
+			}
+			updated++
+		}
+
+		if updated == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// arrayInData returns the array stored at keyPath in already loaded data
+func arrayInData(data map[string]interface{}, filePath, keyPath string) ([]interface{}, error) {
+	value, err := pathresolver.NavigateToKey(data, keyPath)
+	if err != nil {
+		if errors.Is(err, pathresolver.ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		if errors.Is(err, pathresolver.ErrInvalidPath) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+		return nil, fmt.Errorf("PATH_ERROR: %v", err)
+	}
+
+	array, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: Value at '%s' is %s, not an array", ErrNotArray, keyPath, jsonTypeOf(value))
+	}
+	return array, nil
+}
+
+// resolveArrayIndex converts a possibly negative index into a position in an array of the
+// given length. Negative indices count from the end; allowEnd also accepts index == length.
+func resolveArrayIndex(keyPath string, index, length int, allowEnd bool) (int, error) {
+	position := index
+	if position < 0 {
+		position += length
+	}
+
+	limit := length
+	if allowEnd {
+		limit++
+	}
+	if position < 0 || position >= limit {
+		return 0, fmt.Errorf("%w: Index %d is out of range for array '%s' of length %d", ErrIndexOutOfRange, index, keyPath, length)
+	}
+	return position, nil
+}
+
+// replaceValueInData overwrites the existing value at keyPath, giving literal
+// dotted keys precedence the same way NavigateToKey does
+func replaceValueInData(data map[string]interface{}, keyPath string, value interface{}) error {
+	if _, exists := data[keyPath]; exists {
+		data[keyPath] = value
+		return nil
+	}
+	return pathresolver.SetValueAtPath(data, keyPath, value, false)
+}
+
+// GlobGet retrieves every value whose path matches a '*'-wildcard pattern, keyed by concrete path
+func GlobGet(filePath, pattern string) (map[string]interface{}, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(pattern, true)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := pathresolver.ExpandWildcardPath(data, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	return matchValues(matches), nil
+}
+
+// matchValues keys the values of wildcard matches by their display path
+func matchValues(matches []pathresolver.Match) map[string]interface{} {
+	values := make(map[string]interface{}, len(matches))
+	for _, match := range matches {
+		values[match.Path] = match.Value
+	}
+	return values
+}
+
+// RemoveMatching removes every key whose path matches a '*'-wildcard pattern and returns the
+// removed values keyed by concrete path. With dryRun set, matches are reported but the file is left untouched.
+func RemoveMatching(filePath, pattern string, dryRun bool) (map[string]interface{}, error) {
+	if dryRun {
+		return GlobGet(filePath, pattern)
+	}
+
+	var removed map[string]interface{}
+	err := editFile(filePath, ErrRemoveKeyError, func(data map[string]interface{}) error {
+		matches, err := pathresolver.ExpandWildcardPath(data, pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+		removed = matchValues(matches)
+		if len(matches) == 0 {
+			return errNoChange
+		}
+
+		// Remove all matches in memory, then save once. Keys may contain dots, so each is
+		// deleted from the object it was found in rather than looked up again by path.
+		for _, match := range matches {
+			delete(match.Parent, match.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// SetMatching sets every existing leaf whose path matches a '*'-wildcard pattern to value in
+// a single save and returns the paths changed, sorted. Objects and arrays are not leaves and
+// are left alone, nothing new is created, and leaves already equal to value are skipped.
+// With dryRun set, the paths are reported but the file is left untouched.
+func SetMatching(filePath, pattern string, value interface{}, dryRun bool) ([]string, error) {
+	normalized, err := normalizeJSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Value cannot be encoded as JSON: %v", ErrUpdateKeyError, err)
+	}
+
+	// changedLeaves returns the matched leaves that differ from value
+	changedLeaves := func(data map[string]interface{}) ([]pathresolver.Match, error) {
+		matches, err := pathresolver.ExpandWildcardPath(data, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+
+		leaves := []pathresolver.Match{}
+		for _, match := range matches {
+			if !isContainer(match.Value) && !reflect.DeepEqual(match.Value, normalized) {
+				leaves = append(leaves, match)
+			}
+		}
+		return leaves, nil
+	}
+	matchPaths := func(matches []pathresolver.Match) []string {
+		paths := make([]string, len(matches))
+		for i, match := range matches {
+			paths[i] = match.Path
+		}
+		return paths
+	}
+
+	if dryRun {
+		data, err := jsonhandler.GetHandler(filePath).LoadJSON(true)
+		if err != nil {
+			return nil, err
+		}
+		leaves, err := changedLeaves(data)
+		if err != nil {
+			return nil, err
+		}
+		return matchPaths(leaves), nil
+	}
+
+	var paths []string
+	err = editFile(filePath, ErrUpdateKeyError, func(data map[string]interface{}) error {
+		leaves, err := changedLeaves(data)
+		if err != nil {
+			return err
+		}
+		paths = matchPaths(leaves)
+		if len(leaves) == 0 {
+			return errNoChange
+		}
+
+		// Keys may contain dots, so each leaf is set in the object it was found in
+		for _, leaf := range leaves {
+			leaf.Parent[leaf.Key] = normalized
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// FindKeysByPattern returns the sorted paths of every object key, at any depth, whose name
+// matches the regular expression pattern. Array indices are not key names and never match,
+// though keys inside array elements do.
+func FindKeysByPattern(filePath, pattern string) ([]string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Invalid regular expression '%s': %v", ErrInvalidPattern, pattern, err)
+	}
+
+	data, err := jsonhandler.GetHandler(filePath).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	findKeysByPattern(data, "", re, &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// findKeysByPattern records the paths under prefix of the object keys whose name matches re
+func findKeysByPattern(value interface{}, prefix string, re *regexp.Regexp, paths *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := pathresolver.JoinPath(prefix, key)
+			if re.MatchString(key) {
+				*paths = append(*paths, path)
+			}
+			findKeysByPattern(child, path, re, paths)
+		}
+	case []interface{}:
+		for i, child := range v {
+			findKeysByPattern(child, pathresolver.JoinPath(prefix, strconv.Itoa(i)), re, paths)
+		}
+	}
+}
+
+// ValueCount represents the occurrences of a value within a file
+type ValueCount struct {
+	Count int      `json:"count"`
+	Paths []string `json:"paths"`
+}
+
+// CountValue counts every value in the file that deep-equals the given value, including whole objects and arrays
+func CountValue(filePath string, value interface{}) (*ValueCount, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := normalizeJSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Value is not JSON-serializable: %v", ErrInvalidJSON, err)
+	}
+
+	result := &ValueCount{Paths: []string{}}
+	pathresolver.Walk(data, func(path string, current interface{}) bool {
+		if reflect.DeepEqual(current, target) {
+			result.Count++
+			result.Paths = append(result.Paths, path)
+		}
+		return true
+	})
+
+	return result, nil
+}
+
+// PathsOfValue returns the path of every value in the file that deep-equals value, objects and
+// arrays included, in walk order. No matches gives an empty list, not an error.
+func PathsOfValue(filePath string, value interface{}) ([]string, error) {
+	result, err := CountValue(filePath, value)
+	if err != nil {
+		return nil, err
+	}
+	return result.Paths, nil
+}
+
+// PatchOperation represents a single RFC 6902 JSON Patch operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value member for remove operations, which carry none
+func (p PatchOperation) MarshalJSON() ([]byte, error) {
+	if p.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{p.Op, p.Path})
+	}
+	type patchOperation PatchOperation
+	return json.Marshal(patchOperation(p))
+}
+
+// GeneratePatch returns the JSON Patch (RFC 6902) that transforms fileA into fileB
+func GeneratePatch(fileA, fileB string) ([]PatchOperation, error) {
+	dataA, err := jsonhandler.GetHandler(fileA).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	dataB, err := jsonhandler.GetHandler(fileB).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := []PatchOperation{}
+	diffValues("", dataA, dataB, &patch)
+	return patch, nil
+}
+
+// FileComparison is the verdict of comparing two JSON documents
+type FileComparison struct {
+	Equal          bool `json:"equal"`
+	DifferingPaths int  `json:"differing_paths"`
+}
+
+// FilesEqual reports whether fileA and fileB hold the same document, ignoring formatting and
+// key order, and how many paths differ: one per operation in the patch from fileA to fileB
+func FilesEqual(fileA, fileB string) (*FileComparison, error) {
+	patch, err := GeneratePatch(fileA, fileB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileComparison{Equal: len(patch) == 0, DifferingPaths: len(patch)}, nil
+}
+
+// diffValues appends the operations needed to turn a into b at the given JSON Pointer
+func diffValues(pointer string, a, b interface{}, patch *[]PatchOperation) {
+	switch aVal := a.(type) {
+	case map[string]interface{}:
+		if bVal, ok := b.(map[string]interface{}); ok {
+			diffObjects(pointer, aVal, bVal, patch)
+			return
+		}
+	case []interface{}:
+		if bVal, ok := b.([]interface{}); ok {
+			diffArrays(pointer, aVal, bVal, patch)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*patch = append(*patch, PatchOperation{Op: "replace", Path: pointer, Value: b})
+	}
+}
+
+// diffObjects emits removals, then recursive changes and additions, in sorted key order
+func diffObjects(pointer string, a, b map[string]interface{}, patch *[]PatchOperation) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPointer := pointer + "/" + escapePointerToken(key)
+		aChild, inA := a[key]
+		bChild, inB := b[key]
+		switch {
+		case inA && !inB:
+			*patch = append(*patch, PatchOperation{Op: "remove", Path: childPointer})
+		case !inA && inB:
+			*patch = append(*patch, PatchOperation{Op: "add", Path: childPointer, Value: bChild})
+		default:
+			diffValues(childPointer, aChild, bChild, patch)
+		}
+	}
+}
+
+// diffArrays diffs the common prefix element-wise, then appends or trims the tail.
+// Trailing removals run from the highest index down so earlier indices stay valid.
+func diffArrays(pointer string, a, b []interface{}, patch *[]PatchOperation) {
+	common := len(a)
+	if len(b) < common {
+		common = len(b)
+	}
+
+	for i := 0; i < common; i++ {
+		diffValues(pointer+"/"+strconv.Itoa(i), a[i], b[i], patch)
+	}
+	for i := common; i < len(b); i++ {
+		*patch = append(*patch, PatchOperation{Op: "add", Path: pointer + "/" + strconv.Itoa(i), Value: b[i]})
+	}
+	for i := len(a) - 1; i >= common; i-- {
+		*patch = append(*patch, PatchOperation{Op: "remove", Path: pointer + "/" + strconv.Itoa(i)})
+	}
+}
+
+// escapePointerToken escapes a key for use as a JSON Pointer (RFC 6901) reference token
+func escapePointerToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// unescapePointer splits a JSON Pointer into its unescaped reference tokens
+func unescapePointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+
+	tokens := strings.Split(pointer, "/")[1:]
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// KeyLocation represents a key's value together with where it is defined in the file
+type KeyLocation struct {
+	KeyPath string      `json:"key_path"`
+	Value   interface{} `json:"value"`
+	Offset  int64       `json:"offset"`
+	Line    int         `json:"line"`
+	Column  int         `json:"column"`
+}
+
+// LocateKey retrieves the value at keyPath along with the line/column of its key in the source text
+func LocateKey(filePath, keyPath string) (*KeyLocation, error) {
+	value, err := GetKey(filePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := jsonhandler.GetHandler(filePath).LocateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+	}
+
+	return &KeyLocation{
+		KeyPath: keyPath,
+		Value:   value,
+		Offset:  location.Offset,
+		Line:    location.Line,
+		Column:  location.Column,
+	}, nil
+}
+
+// TextSpan represents the range of source text holding the value at a path
+type TextSpan struct {
+	KeyPath string `json:"key_path"`
+	jsonhandler.TextSpan
+}
+
+// SubtreeSpan finds the byte offsets and line/column range of the text the value at keyPath
+// occupies in filePath, so a client can replace exactly that span and leave the formatting
+// around it untouched. An empty keyPath spans the whole document.
+func SubtreeSpan(filePath, keyPath string) (*TextSpan, error) {
+	span, err := jsonhandler.GetHandler(filePath).SubtreeSpan(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if span == nil {
+		return nil, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+	}
+
+	return &TextSpan{KeyPath: keyPath, TextSpan: *span}, nil
+}
+
+// SubtreeSize represents the serialized size of the value at a path
+type SubtreeSize struct {
+	Path string `json:"path"`
+	Size int    `json:"size_bytes"`
+}
+
+// LargestSubtrees returns the topN heaviest values by compact serialized size, considering
+// every path up to the given depth (1 means top-level keys only)
+func LargestSubtrees(filePath string, topN, depth int) ([]SubtreeSize, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth < 1 {
+		depth = 1
+	}
+
+	sizes := []SubtreeSize{}
+	var walkErr error
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			walkErr = err
+			return false
+		}
+		sizes = append(sizes, SubtreeSize{Path: path, Size: len(encoded)})
+		return len(pathresolver.SplitPath(path)) < depth
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("%w: Failed to serialize value: %v", ErrInvalidJSON, walkErr)
+	}
+
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].Size > sizes[j].Size
+	})
+	if topN > 0 && len(sizes) > topN {
+		sizes = sizes[:topN]
+	}
+
+	return sizes, nil
+}
+
+// StringLength is the length, in characters, of the string at a path
+type StringLength struct {
+	Path   string `json:"path"`
+	Length int    `json:"length"`
+}
+
+// LongestStrings returns the topN longest string values at any depth, longest first, with
+// ties in path order. Length counts Unicode characters rather than bytes. A topN of 0 or
+// less returns every string.
+func LongestStrings(filePath string, topN int) ([]StringLength, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	lengths := []StringLength{}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		if s, ok := value.(string); ok {
+			lengths = append(lengths, StringLength{Path: path, Length: utf8.RuneCountInString(s)})
+		}
+		return true
+	})
+
+	sort.SliceStable(lengths, func(i, j int) bool {
+		return lengths[i].Length > lengths[j].Length
+	})
+	if topN > 0 && len(lengths) > topN {
+		lengths = lengths[:topN]
+	}
+
+	return lengths, nil
+}
+
+// RenderTree renders the structure at keyPath (or the root) as an indented ASCII tree.
+// Containers deeper than maxDepth are summarized, and leaf values longer than maxValueLen
+// characters are truncated; zero or less disables either limit.
+func RenderTree(filePath string, keyPath *string, maxDepth, maxValueLen int) (string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return "", err
+	}
+
+	var target interface{} = data
+	label := filePath
+	if keyPath != nil {
+		target, err = pathresolver.NavigateToKey(data, *keyPath)
+		if err != nil {
+			if errors.Is(err, pathresolver.ErrKeyNotFound) {
+				return "", fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, *keyPath, filePath)
+			}
+			return "", fmt.Errorf("PATH_ERROR: %v", err)
+		}
+		label = *keyPath
+	}
+
+	var builder strings.Builder
+	builder.WriteString(label)
+	if isContainer(target) {
+		builder.WriteString("\n")
+		renderTreeChildren(&builder, target, "", 1, maxDepth, maxValueLen)
+	} else {
+		builder.WriteString(": " + renderTreeValue(target, maxValueLen) + "\n")
+	}
+
+	return builder.String(), nil
+}
+
+// renderTreeChildren writes one line per child of value, recursing into containers
+func renderTreeChildren(builder *strings.Builder, value interface{}, indent string, depth, maxDepth, maxValueLen int) {
+	var names []string
+	var children []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		for _, key := range names {
+			children = append(children, v[key])
+		}
+	case []interface{}:
+		for i, item := range v {
+			names = append(names, fmt.Sprintf("[%d]", i))
+			children = append(children, item)
+		}
+	}
+
+	for i, child := range children {
+		branch, childIndent := "├─ ", indent+"│  "
+		if i == len(children)-1 {
+			branch, childIndent = "└─ ", indent+"   "
+		}
+
+		builder.WriteString(indent + branch + names[i])
+		switch {
+		case !isContainer(child) || containerLen(child) == 0:
+			builder.WriteString(": " + renderTreeValue(child, maxValueLen) + "\n")
+		case maxDepth > 0 && depth >= maxDepth:
+			builder.WriteString(": " + summarizeContainer(child) + "\n")
+		default:
+			builder.WriteString("\n")
+			renderTreeChildren(builder, child, childIndent, depth+1, maxDepth, maxValueLen)
+		}
+	}
+}
+
+// renderTreeValue formats a leaf as compact JSON, truncated to maxValueLen characters
+func renderTreeValue(value interface{}, maxValueLen int) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return truncateString(string(encoded), maxValueLen)
+}
+
+// summarizeContainer describes a container that is not expanded further
+func summarizeContainer(value interface{}) string {
+	if _, ok := value.([]interface{}); ok {
+		return fmt.Sprintf("[%d items]", containerLen(value))
+	}
+	return fmt.Sprintf("{%d keys}", containerLen(value))
+}
+
+// PathComparison represents the result of comparing the values at two paths
+type PathComparison struct {
+	Equal      bool   `json:"equal"`
+	Difference string `json:"difference,omitempty"`
+}
+
+// PathsEqual deep-compares the values at pathA and pathB, describing the first difference if any
+func PathsEqual(filePath, pathA, pathB string) (*PathComparison, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 2)
+	for i, keyPath := range []string{pathA, pathB} {
+		values[i], err = pathresolver.NavigateToKey(data, keyPath)
+		if err != nil {
+			name := [2]string{"path_a", "path_b"}[i]
+			if errors.Is(err, pathresolver.ErrKeyNotFound) {
+				return nil, fmt.Errorf("%w: Key '%s' (%s) not found in %s", ErrKeyNotFound, keyPath, name, filePath)
+			}
+			if errors.Is(err, pathresolver.ErrInvalidPath) {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPath, name, err)
+			}
+			return nil, fmt.Errorf("PATH_ERROR: %s: %v", name, err)
+		}
+	}
+
+	patch := []PatchOperation{}
+	diffValues("", values[0], values[1], &patch)
+	if len(patch) == 0 {
+		return &PathComparison{Equal: true}, nil
+	}
+
+	return &PathComparison{Equal: false, Difference: describePatchOperation(patch[0], values[0])}, nil
+}
+
+// describePatchOperation turns a patch operation into a short human-readable difference,
+// using source to look up the original value of replaced entries
+func describePatchOperation(op PatchOperation, source interface{}) string {
+	tokens := unescapePointer(op.Path)
+	location := "the root"
+	if len(tokens) > 0 {
+		location = fmt.Sprintf("'%s'", strings.Join(tokens, "."))
+	}
+
+	switch op.Op {
+	case "remove":
+		return fmt.Sprintf("%s exists only in the first value", location)
+	case "add":
+		return fmt.Sprintf("%s exists only in the second value", location)
+	}
+
+	original := source
+	for _, token := range tokens {
+		switch node := original.(type) {
+		case map[string]interface{}:
+			original = node[token]
+		case []interface{}:
+			index, _ := strconv.Atoi(token)
+			original = node[index]
+		}
+	}
+	return fmt.Sprintf("%s differs: %s vs %s", location, renderTreeValue(original, 60), renderTreeValue(op.Value, 60))
+}
+
+// snapshotLabelPattern restricts labels to names that are safe as file names
+var snapshotLabelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SnapshotInfo describes a stored snapshot of a file
+type SnapshotInfo struct {
+	Label       string    `json:"label"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedTime time.Time `json:"created_time"`
+}
+
+// Snapshot copies the current contents of filePath to <file>.snapshots/<label>.json
+func Snapshot(filePath, label string) error {
+	snapshotPath, err := snapshotFilePath(filePath, label)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(snapshotPath); err == nil {
+		return fmt.Errorf("%w: Snapshot '%s' already exists for %s", ErrSnapshotExists, label, filePath)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: File %s not found", ErrFileNotFound, filePath)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: Failed to read %s: %v", ErrSnapshotError, filePath, err)
+	}
+	handler.RecordAccess("", jsonhandler.AuditRead)
+
+	if err := os.MkdirAll(filepath.Dir(snapshotPath), 0755); err != nil {
+		return fmt.Errorf("%w: Failed to create snapshot directory: %v", ErrSnapshotError, err)
+	}
+	if err := jsonhandler.WriteFileAtomic(snapshotPath, content); err != nil {
+		return fmt.Errorf("%w: Failed to write snapshot: %w", ErrSnapshotError, err)
+	}
+
+	return nil
+}
+
+// Restore atomically replaces filePath with the snapshot stored under label
+func Restore(filePath, label string) error {
+	snapshotPath, err := snapshotFilePath(filePath, label)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(snapshotPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: Snapshot '%s' not found for %s", ErrSnapshotNotFound, label, filePath)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: Failed to read snapshot: %v", ErrSnapshotError, err)
+	}
+	if !json.Valid(content) {
+		return fmt.Errorf("%w: Snapshot '%s' does not contain valid JSON", ErrInvalidJSON, label)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	if err := handler.ReplaceContents(content); err != nil {
+		return fmt.Errorf("%w: Failed to restore snapshot: %w", ErrSnapshotError, err)
+	}
+
+	return nil
+}
+
+// ListSnapshots lists the snapshots stored for filePath, oldest first
+func ListSnapshots(filePath string) ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(snapshotDir(filePath))
+	if os.IsNotExist(err) {
+		return []SnapshotInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to read snapshot directory: %v", ErrSnapshotError, err)
+	}
+
+	snapshots := []SnapshotInfo{}
+	for _, entry := range entries {
+		label, isSnapshot := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !isSnapshot {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, SnapshotInfo{
+			Label:       label,
+			SizeBytes:   info.Size(),
+			CreatedTime: info.ModTime(),
+		})
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedTime.Before(snapshots[j].CreatedTime)
+	})
+
+	return snapshots, nil
+}
+
+// snapshotDir returns the sidecar directory holding snapshots of filePath
+func snapshotDir(filePath string) string {
+	return filePath + ".snapshots"
+}
+
+// snapshotFilePath validates label and returns where its snapshot is stored
+func snapshotFilePath(filePath, label string) (string, error) {
+	if !snapshotLabelPattern.MatchString(label) {
+		return "", fmt.Errorf("%w: Snapshot label '%s' may only contain letters, digits, '.', '_' and '-'", ErrInvalidLabel, label)
+	}
+	return filepath.Join(snapshotDir(filePath), label+".json"), nil
+}
+
+// Undo reverts the last edit made to filePath through this server
+func Undo(filePath string) error {
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	ok, err := handler.Undo(2)
+	if err != nil {
+		return fmt.Errorf("%w: Failed to undo: %w", ErrHistoryError, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: No edits to undo for %s", ErrNothingToUndo, filePath)
+	}
+
+	return nil
+}
+
+// Redo reapplies the last edit to filePath reverted by Undo
+func Redo(filePath string) error {
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	ok, err := handler.Redo(2)
+	if err != nil {
+		return fmt.Errorf("%w: Failed to redo: %w", ErrHistoryError, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: No undone edits to redo for %s", ErrNothingToRedo, filePath)
+	}
+
+	return nil
+}
+
+// EncodingInfo describes the character encoding of a JSON file
+type EncodingInfo struct {
+	Encoding string `json:"encoding"`
+	BOM      bool   `json:"bom"`
+}
+
+// DetectEncoding reports the character encoding of filePath by sniffing its first bytes
+func DetectEncoding(filePath string) (*EncodingInfo, error) {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: File %s not found", ErrFileNotFound, filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to open %s: %v", jsonhandler.ErrFileReadError, filePath, err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 4)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: Failed to read %s: %v", jsonhandler.ErrFileReadError, filePath, err)
+	}
+
+	info := jsonhandler.DetectEncoding(head[:n])
+	return &EncodingInfo{
+		Encoding: info.Encoding,
+		BOM:      info.BOM,
+	}, nil
+}
+
+// maxShowIndent caps the indent accepted by ShowSubtree
+const maxShowIndent = 8
+
+// ShowSubtree returns the value at keyPath as JSON indented by indent spaces per level.
+// An indent of zero gives compact output; larger values are capped at 8.
+func ShowSubtree(filePath, keyPath string, indent int) (string, error) {
+	value, err := GetKey(filePath, keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	if indent < 0 {
+		indent = 0
+	}
+	if indent > maxShowIndent {
+		indent = maxShowIndent
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", strings.Repeat(" ", indent))
+	encoder.SetEscapeHTML(jsonhandler.GetHandler(filePath).EscapeHTML())
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("%w: Failed to encode value at '%s': %v", ErrInvalidJSON, keyPath, err)
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// Placeholder styles understood by ListPlaceholders
+const (
+	PlaceholderHandlebars = "handlebars"
+	PlaceholderICU        = "icu"
+	PlaceholderPrintf     = "printf"
+)
+
+var (
+	// {{name}}, {{- name}} and {{name, format}} as used by i18next
+	handlebarsPattern = regexp.MustCompile(`\{\{-?\s*([^{}\s,]+)\s*(?:,[^{}]*)?\}\}`)
+	// %s, %2$d and %(name)s; %% is a literal percent sign
+	printfPattern = regexp.MustCompile(`%(?:%|(?:\(([^)]+)\)|(\d+)\$)?[-+0#]*\d*(?:\.\d+)?[sdifjoxXeEgGcbuq])`)
+)
+
+// PlaceholderUsage lists the paths whose strings reference a placeholder variable
+type PlaceholderUsage struct {
+	Name  string   `json:"name"`
+	Paths []string `json:"paths"`
+}
+
+// ListPlaceholders scans every string leaf for placeholders of the given style
+// (handlebars, icu or printf) and returns the unique variable names, sorted, with the
+// paths using each. Unnamed printf arguments are numbered by position ("1", "2", ...).
+func ListPlaceholders(filePath, style string) ([]PlaceholderUsage, error) {
+	var extract func(string) []string
+	switch style {
+	case PlaceholderHandlebars:
+		extract = handlebarsVariables
+	case PlaceholderICU:
+		extract = icuVariables
+	case PlaceholderPrintf:
+		extract = printfVariables
+	default:
+		return nil, fmt.Errorf("%w: Unknown placeholder style '%s' (expected handlebars, icu or printf)", ErrInvalidStyle, style)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := map[string][]string{}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		text, ok := value.(string)
+		if !ok {
+			return true
+		}
+
+		seen := map[string]bool{}
+		for _, name := range extract(text) {
+			if !seen[name] {
+				seen[name] = true
+				usages[name] = append(usages[name], path)
+			}
+		}
+		return true
+	})
+
+	result := make([]PlaceholderUsage, 0, len(usages))
+	for name, paths := range usages {
+		result = append(result, PlaceholderUsage{Name: name, Paths: paths})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// handlebarsVariables returns the variable names of {{...}} placeholders in text
+func handlebarsVariables(text string) []string {
+	var names []string
+	for _, match := range handlebarsPattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// printfVariables returns the argument names or positions of printf verbs in text
+func printfVariables(text string) []string {
+	var names []string
+	position := 0
+	for _, match := range printfPattern.FindAllStringSubmatch(text, -1) {
+		switch {
+		case match[0] == "%%":
+		case match[1] != "":
+			names = append(names, match[1])
+		case match[2] != "":
+			names = append(names, match[2])
+		default:
+			position++
+			names = append(names, strconv.Itoa(position))
+		}
+	}
+	return names
+}
+
+// icuVariables returns the argument names in an ICU MessageFormat string, including
+// those nested in plural and select branches
+func icuVariables(text string) []string {
+	var names []string
+	parseICUMessage(text, 0, false, &names)
+	return names
+}
+
+// parseICUMessage scans message text from i up to the end or, when nested, past the
+// closing brace of the enclosing branch, and returns where it stopped
+func parseICUMessage(text string, i int, nested bool, names *[]string) int {
+	for i < len(text) {
+		switch text[i] {
+		case '\'':
+			// '' is a literal quote; a quote before syntax characters quotes up to the next quote
+			if i+1 < len(text) && text[i+1] == '\'' {
+				i += 2
+				continue
+			}
+			if i+1 < len(text) && strings.IndexByte("{}#|", text[i+1]) >= 0 {
+				end := strings.IndexByte(text[i+1:], '\'')
+				if end < 0 {
+					return len(text)
+				}
+				i += end + 2
+				continue
+			}
+			i++
+		case '{':
+			i = parseICUArgument(text, i+1, names)
+		case '}':
+			if nested {
+				return i + 1
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// parseICUArgument parses an argument after its opening brace and returns the index
+// past its closing brace
+func parseICUArgument(text string, i int, names *[]string) int {
+	name, i := readICUName(text, i)
+	if name == "" || i >= len(text) || (text[i] != '}' && text[i] != ',') {
+		return skipICUArgument(text, i)
+	}
+	*names = append(*names, name)
+	if text[i] == '}' {
+		return i + 1
+	}
+
+	argType, i := readICUName(text, i+1)
+	if argType != "plural" && argType != "selectordinal" && argType != "select" {
+		return skipICUArgument(text, i)
+	}
+
+	for i < len(text) && text[i] != ',' && text[i] != '}' {
+		i++
+	}
+	if i >= len(text) || text[i] == '}' {
+		return i + 1
+	}
+
+	// Branches: selector {message} ...
+	for i++; i < len(text); {
+		switch text[i] {
+		case '}':
+			return i + 1
+		case '{':
+			i = parseICUMessage(text, i+1, true, names)
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// readICUName reads an identifier surrounded by optional whitespace
+func readICUName(text string, i int) (string, int) {
+	for i < len(text) && unicode.IsSpace(rune(text[i])) {
+		i++
+	}
+	start := i
+	for i < len(text) && (text[i] == '_' || unicode.IsLetter(rune(text[i])) || unicode.IsDigit(rune(text[i]))) {
+		i++
+	}
+	name := text[start:i]
+	for i < len(text) && unicode.IsSpace(rune(text[i])) {
+		i++
+	}
+	return name, i
+}
+
+// skipICUArgument skips past the brace closing the current argument
+func skipICUArgument(text string, i int) int {
+	depth := 0
+	for ; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i + 1
+			}
+			depth--
+		}
+	}
+	return i
+}
+
+// pluralCategoryNames are the CLDR plural categories
+var pluralCategoryNames = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+// pluralCategories maps a language subtag to the CLDR cardinal plural categories its
+// messages must provide
+var pluralCategories = map[string][]string{}
+
+func init() {
+	groups := map[string][]string{
+		"other": {"id", "ja", "km", "ko", "lo", "ms", "my", "th", "vi", "zh"},
+		"one other": {"af", "az", "bg", "bn", "da", "de", "el", "en", "et", "eu", "fa", "fi", "gl",
+			"hi", "hu", "hy", "is", "ka", "kk", "ky", "mk", "ml", "mn", "mr", "nb", "ne", "nl", "nn",
+			"no", "pa", "sq", "sv", "sw", "ta", "te", "tr", "ur", "uz"},
+		"one many other":              {"ca", "es", "fr", "it", "pt"},
+		"one few other":               {"bs", "hr", "ro", "sr"},
+		"one few many other":          {"be", "cs", "lt", "pl", "ru", "sk", "uk"},
+		"one two other":               {"he"},
+		"one two few other":           {"sl"},
+		"one two few many other":      {"ga"},
+		"zero one other":              {"lv"},
+		"zero one two few many other": {"ar", "cy"},
+	}
+	for categories, languages := range groups {
+		for _, language := range languages {
+			pluralCategories[language] = strings.Fields(categories)
+		}
+	}
+}
+
+// PluralFormIssue is a plural-forms object lacking categories its locale requires
+type PluralFormIssue struct {
+	Path    string   `json:"path"`
+	Missing []string `json:"missing"`
+}
+
+// CheckPluralForms finds the objects, at any depth, that look like plural forms, meaning every
+// key is a CLDR plural category (zero, one, two, few, many, other) or an exact-value key like
+// "=0", and reports those missing any category required by locale. Only the language part of
+// locale is used, so "pt-BR" and "pt_PT" both check Portuguese. Results are sorted by path.
+func CheckPluralForms(filePath, locale string) ([]PluralFormIssue, error) {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	required, ok := pluralCategories[language]
+	if !ok {
+		return nil, fmt.Errorf("%w: No plural rules known for locale '%s'", ErrInvalidLocale, locale)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []PluralFormIssue{}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		object, ok := value.(map[string]interface{})
+		if !ok || !isPluralForms(object) {
+			return true
+		}
+
+		missing := []string{}
+		for _, category := range required {
+			if _, exists := object[category]; !exists {
+				missing = append(missing, category)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, PluralFormIssue{Path: path, Missing: missing})
+		}
+		return false
+	})
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Path < issues[j].Path
+	})
+
+	return issues, nil
+}
+
+// explicitPluralKey matches an exact-value plural key such as "=0"
+var explicitPluralKey = regexp.MustCompile(`^=\d+$`)
+
+// isPluralForms reports whether every key of object is a plural category or exact-value key,
+// with at least one plural category among them
+func isPluralForms(object map[string]interface{}) bool {
+	hasCategory := false
+	for key := range object {
+		switch {
+		case pluralCategoryNames[key]:
+			hasCategory = true
+		case explicitPluralKey.MatchString(key):
+		default:
+			return false
+		}
+	}
+	return hasCategory
+}
+
+// RequiredKeysReport lists the required paths a file fails to provide
+type RequiredKeysReport struct {
+	Passed  bool     `json:"passed"`
+	Missing []string `json:"missing"`
+	Null    []string `json:"null"`
+}
+
+// RequireKeys checks that every path in requiredPaths exists. Paths holding null are
+// listed in Null; with nullIsMissing set they also count as missing and fail the check.
+func RequireKeys(filePath string, requiredPaths []string, nullIsMissing bool) (*RequiredKeysReport, error) {
+	for _, keyPath := range requiredPaths {
+		if err := pathresolver.ValidatePath(keyPath); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RequiredKeysReport{Missing: []string{}, Null: []string{}}
+	for _, keyPath := range requiredPaths {
+		value, err := pathresolver.NavigateToKey(data, keyPath)
+		switch {
+		case err != nil:
+			report.Missing = append(report.Missing, keyPath)
+		case value == nil:
+			report.Null = append(report.Null, keyPath)
+			if nullIsMissing {
+				report.Missing = append(report.Missing, keyPath)
+			}
+		}
+	}
+	report.Passed = len(report.Missing) == 0
+
+	return report, nil
+}
+
+// ValueMismatch is a string value that failed a pattern check
+type ValueMismatch struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// ValueMatchReport summarizes an AssertValuesMatch check
+type ValueMatchReport struct {
+	Checked  int             `json:"checked"`
+	Failures []ValueMismatch `json:"failures"`
+}
+
+// AssertValuesMatch checks every string value whose path matches pathGlob ('*' matches any
+// key) against the regular expression pattern and reports those that don't match, sorted
+// by path. Non-string values are skipped.
+func AssertValuesMatch(filePath, pathGlob, pattern string) (*ValueMatchReport, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Invalid regular expression '%s': %v", ErrInvalidPattern, pattern, err)
+	}
+
+	matches, err := GlobGet(filePath, pathGlob)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ValueMatchReport{Failures: []ValueMismatch{}}
+	for path, value := range matches {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		report.Checked++
+		if !re.MatchString(text) {
+			report.Failures = append(report.Failures, ValueMismatch{Path: path, Value: text})
+		}
+	}
+	sort.Slice(report.Failures, func(i, j int) bool {
+		return report.Failures[i].Path < report.Failures[j].Path
+	})
+
+	return report, nil
+}
+
+// RangeViolation is a numeric value outside the allowed range
+type RangeViolation struct {
+	Path  string  `json:"path"`
+	Value float64 `json:"value"`
+}
+
+// TypeViolation is a value that should have been a number
+type TypeViolation struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// NumericRangeReport summarizes an AssertNumericRange check
+type NumericRangeReport struct {
+	Checked    int              `json:"checked"`
+	OutOfRange []RangeViolation `json:"out_of_range"`
+	WrongType  []TypeViolation  `json:"wrong_type"`
+}
+
+// AssertNumericRange checks that every value whose path matches pathGlob ('*' matches any
+// key) is a number between min and max inclusive. Numbers outside the range and values of
+// any other type are reported separately, each sorted by path.
+func AssertNumericRange(filePath, pathGlob string, min, max float64) (*NumericRangeReport, error) {
+	if min > max {
+		return nil, fmt.Errorf("%w: Minimum %v is greater than maximum %v", ErrInvalidRange, min, max)
+	}
+
+	matches, err := GlobGet(filePath, pathGlob)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NumericRangeReport{OutOfRange: []RangeViolation{}, WrongType: []TypeViolation{}}
+	for path, value := range matches {
+		report.Checked++
+		number, ok := value.(float64)
+		if !ok {
+			report.WrongType = append(report.WrongType, TypeViolation{Path: path, Type: jsonTypeOf(value)})
+			continue
+		}
+		if number < min || number > max {
+			report.OutOfRange = append(report.OutOfRange, RangeViolation{Path: path, Value: number})
+		}
+	}
+	sort.Slice(report.OutOfRange, func(i, j int) bool {
+		return report.OutOfRange[i].Path < report.OutOfRange[j].Path
+	})
+	sort.Slice(report.WrongType, func(i, j int) bool {
+		return report.WrongType[i].Path < report.WrongType[j].Path
+	})
+
+	return report, nil
+}
+
+// DuplicateValue is a value shared by more than one array element
+type DuplicateValue struct {
+	Value   interface{} `json:"value"`
+	Indices []int       `json:"indices"`
+}
+
+// UniquenessReport lists the values repeated across an array's elements
+type UniquenessReport struct {
+	Passed     bool             `json:"passed"`
+	Duplicates []DuplicateValue `json:"duplicates"`
+	Skipped    []int            `json:"skipped"`
+}
+
+// AssertArrayUnique checks that no two elements of the array at keyPath share the same
+// uniqueField value, or with an empty uniqueField that no two elements are deep-equal.
+// Duplicates are listed in order of first occurrence. Elements without the field, or that
+// aren't objects, are listed in Skipped and don't fail the check.
+func AssertArrayUnique(filePath, keyPath, uniqueField string) (*UniquenessReport, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	array, err := arrayInData(data, filePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UniquenessReport{Duplicates: []DuplicateValue{}, Skipped: []int{}}
+	seen := map[string]int{}
+	var values []DuplicateValue
+	for i, element := range array {
+		candidate := element
+		if uniqueField != "" {
+			if candidate, err = pathresolver.NavigateToKey(element, uniqueField); err != nil {
+				report.Skipped = append(report.Skipped, i)
+				continue
+			}
+		}
+
+		// Encoding sorts object keys, so deep-equal values encode identically
+		encoded, err := json.Marshal(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Element %d of '%s' is not JSON-serializable: %v", ErrInvalidJSON, i, keyPath, err)
+		}
+		key := string(encoded)
+		if position, ok := seen[key]; ok {
+			values[position].Indices = append(values[position].Indices, i)
+			continue
+		}
+		seen[key] = len(values)
+		values = append(values, DuplicateValue{Value: candidate, Indices: []int{i}})
+	}
+
+	for _, value := range values {
+		if len(value.Indices) > 1 {
+			report.Duplicates = append(report.Duplicates, value)
+		}
+	}
+	report.Passed = len(report.Duplicates) == 0
+
+	return report, nil
+}
+
+// CacheReport describes the caching state of a file's shared handler
+type CacheReport struct {
+	File          string `json:"file"`
+	Cached        bool   `json:"cached"`
+	Hits          int64  `json:"hits"`
+	Misses        int64  `json:"misses"`
+	Reloads       int64  `json:"reloads"`
+	UncachedReads int64  `json:"uncached_reads"`
+}
+
+// CacheStats reports how reads of filePath have been served since its handler was created.
+// Counters start over if the handler is evicted from the registry.
+func CacheStats(filePath string) *CacheReport {
+	handler := jsonhandler.GetHandler(filePath)
+	stats := handler.CacheStats()
+
+	return &CacheReport{
+		File:          filePath,
+		Cached:        handler.GetFileInfo().IsCached,
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		Reloads:       stats.Reloads,
+		UncachedReads: stats.UncachedReads,
+	}
+}
+
+// ClearCache drops the cached data for filePath so the next read reparses it from disk,
+// and reports whether anything was cached
+func ClearCache(filePath string) bool {
+	handler := jsonhandler.GetHandler(filePath)
+	wasCached := handler.GetFileInfo().IsCached
+	handler.ClearCache()
+	return wasCached
+}
+
+// Integer policies understood by NormalizeNumbers
+const (
+	IntegerPolicyInt   = "int"
+	IntegerPolicyFloat = "float"
+)
+
+// NormalizeNumbers rewrites whole numbers in filePath consistently: the "int" policy
+// renders them without a decimal part (1.0 and 1e3 become 1 and 1000), the "float"
+// policy forces one (1 becomes 1.0). Other numbers keep their original text. Returns how
+// many values were reformatted. Numbers are handled as json.Number so the text round-trips;
+// note that later edits through other tools re-encode numbers in Go's default form. Like
+// other edits it fails on concurrent modification and can be undone.
+func NormalizeNumbers(filePath, integerPolicy string) (int, error) {
+	if integerPolicy != IntegerPolicyInt && integerPolicy != IntegerPolicyFloat {
+		return 0, fmt.Errorf("%w: Unknown integer policy '%s' (expected int or float)", ErrInvalidPolicy, integerPolicy)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	for attempt := 1; ; attempt++ {
+		// Loading for edit records the file's state for conflict detection; the document
+		// itself is read again as text so the numbers keep their original form
+		if _, err := handler.LoadJSONForEdit(); err != nil {
+			return 0, err
+		}
+		content, err := handler.ReadContents()
+		if err != nil {
+			return 0, err
+		}
+
+		var data map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return 0, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, filePath, err)
+		}
+
+		changed := 0
+		normalizeNumbersInValue(data, integerPolicy, &changed)
+		if changed == 0 {
+			return 0, nil
+		}
+
+		normalized, err := handler.EncodeJSON(data, 2)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrNormalizeError, err)
+		}
+
+		err = handler.ReplaceContentsForEdit(normalized)
+		if errors.Is(err, jsonhandler.ErrConflict) && attempt < maxEditAttempts {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%w: Failed to save file: %w", ErrNormalizeError, err)
+		}
+		return changed, nil
+	}
+}
+
+// normalizeNumbersInValue reformats the json.Number leaves of value in place
+func normalizeNumbersInValue(value interface{}, integerPolicy string, changed *int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = normalizeNumbersInValue(child, integerPolicy, changed)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalizeNumbersInValue(child, integerPolicy, changed)
+		}
+	case json.Number:
+		rat, ok := new(big.Rat).SetString(string(v))
+		if !ok || !rat.IsInt() {
+			return v
+		}
+
+		formatted := rat.Num().String()
+		if integerPolicy == IntegerPolicyFloat {
+			formatted += ".0"
+		}
+		if formatted != string(v) {
+			*changed++
+			return json.Number(formatted)
+		}
+	}
+	return value
+}
+
+// stringTransforms maps the transform names accepted by TransformStrings to their functions.
+// "trim" strips only spaces, "trimspace" strips all leading and trailing whitespace.
+var stringTransforms = map[string]func(string) string{
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"trim":      func(value string) string { return strings.Trim(value, " ") },
+	"trimspace": strings.TrimSpace,
+}
+
+// TransformStrings applies transform to every string leaf under the paths matching the
+// '*'-wildcard pathGlob, or to the whole document when pathGlob is empty. Non-string values
+// are left alone. Returns how many strings changed.
+func TransformStrings(filePath, transform, pathGlob string) (int, error) {
+	fn, ok := stringTransforms[transform]
+	if !ok {
+		return 0, fmt.Errorf("%w: Unknown transform '%s' (expected lower, upper, trim or trimspace)", ErrInvalidTransform, transform)
+	}
+
+	var changed int
+	err := editFile(filePath, ErrTransformError, func(data map[string]interface{}) error {
+		changed = 0
+		if pathGlob == "" {
+			transformStringsInValue(data, fn, &changed)
+		} else {
+			matches, err := pathresolver.ExpandWildcardPath(data, pathGlob)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+			}
+			for _, match := range matches {
+				// Containers are updated in place; string matches are stored back under their
+				// key, which may itself contain dots
+				if _, isString := match.Value.(string); isString {
+					match.Parent[match.Key] = transformStringsInValue(match.Value, fn, &changed)
+				} else {
+					transformStringsInValue(match.Value, fn, &changed)
+				}
+			}
+		}
+
+		if changed == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// transformStringsInValue applies fn to the string leaves of value, updating containers in place
+func transformStringsInValue(value interface{}, fn func(string) string, changed *int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = transformStringsInValue(child, fn, changed)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = transformStringsInValue(child, fn, changed)
+		}
+	case string:
+		if transformed := fn(v); transformed != v {
+			*changed++
+			return transformed
+		}
+	}
+	return value
+}
+
+// MapResult reports how MapValues went: how many leaves changed, and the leaves it skipped
+// because the operation doesn't apply to their type
+type MapResult struct {
+	Changed      int             `json:"changed"`
+	Incompatible []TypeViolation `json:"incompatible"`
+}
+
+// leafMapper applies a MapValues operation to a leaf, returning false when the leaf's type
+// doesn't support it
+type leafMapper func(value interface{}) (interface{}, bool)
+
+// valueMapper builds the leafMapper for op. "add" and "mul" take a number operand, "replace"
+// takes an object with "old" and "new" strings, "upper" and "lower" ignore the operand.
+func valueMapper(op string, operand interface{}) (leafMapper, error) {
+	switch op {
+	case "add", "mul":
+		number, ok := operand.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: Operation '%s' needs a number operand", ErrInvalidTransform, op)
+		}
+		return func(value interface{}) (interface{}, bool) {
+			current, ok := value.(float64)
+			if !ok {
+				return value, false
+			}
+			if op == "add" {
+				return current + number, true
+			}
+			return current * number, true
+		}, nil
+	case "upper", "lower":
+		fn := stringTransforms[op]
+		return func(value interface{}) (interface{}, bool) {
+			current, ok := value.(string)
+			if !ok {
+				return value, false
+			}
+			return fn(current), true
+		}, nil
+	case "replace":
+		spec, _ := operand.(map[string]interface{})
+		old, oldOK := spec["old"].(string)
+		replacement, newOK := spec["new"].(string)
+		if !oldOK || !newOK || old == "" {
+			return nil, fmt.Errorf("%w: Operation 'replace' needs an operand like {\"old\": \"...\", \"new\": \"...\"} with a non-empty old", ErrInvalidTransform)
+		}
+		return func(value interface{}) (interface{}, bool) {
+			current, ok := value.(string)
+			if !ok {
+				return value, false
+			}
+			return strings.ReplaceAll(current, old, replacement), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: Unknown operation '%s' (expected add, mul, upper, lower or replace)", ErrInvalidTransform, op)
+	}
+}
+
+// MapValues applies op to every leaf under the paths matching the '*'-wildcard pathGlob, or
+// to the whole document when pathGlob is empty. Leaves whose type op doesn't apply to, such as
+// strings for "mul", are left as they are and listed in Incompatible, sorted by path.
+func MapValues(filePath, pathGlob, op string, operand interface{}) (*MapResult, error) {
+	operand, err := normalizeJSON(operand)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Operand cannot be encoded as JSON: %v", ErrInvalidTransform, err)
+	}
+	mapper, err := valueMapper(op, operand)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *MapResult
+	err = editFile(filePath, ErrTransformError, func(data map[string]interface{}) error {
+		result = &MapResult{Incompatible: []TypeViolation{}}
+		if pathGlob == "" {
+			mapLeaves(data, "", mapper, result)
+		} else {
+			matches, err := pathresolver.ExpandWildcardPath(data, pathGlob)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+			}
+			for _, match := range matches {
+				// Containers are updated in place; leaf matches are stored back under their
+				// key, which may itself contain dots
+				mapped := mapLeaves(match.Value, match.Path, mapper, result)
+				if !isContainer(match.Value) {
+					match.Parent[match.Key] = mapped
+				}
+			}
+		}
+		sort.Slice(result.Incompatible, func(i, j int) bool {
+			return result.Incompatible[i].Path < result.Incompatible[j].Path
+		})
+
+		if result.Changed == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mapLeaves applies mapper to the leaves of value, updating containers in place
+func mapLeaves(value interface{}, path string, mapper leafMapper, result *MapResult) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = mapLeaves(child, pathresolver.JoinPath(path, key), mapper, result)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = mapLeaves(child, pathresolver.JoinPath(path, strconv.Itoa(i)), mapper, result)
+		}
+		return v
+	}
+
+	mapped, ok := mapper(value)
+	if !ok {
+		result.Incompatible = append(result.Incompatible, TypeViolation{Path: path, Type: jsonTypeOf(value)})
+		return value
+	}
+	if !reflect.DeepEqual(mapped, value) {
+		result.Changed++
+	}
+	return mapped
+}
+
+// booleanTokens maps the strings CoerceBooleans recognizes, lowercased and trimmed, to the
+// boolean they stand for
+var booleanTokens = map[string]bool{
+	"true": true, "yes": true, "y": true, "on": true, "1": true,
+	"false": false, "no": false, "n": false, "off": false, "0": false,
+}
+
+// CoerceBooleans replaces string leaves that spell a boolean with real booleans, under the
+// paths matching the '*'-wildcard pathGlob or in the whole document when pathGlob is empty.
+// Ignoring case and surrounding whitespace, "true", "yes", "y", "on" and "1" become true and
+// "false", "no", "n", "off" and "0" become false; any other string is left alone. Returns the
+// sorted paths converted.
+func CoerceBooleans(filePath, pathGlob string) ([]string, error) {
+	var converted []string
+	err := editFile(filePath, ErrTransformError, func(data map[string]interface{}) error {
+		converted = []string{}
+		if pathGlob == "" {
+			coerceBooleansIn(data, "", &converted)
+		} else {
+			matches, err := pathresolver.ExpandWildcardPath(data, pathGlob)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+			}
+			for _, match := range matches {
+				// Containers are updated in place; string matches are stored back under their
+				// key, which may itself contain dots
+				if _, isString := match.Value.(string); isString {
+					match.Parent[match.Key] = coerceBooleansIn(match.Value, match.Path, &converted)
+				} else {
+					coerceBooleansIn(match.Value, match.Path, &converted)
+				}
+			}
+		}
+		sort.Strings(converted)
+
+		if len(converted) == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+// coerceBooleansIn converts the boolean-like string leaves of value, updating containers in
+// place and recording each converted path
+func coerceBooleansIn(value interface{}, path string, converted *[]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = coerceBooleansIn(child, pathresolver.JoinPath(path, key), converted)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = coerceBooleansIn(child, pathresolver.JoinPath(path, strconv.Itoa(i)), converted)
+		}
+	case string:
+		if boolean, ok := booleanTokens[strings.ToLower(strings.TrimSpace(v))]; ok {
+			*converted = append(*converted, path)
+			return boolean
+		}
+	}
+	return value
+}
+
+// String health issues reported by CheckStringHealth
+const (
+	StringIssueInvalidUTF8        = "invalid_utf8"
+	StringIssueControlCharacters  = "control_characters"
+	StringIssueLeadingWhitespace  = "leading_whitespace"
+	StringIssueTrailingWhitespace = "trailing_whitespace"
+)
+
+// StringHealthIssue is a string value with one or more content problems
+type StringHealthIssue struct {
+	Path   string   `json:"path"`
+	Issues []string `json:"issues"`
+}
+
+// CheckStringHealth scans every string value, at any depth, for invalid UTF-8, control
+// characters other than tab, newline and carriage return, and leading or trailing whitespace.
+// The file's raw text is scanned, since decoding replaces invalid UTF-8. Results are sorted
+// by path; object keys themselves aren't checked.
+func CheckStringHealth(filePath string) ([]StringHealthIssue, error) {
+	content, err := jsonhandler.GetHandler(filePath).ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []StringHealthIssue{}
+	if err := checkStringHealthIn(content, "", &issues); err != nil {
+		return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, filePath, err)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Path < issues[j].Path
+	})
+
+	return issues, nil
+}
+
+// checkStringHealthIn appends the string health issues in the raw JSON value found at prefix
+func checkStringHealthIn(raw json.RawMessage, prefix string, issues *[]StringHealthIssue) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	switch raw[0] {
+	case '{':
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return err
+		}
+		for key, child := range object {
+			if err := checkStringHealthIn(child, pathresolver.JoinPath(prefix, key), issues); err != nil {
+				return err
+			}
+		}
+	case '[':
+		var array []json.RawMessage
+		if err := json.Unmarshal(raw, &array); err != nil {
+			return err
+		}
+		for i, child := range array {
+			if err := checkStringHealthIn(child, pathresolver.JoinPath(prefix, strconv.Itoa(i)), issues); err != nil {
+				return err
+			}
+		}
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+
+		found := []string{}
+		if !utf8.Valid(raw) {
+			found = append(found, StringIssueInvalidUTF8)
+		}
+		if strings.IndexFunc(s, isStrayControl) >= 0 {
+			found = append(found, StringIssueControlCharacters)
+		}
+		if first, _ := utf8.DecodeRuneInString(s); s != "" && unicode.IsSpace(first) {
+			found = append(found, StringIssueLeadingWhitespace)
+		}
+		if last, _ := utf8.DecodeLastRuneInString(s); s != "" && unicode.IsSpace(last) {
+			found = append(found, StringIssueTrailingWhitespace)
+		}
+		if len(found) > 0 {
+			*issues = append(*issues, StringHealthIssue{Path: prefix, Issues: found})
+		}
+	}
+	return nil
+}
+
+// isStrayControl reports whether r is a control character unlikely to belong in text
+func isStrayControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r'
+}
+
+// StripControlCharacters removes the control characters CheckStringHealth reports from every
+// string value and returns how many strings changed. Invalid UTF-8 is saved as U+FFFD, the
+// replacement decoding gives it, in any file this rewrites.
+func StripControlCharacters(filePath string) (int, error) {
+	strip := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if isStrayControl(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	var changed int
+	err := editFile(filePath, ErrTransformError, func(data map[string]interface{}) error {
+		changed = 0
+		transformStringsInValue(data, strip, &changed)
+		if changed == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// Key naming conventions understood by CheckKeyNaming
+const (
+	ConventionCamelCase = "camelCase"
+	ConventionSnakeCase = "snake_case"
+	ConventionKebabCase = "kebab-case"
+)
+
+// namingPatterns match a single key written in each naming convention
+var namingPatterns = map[string]*regexp.Regexp{
+	ConventionCamelCase: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	ConventionSnakeCase: regexp.MustCompile(`^[a-z][a-z0-9]*(?:_[a-z0-9]+)*$`),
+	ConventionKebabCase: regexp.MustCompile(`^[a-z][a-z0-9]*(?:-[a-z0-9]+)*$`),
+}
+
+// CheckKeyNaming returns the sorted paths of every object key, at any depth, whose name
+// does not follow convention. Objects inside arrays are checked too.
+func CheckKeyNaming(filePath, convention string) ([]string, error) {
+	pattern, ok := namingPatterns[convention]
+	if !ok {
+		return nil, fmt.Errorf("%w: Unknown naming convention '%s' (expected camelCase, snake_case or kebab-case)", ErrInvalidConvention, convention)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []string{}
+	checkKeyNames(data, "", pattern, &violations)
+	sort.Strings(violations)
+
+	return violations, nil
+}
+
+// checkKeyNames records the paths of keys under value, whose own path is prefix, that don't match pattern
+func checkKeyNames(value interface{}, prefix string, pattern *regexp.Regexp, violations *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := pathresolver.JoinPath(prefix, key)
+			if !pattern.MatchString(key) {
+				*violations = append(*violations, path)
+			}
+			checkKeyNames(child, path, pattern, violations)
+		}
+	case []interface{}:
+		for i, child := range v {
+			checkKeyNames(child, pathresolver.JoinPath(prefix, strconv.Itoa(i)), pattern, violations)
+		}
+	}
+}
+
+// NormalizeKeyNames renames every object key, at any depth, to convention and returns the
+// applied renames as old path to new path. The conversion depends only on the key name, so a
+// name is rewritten the same way wherever it appears. When two keys of one object end up with
+// the same name, KEY_EXISTS is returned unless suffixCollisions is set, in which case later
+// keys get a numeric suffix. Keys that already follow the convention keep their name.
+func NormalizeKeyNames(filePath, convention string, suffixCollisions bool) (map[string]string, error) {
+	if _, ok := namingPatterns[convention]; !ok {
+		return nil, fmt.Errorf("%w: Unknown naming convention '%s' (expected camelCase, snake_case or kebab-case)", ErrInvalidConvention, convention)
+	}
+
+	var renames map[string]string
+	err := editFile(filePath, ErrRenameKeyError, func(data map[string]interface{}) error {
+		renames = make(map[string]string)
+		renamed, err := renameKeysInValue(data, "", "", convention, suffixCollisions, renames)
+		if err != nil {
+			return err
+		}
+		if len(renames) == 0 {
+			return errNoChange
+		}
+
+		// The root map is owned by the caller, so replace its contents
+		for key := range data {
+			delete(data, key)
+		}
+		for key, value := range renamed.(map[string]interface{}) {
+			data[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return renames, nil
+}
+
+// renameKeysInValue returns value with its object keys converted to convention, recording
+// renamed keys in renames. oldPrefix and newPrefix are value's path before and after renaming.
+func renameKeysInValue(value interface{}, oldPrefix, newPrefix, convention string, suffixCollisions bool, renames map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// Keys that don't change claim their names first
+		names := make(map[string]string, len(v))
+		taken := make(map[string]bool, len(v))
+		for _, key := range keys {
+			if convertKeyName(key, convention) == key {
+				names[key] = key
+				taken[key] = true
+			}
+		}
+		for _, key := range keys {
+			if _, done := names[key]; done {
+				continue
+			}
+
+			name := convertKeyName(key, convention)
+			if taken[name] {
+				if !suffixCollisions {
+					return nil, fmt.Errorf("%w: Renaming '%s' to '%s' collides with another key", ErrKeyExists,
+						pathresolver.JoinPath(oldPrefix, key), pathresolver.JoinPath(newPrefix, name))
+				}
+				name = suffixKeyName(name, convention, taken)
+			}
+			names[key] = name
+			taken[name] = true
+			renames[pathresolver.JoinPath(oldPrefix, key)] = pathresolver.JoinPath(newPrefix, name)
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for _, key := range keys {
+			child, err := renameKeysInValue(v[key], pathresolver.JoinPath(oldPrefix, key), pathresolver.JoinPath(newPrefix, names[key]), convention, suffixCollisions, renames)
+			if err != nil {
+				return nil, err
+			}
+			result[names[key]] = child
+		}
+		return result, nil
+	case []interface{}:
+		for i, child := range v {
+			index := strconv.Itoa(i)
+			renamed, err := renameKeysInValue(child, pathresolver.JoinPath(oldPrefix, index), pathresolver.JoinPath(newPrefix, index), convention, suffixCollisions, renames)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = renamed
+		}
+	}
+	return value, nil
+}
+
+// suffixKeyName appends the smallest numeric suffix, starting at 2, that makes name unique
+func suffixKeyName(name, convention string, taken map[string]bool) string {
+	separator := ""
+	switch convention {
+	case ConventionSnakeCase:
+		separator = "_"
+	case ConventionKebabCase:
+		separator = "-"
+	}
+
+	for n := 2; ; n++ {
+		candidate := name + separator + strconv.Itoa(n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// convertKeyName rewrites key in convention. Words are split at any character that is not a
+// letter or digit and at case changes ("HTTPServer" is "http" and "server"). A key without any
+// words is returned unchanged.
+func convertKeyName(key, convention string) string {
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	switch convention {
+	case ConventionSnakeCase:
+		return strings.Join(words, "_")
+	case ConventionKebabCase:
+		return strings.Join(words, "-")
+	}
+
+	var builder strings.Builder
+	builder.WriteString(words[0])
+	for _, word := range words[1:] {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+// splitKeyWords splits key into lowercase words
+func splitKeyWords(key string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = nil
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			previous := current[len(current)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// Start a word at "aB", and at the last capital of an acronym as in "HTTPServer"
+			if !unicode.IsUpper(previous) || nextIsLower {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// KeyRenameReport lists the keys renamed by RenameKeyEverywhere and those it had to skip
+type KeyRenameReport struct {
+	Renamed   map[string]string `json:"renamed"`
+	Conflicts []string          `json:"conflicts"`
+}
+
+// RenameKeyEverywhere renames every object key named oldName, at any depth and inside arrays,
+// to newName. Renamed keys are reported as old path to new path. A key whose object already
+// has a newName key is left alone and its path listed, sorted, in Conflicts.
+func RenameKeyEverywhere(filePath, oldName, newName string) (*KeyRenameReport, error) {
+	if oldName == "" || newName == "" {
+		return nil, fmt.Errorf("%w: Key names cannot be empty", ErrInvalidName)
+	}
+	if oldName == newName {
+		return nil, fmt.Errorf("%w: Old and new key names are both '%s'", ErrSameKey, oldName)
+	}
+
+	var report *KeyRenameReport
+	err := editFile(filePath, ErrRenameKeyError, func(data map[string]interface{}) error {
+		report = &KeyRenameReport{Renamed: map[string]string{}, Conflicts: []string{}}
+		renameKeyEverywhereIn(data, "", "", oldName, newName, report)
+		sort.Strings(report.Conflicts)
+		if len(report.Renamed) == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// renameKeyEverywhereIn renames oldName keys in value in place. oldPrefix and newPrefix are
+// value's path before and after renaming.
+func renameKeyEverywhereIn(value interface{}, oldPrefix, newPrefix, oldName, newName string, report *KeyRenameReport) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		for _, key := range keys {
+			name := key
+			if key == oldName {
+				if _, exists := v[newName]; exists {
+					report.Conflicts = append(report.Conflicts, pathresolver.JoinPath(oldPrefix, key))
+				} else {
+					name = newName
+				}
+			}
+
+			child := v[key]
+			renameKeyEverywhereIn(child, pathresolver.JoinPath(oldPrefix, key), pathresolver.JoinPath(newPrefix, name), oldName, newName, report)
+			if name != key {
+				delete(v, key)
+				v[name] = child
+				report.Renamed[pathresolver.JoinPath(oldPrefix, key)] = pathresolver.JoinPath(newPrefix, name)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			index := strconv.Itoa(i)
+			renameKeyEverywhereIn(child, pathresolver.JoinPath(oldPrefix, index), pathresolver.JoinPath(newPrefix, index), oldName, newName, report)
+		}
+	}
+}
+
+// GetKeyDepth returns how many path segments keyPath resolves through, so a root-level key
+// has depth 1. A root key containing dots counts as one segment.
+func GetKeyDepth(filePath, keyPath string) (int, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(keyPath, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := pathresolver.NavigateToKey(data, keyPath); err != nil {
+		if errors.Is(err, pathresolver.ErrKeyNotFound) {
+			return 0, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		if errors.Is(err, pathresolver.ErrInvalidPath) {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+		return 0, fmt.Errorf("PATH_ERROR: %v", err)
+	}
+
+	// Like GetKey, prefer a literal dotted key at the root
+	if _, literal := data[keyPath]; literal {
+		return 1, nil
+	}
+	return len(pathresolver.SplitPath(keyPath)), nil
+}
+
+// AncestorValue is the value found at one prefix of a path
+type AncestorValue struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// GetAncestors returns the value at each prefix of keyPath, outermost first and ending with
+// keyPath itself, so "a.b.c" yields the values at a, a.b and a.b.c. A root key containing
+// dots is a single level.
+func GetAncestors(filePath, keyPath string) ([]AncestorValue, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(keyPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like GetKey, prefer a literal dotted key at the root
+	if value, literal := data[keyPath]; literal {
+		return []AncestorValue{{Path: keyPath, Value: value}}, nil
+	}
+
+	var ancestors []AncestorValue
+	var current interface{} = data
+	path := ""
+	for _, segment := range pathresolver.SplitPath(keyPath) {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		current, ok = object[segment]
+		if !ok {
+			return nil, fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, keyPath, filePath)
+		}
+		path = pathresolver.JoinPath(path, segment)
+		ancestors = append(ancestors, AncestorValue{Path: path, Value: current})
+	}
+	return ancestors, nil
+}
+
+// MaxDepth returns the depth of the most deeply nested value in the document, counting array
+// elements as a level. An empty document has depth 0.
+func MaxDepth(filePath string) (int, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return 0, err
+	}
+
+	return valueDepth(data), nil
+}
+
+// valueDepth returns how many levels of nesting lie below value
+func valueDepth(value interface{}) int {
+	deepest := 0
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			deepest = max(deepest, 1+valueDepth(child))
+		}
+	case []interface{}:
+		for _, child := range v {
+			deepest = max(deepest, 1+valueDepth(child))
+		}
+	}
+	return deepest
+}
+
+// PathDepth is a path together with how many segments it has
+type PathDepth struct {
+	Path  string `json:"path"`
+	Depth int    `json:"depth"`
+}
+
+// DeepestPath returns the path with the most segments in the document, counting array
+// elements as a segment as MaxDepth does. Ties go to the path visited first, with keys in
+// sorted order. An empty document yields an empty path of depth 0.
+func DeepestPath(filePath string) (*PathDepth, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	deepest := &PathDepth{}
+	findDeepestPath(data, "", 0, deepest)
+	return deepest, nil
+}
+
+// findDeepestPath records in deepest the first path below value, found at path and depth,
+// that is deeper than any seen so far
+func findDeepestPath(value interface{}, path string, depth int, deepest *PathDepth) {
+	if depth > deepest.Depth {
+		deepest.Path, deepest.Depth = path, depth
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			findDeepestPath(v[key], pathresolver.JoinPath(path, key), depth+1, deepest)
+		}
+	case []interface{}:
+		for i, child := range v {
+			findDeepestPath(child, pathresolver.JoinPath(path, strconv.Itoa(i)), depth+1, deepest)
+		}
+	}
+}
+
+// CreateFile writes a new JSON file holding initial, or an empty object if initial is nil,
+// creating its parent directories as needed. An existing file is only replaced when
+// overwrite is set.
+func CreateFile(filePath string, initial map[string]interface{}, overwrite bool) error {
+	if initial == nil {
+		initial = map[string]interface{}{}
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	fileInfo, err := os.Stat(filePath)
+	switch {
+	case err == nil && !fileInfo.Mode().IsRegular():
+		return fmt.Errorf("%w: %s exists and is not a regular file", jsonhandler.ErrNotRegularFile, filePath)
+	case err == nil && !overwrite:
+		return fmt.Errorf("%w: %s already exists", ErrFileExists, filePath)
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("%w: Failed to stat %s: %v", jsonhandler.ErrFileReadError, filePath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("%w: Failed to create %s: %v", ErrCreateError, filepath.Dir(filePath), err)
+	}
+	if err := handler.SaveJSON(initial, 2); err != nil {
+		return fmt.Errorf("%w: Failed to write %s: %w", ErrCreateError, filePath, err)
+	}
+	return nil
+}
+
+// SplitByTopKey writes each top-level key's subtree to <outputDir>/<key>.json and returns the
+// files written, sorted by key. Every top-level value must be an object and every key usable
+// as a file name. Existing output files are only replaced when overwrite is set; all checks
+// happen before anything is written.
+func SplitByTopKey(filePath, outputDir string, overwrite bool) ([]string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	outputFiles := make([]string, len(keys))
+	for i, key := range keys {
+		if _, ok := data[key].(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%w: Top-level key '%s' is not an object", ErrNotContainer, key)
+		}
+		if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+			return nil, fmt.Errorf("%w: Top-level key '%s' cannot be used as a file name", ErrSplitError, key)
+		}
+
+		outputFiles[i] = filepath.Join(outputDir, key+".json")
+		if !overwrite {
+			if _, err := os.Stat(outputFiles[i]); err == nil {
+				return nil, fmt.Errorf("%w: %s already exists", ErrSplitError, outputFiles[i])
+			}
+		}
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("%w: Failed to create %s: %v", ErrSplitError, outputDir, err)
+	}
+	for i, key := range keys {
+		if err := writeDerivedDocument(filePath, outputFiles[i], data[key].(map[string]interface{}), false, ErrSplitError); err != nil {
+			return nil, err
+		}
+	}
+
+	return outputFiles, nil
+}
+
+// CombineFiles writes the documents in inputFiles to outputFile as one document, the inverse
+// of SplitByTopKey. With keyFromFilename set each document is nested under its file name
+// without extension (dashboard.json becomes "dashboard"); otherwise their top-level keys are
+// merged at the root. A top-level key produced by more than one input is an error. Returns
+// the top-level keys written, sorted.
+func CombineFiles(inputFiles []string, outputFile string, keyFromFilename, createDirs bool) ([]string, error) {
+	if len(inputFiles) == 0 {
+		return nil, fmt.Errorf("%w: No input files given", ErrCombineError)
+	}
+
+	combined := make(map[string]interface{})
+	sources := make(map[string]string)
+	add := func(key string, value interface{}, inputFile string) error {
+		if previous, exists := sources[key]; exists {
+			return fmt.Errorf("%w: Top-level key '%s' comes from both %s and %s", ErrKeyExists, key, previous, inputFile)
+		}
+		combined[key] = value
+		sources[key] = inputFile
+		return nil
+	}
+
+	for _, inputFile := range inputFiles {
+		same, err := sameFile(inputFile, outputFile)
+		if err != nil {
+			return nil, err
+		}
+		if same {
+			return nil, fmt.Errorf("%w: Output file must differ from input %s", ErrCombineError, inputFile)
+		}
+
+		data, err := jsonhandler.GetHandler(inputFile).LoadJSON(false)
+		if err != nil {
+			return nil, err
+		}
+
+		if keyFromFilename {
+			base := filepath.Base(inputFile)
+			if err := add(strings.TrimSuffix(base, filepath.Ext(base)), data, inputFile); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for key, value := range data {
+			if err := add(key, value, inputFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := ensureOutputDir(outputFile, createDirs, ErrCombineError); err != nil {
+		return nil, err
+	}
+	if err := jsonhandler.GetHandler(outputFile).SaveJSON(combined, 2); err != nil {
+		return nil, fmt.Errorf("%w: Failed to write %s: %w", ErrCombineError, outputFile, err)
+	}
+
+	keys := make([]string, 0, len(combined))
+	for key := range combined {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// ChangeRecord lists the paths changed by one save of a file
+type ChangeRecord struct {
+	Time  time.Time `json:"time"`
+	Paths []string  `json:"paths"`
+}
+
+// RecentChanges returns up to limit records of the paths changed by saves to filePath made
+// while the server runs, newest first. A limit of zero or less returns every record kept.
+// Changes made by other processes are not seen.
+func RecentChanges(filePath string, limit int) []ChangeRecord {
+	handlerRecords := jsonhandler.GetHandler(filePath).RecentChanges(limit)
+
+	records := make([]ChangeRecord, len(handlerRecords))
+	for i, record := range handlerRecords {
+		records[i] = ChangeRecord{Time: record.Time, Paths: record.Paths}
+	}
+	return records
+}
+
+// TypeMismatch is a path whose JSON type differs from the reference document
+type TypeMismatch struct {
+	Path     string `json:"path"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// ShapeReport is the result of checking a file against a reference shape
+type ShapeReport struct {
+	Passed         bool           `json:"passed"`
+	Missing        []string       `json:"missing"`
+	TypeMismatches []TypeMismatch `json:"type_mismatches"`
+	Extra          []string       `json:"extra,omitempty"`
+}
+
+// ValidateShape checks that every path in shapeFile exists in targetFile with the same JSON
+// type; values themselves are not compared. Arrays are compared by type only, not element by
+// element. In strict mode paths of targetFile missing from the shape are reported as Extra
+// and fail the check. All lists are sorted.
+func ValidateShape(targetFile, shapeFile string, strict bool) (*ShapeReport, error) {
+	target, err := jsonhandler.GetHandler(targetFile).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	shape, err := jsonhandler.GetHandler(shapeFile).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ShapeReport{Missing: []string{}, TypeMismatches: []TypeMismatch{}}
+	if strict {
+		report.Extra = []string{}
+	}
+	compareShape(shape, target, "", strict, report)
+
+	sort.Strings(report.Missing)
+	sort.Strings(report.Extra)
+	sort.Slice(report.TypeMismatches, func(i, j int) bool {
+		return report.TypeMismatches[i].Path < report.TypeMismatches[j].Path
+	})
+	report.Passed = len(report.Missing) == 0 && len(report.TypeMismatches) == 0 && len(report.Extra) == 0
+
+	return report, nil
+}
+
+// compareShape records how the object target, at path prefix, departs from the object shape
+func compareShape(shape, target map[string]interface{}, prefix string, strict bool, report *ShapeReport) {
+	for key, expected := range shape {
+		path := pathresolver.JoinPath(prefix, key)
+		actual, exists := target[key]
+		if !exists {
+			report.Missing = append(report.Missing, path)
+			continue
+		}
+
+		expectedType, actualType := jsonTypeOf(expected), jsonTypeOf(actual)
+		if expectedType != actualType {
+			report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{Path: path, Expected: expectedType, Actual: actualType})
+			continue
+		}
+		if expectedType == "object" {
+			compareShape(expected.(map[string]interface{}), actual.(map[string]interface{}), path, strict, report)
+		}
+	}
+
+	if strict {
+		for key := range target {
+			if _, exists := shape[key]; !exists {
+				report.Extra = append(report.Extra, pathresolver.JoinPath(prefix, key))
+			}
+		}
+	}
+}
+
+// TypeDifference is a path present in two documents with a different JSON type in each
+type TypeDifference struct {
+	Path  string `json:"path"`
+	TypeA string `json:"type_a"`
+	TypeB string `json:"type_b"`
+}
+
+// TypeDiff reports every path present in both fileA and fileB whose JSON type differs,
+// sorted by path. Objects and arrays of the same type are compared key by key and index by
+// index; paths present in only one file are ignored.
+func TypeDiff(fileA, fileB string) ([]TypeDifference, error) {
+	dataA, err := jsonhandler.GetHandler(fileA).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	dataB, err := jsonhandler.GetHandler(fileB).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := []TypeDifference{}
+	compareTypes(dataA, dataB, "", &differences)
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+
+	return differences, nil
+}
+
+// compareTypes appends the type differences between a and b, found at path prefix
+func compareTypes(a, b interface{}, prefix string, differences *[]TypeDifference) {
+	typeA, typeB := jsonTypeOf(a), jsonTypeOf(b)
+	if typeA != typeB {
+		*differences = append(*differences, TypeDifference{Path: prefix, TypeA: typeA, TypeB: typeB})
+		return
+	}
+
+	switch aVal := a.(type) {
+	case map[string]interface{}:
+		bVal := b.(map[string]interface{})
+		for key, aChild := range aVal {
+			if bChild, exists := bVal[key]; exists {
+				compareTypes(aChild, bChild, pathresolver.JoinPath(prefix, key), differences)
+			}
+		}
+	case []interface{}:
+		bVal := b.([]interface{})
+		for i := 0; i < len(aVal) && i < len(bVal); i++ {
+			compareTypes(aVal[i], bVal[i], pathresolver.JoinPath(prefix, strconv.Itoa(i)), differences)
+		}
+	}
+}
+
+// RefResolution is a document with its local $ref references inlined
+type RefResolution struct {
+	Document map[string]interface{} `json:"document"`
+	Resolved int                    `json:"resolved"`
+}
+
+// ResolveRefs replaces every object of the form {"$ref": "#/json/pointer"} with a copy of the
+// value the pointer refers to, resolving references inside inlined values too. Other members
+// of a $ref object are dropped. References to other documents are left as they are. A
+// reference that leads back to itself fails with CIRCULAR_REFERENCE. The result is written
+// to outputFile when given; the source file is never modified.
+func ResolveRefs(filePath, outputFile string, createDirs bool) (*RefResolution, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := &refResolver{root: data, active: make(map[string]bool)}
+	resolved, err := resolver.resolve(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RefResolution{Document: resolved.(map[string]interface{}), Resolved: resolver.resolved}
+	if err := writeDerivedDocument(filePath, outputFile, result.Document, createDirs, ErrRefError); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// refResolver inlines local $ref references, tracking the pointers being expanded to detect cycles
+type refResolver struct {
+	root     interface{}
+	active   map[string]bool
+	resolved int
+}
+
+// resolve returns a copy of value with its local references inlined
+func (r *refResolver) resolve(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			return r.resolveRef(ref)
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			resolved, err := r.resolve(child)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			resolved, err := r.resolve(child)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+// resolveRef returns the resolved target of a local reference such as "#/definitions/x"
+func (r *refResolver) resolveRef(ref string) (interface{}, error) {
+	pointer := ref[1:]
+	if r.active[pointer] {
+		return nil, fmt.Errorf("%w: Reference '%s' refers back to itself", ErrCircularRef, ref)
+	}
+
+	target, err := lookupPointer(r.root, pointer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Cannot resolve '%s': %v", ErrRefError, ref, err)
+	}
+
+	r.active[pointer] = true
+	defer delete(r.active, pointer)
+
+	resolved, err := r.resolve(target)
+	if err != nil {
+		return nil, err
+	}
+	r.resolved++
+	return resolved, nil
+}
+
+// lookupPointer returns the value a JSON Pointer (RFC 6901) refers to within root
+func lookupPointer(root interface{}, pointer string) (interface{}, error) {
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer must start with '/'")
+	}
+
+	current := root
+	for _, token := range unescapePointer(pointer) {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, exists := v[token]
+			if !exists {
+				return nil, fmt.Errorf("key '%s' not found", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("invalid array index '%s'", token)
+			}
+			current = v[index]
+		default:
+			return nil, fmt.Errorf("cannot descend into %s at '%s'", jsonTypeOf(current), token)
+		}
+	}
+	return current, nil
+}
+
+// ListByType returns the path of every value, at any depth, whose JSON type is jsonType
+// (string, number, boolean, null, object or array). Array elements are addressed by index.
+// Paths are listed depth-first with object keys in sorted order.
+func ListByType(filePath, jsonType string) ([]string, error) {
+	switch jsonType {
+	case "string", "number", "boolean", "null", "object", "array":
+	default:
+		return nil, fmt.Errorf("%w: Unknown JSON type '%s' (expected string, number, boolean, null, object or array)", ErrInvalidType, jsonType)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		if jsonTypeOf(value) == jsonType {
+			paths = append(paths, path)
+		}
+		return true
+	})
+
+	return paths, nil
+}
+
+// GroupPathsByType returns the path of every value, at any depth, grouped by JSON type like
+// ListByType. Every type is present in the result, with an empty list if nothing has it.
+func GroupPathsByType(filePath string) (map[string][]string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]string{}
+	for _, jsonType := range []string{"string", "number", "boolean", "null", "object", "array"} {
+		groups[jsonType] = []string{}
+	}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		jsonType := jsonTypeOf(value)
+		groups[jsonType] = append(groups[jsonType], path)
+		return true
+	})
+
+	return groups, nil
+}
+
+// PruneEmpty removes every empty object and array from filePath, and null values too when
+// removeNull is set. Children are pruned before their parent, so a container left empty by
+// pruning is removed as well; the root object is kept even when empty. Returns the sorted
+// paths removed, with array indices as they were before pruning.
+func PruneEmpty(filePath string, removeNull bool) ([]string, error) {
+	var removed []string
+	err := editFile(filePath, ErrRemoveKeyError, func(data map[string]interface{}) error {
+		removed = []string{}
+		pruneEmptyIn(data, "", removeNull, &removed)
+		if len(removed) == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// pruneEmptyIn prunes the children of value, whose path is prefix, and returns the pruned value
+func pruneEmptyIn(value interface{}, prefix string, removeNull bool, removed *[]string) interface{} {
+	prunable := func(child interface{}) bool {
+		return (child == nil && removeNull) || (isContainer(child) && containerLen(child) == 0)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := pathresolver.JoinPath(prefix, key)
+			child = pruneEmptyIn(child, path, removeNull, removed)
+			if prunable(child) {
+				delete(v, key)
+				*removed = append(*removed, path)
+				continue
+			}
+			v[key] = child
+		}
+	case []interface{}:
+		kept := v[:0]
+		for i, child := range v {
+			path := pathresolver.JoinPath(prefix, strconv.Itoa(i))
+			child = pruneEmptyIn(child, path, removeNull, removed)
+			if prunable(child) {
+				*removed = append(*removed, path)
+				continue
+			}
+			kept = append(kept, child)
+		}
+		return kept
+	}
+	return value
+}
+
+// SectionHashes returns a SHA-256 hash, hex encoded, of every top-level key's subtree. Each
+// subtree is hashed in a canonical form (compact, object keys sorted), so the hashes don't
+// depend on formatting or key order and two files can be compared section by section.
+func SectionHashes(filePath string) (map[string]string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(data))
+	for key, value := range data {
+		// encoding/json writes map keys in sorted order
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: Failed to encode '%s': %v", ErrInvalidJSON, key, err)
+		}
+		sum := sha256.Sum256(encoded)
+		hashes[key] = hex.EncodeToString(sum[:])
+	}
+
+	return hashes, nil
+}
+
+// LineError is a line of a line-oriented input that could not be parsed
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// NDJSONImport is the result of importing a newline-delimited JSON file
+type NDJSONImport struct {
+	Records int         `json:"records"`
+	Skipped []LineError `json:"skipped"`
+}
+
+// ImportNDJSON reads one JSON value per line of ndjsonFile and writes them to jsonFile as an
+// array, nested under key, or as the whole document when key is empty. Blank lines are
+// ignored. A malformed line aborts the import unless skipInvalid is set, in which case it is
+// reported in Skipped. jsonFile is replaced if it exists.
+func ImportNDJSON(ndjsonFile, jsonFile, key string, skipInvalid, createDirs bool) (*NDJSONImport, error) {
+	same, err := sameFile(ndjsonFile, jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		return nil, fmt.Errorf("%w: Output file must differ from %s", ErrNDJSONError, ndjsonFile)
+	}
+
+	content, err := jsonhandler.GetHandler(ndjsonFile).ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []interface{}{}
+	result := &NDJSONImport{Skipped: []LineError{}}
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var record interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			if !skipInvalid {
+				return nil, fmt.Errorf("%w: Line %d of %s is not valid JSON: %v", ErrNDJSONError, i+1, ndjsonFile, err)
+			}
+			result.Skipped = append(result.Skipped, LineError{Line: i + 1, Message: err.Error()})
+			continue
+		}
+		records = append(records, record)
+	}
+	result.Records = len(records)
+
+	if err := ensureOutputDir(jsonFile, createDirs, ErrNDJSONError); err != nil {
+		return nil, err
+	}
+	handler := jsonhandler.GetHandler(jsonFile)
+	if key != "" {
+		if err := handler.SaveJSON(map[string]interface{}{key: records}, 2); err != nil {
+			return nil, fmt.Errorf("%w: Failed to write %s: %w", ErrNDJSONError, jsonFile, err)
+		}
+		return result, nil
+	}
+
+	// A top-level array can't go through SaveJSON, which only writes objects
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(handler.EscapeHTML())
+	if err := encoder.Encode(records); err != nil {
+		return nil, fmt.Errorf("%w: Failed to encode JSON: %v", ErrNDJSONError, err)
+	}
+	if err := handler.ReplaceContents(buffer.Bytes()); err != nil {
+		return nil, fmt.Errorf("%w: Failed to write %s: %w", ErrNDJSONError, jsonFile, err)
+	}
+
+	return result, nil
+}
+
+// ExportNDJSON writes each element of the array at keyPath to outputFile as newline-delimited
+// JSON, one minified element per line, and returns how many lines were written. An empty
+// keyPath exports a file whose whole document is an array. outputFile is replaced if it exists.
+func ExportNDJSON(filePath, keyPath, outputFile string, createDirs bool) (int, error) {
+	same, err := sameFile(filePath, outputFile)
+	if err != nil {
+		return 0, err
+	}
+	if same {
+		return 0, fmt.Errorf("%w: Output file must differ from %s", ErrNDJSONError, filePath)
+	}
+
+	var value interface{}
+	if keyPath == "" {
+		// A top-level array can't be loaded through LoadJSON, which only reads objects
+		content, err := jsonhandler.GetHandler(filePath).ReadContents()
+		if err != nil {
+			return 0, err
+		}
+		if err := json.Unmarshal(content, &value); err != nil {
+			return 0, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, filePath, err)
+		}
+	} else {
+		value, err = GetKey(filePath, keyPath)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	array, ok := value.([]interface{})
+	if !ok {
+		location := "root"
+		if keyPath != "" {
+			location = "'" + keyPath + "'"
+		}
+		return 0, fmt.Errorf("%w: Value at %s is %s, not an array", ErrNotArray, location, jsonTypeOf(value))
+	}
+
+	// Encode writes compact JSON followed by a newline; strings never contain raw newlines
+	handler := jsonhandler.GetHandler(outputFile)
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(handler.EscapeHTML())
+	for i, element := range array {
+		if err := encoder.Encode(element); err != nil {
+			return 0, fmt.Errorf("%w: Failed to encode element %d: %v", ErrNDJSONError, i, err)
+		}
+	}
+
+	if err := ensureOutputDir(outputFile, createDirs, ErrNDJSONError); err != nil {
+		return 0, err
+	}
+	if err := handler.ReplaceContents(buffer.Bytes()); err != nil {
+		return 0, fmt.Errorf("%w: Failed to write %s: %w", ErrNDJSONError, outputFile, err)
+	}
+
+	return len(array), nil
+}
+
+// EffectiveValueResult is the value a key resolves to across layered files
+type EffectiveValueResult struct {
+	KeyPath   string      `json:"key_path"`
+	Value     interface{} `json:"value"`
+	Source    string      `json:"source"`
+	DefinedIn []string    `json:"defined_in"`
+}
+
+// EffectiveValue deep-merges files in order, later files winning, and returns the value of
+// keyPath in the merged document. Objects are merged key by key; any other value replaces
+// what came before. Source is the last file that defines keyPath, and so provides the value
+// (or, for an object, its last layer); DefinedIn lists every file that defines it, in order.
+// Nothing is written.
+func EffectiveValue(keyPath string, files []string) (*EffectiveValueResult, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: No files given", ErrInvalidPath)
+	}
+
+	result := &EffectiveValueResult{KeyPath: keyPath, DefinedIn: []string{}}
+	merged := make(map[string]interface{})
+	for _, file := range files {
+		// Private copies, so merging can take ownership of their maps
+		data, err := jsonhandler.GetHandler(file).LoadJSON(false)
+		if err != nil {
+			return nil, err
+		}
+
+		if pathresolver.KeyExists(data, keyPath) {
+			result.DefinedIn = append(result.DefinedIn, file)
+			result.Source = file
+		}
+		mergeObjects(merged, data)
+	}
+
+	value, err := pathresolver.NavigateToKey(merged, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Key '%s' not found in the merged files", ErrKeyNotFound, keyPath)
+	}
+	result.Value = value
+
+	return result, nil
+}
+
+// mergeObjects deep-merges src into dst; values from src win unless both sides are objects
+func mergeObjects(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeObjects(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// MissingKeys returns the paths in expectedPaths that don't resolve in filePath, in the order
+// given and without duplicates
+func MissingKeys(filePath string, expectedPaths []string) ([]string, error) {
+	for _, keyPath := range expectedPaths {
+		if err := pathresolver.ValidatePath(keyPath); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	seen := make(map[string]bool, len(expectedPaths))
+	for _, keyPath := range expectedPaths {
+		if seen[keyPath] {
+			continue
+		}
+		seen[keyPath] = true
+
+		if !pathresolver.KeyExists(data, keyPath) {
+			missing = append(missing, keyPath)
+		}
+	}
+
+	return missing, nil
+}
+
+// UnusedKeys returns the leaf paths of filePath, in walk order, not covered by usedPaths. A
+// used path covers the leaf itself and, when it names an object or array, every leaf under it.
+// Leaves under any of alwaysUsed are never reported.
+func UnusedKeys(filePath string, usedPaths, alwaysUsed []string) ([]string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	covered := append(append([]string{}, usedPaths...), alwaysUsed...)
+	unused := []string{}
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		if isContainer(value) {
+			return true
+		}
+		for _, prefix := range covered {
+			if isPathWithin(path, prefix) {
+				return true
+			}
+		}
+		unused = append(unused, path)
+		return true
+	})
+
+	return unused, nil
+}
+
+// isPathWithin reports whether path is prefix or nested under it
+func isPathWithin(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+".")
+}
+
+// CompletenessReport summarizes how much of a reference document a target translates
+type CompletenessReport struct {
+	Percentage float64 `json:"percentage"`
+	Total      int     `json:"total"`
+	Translated int     `json:"translated"`
+	Missing    int     `json:"missing"`
+	Empty      int     `json:"empty"`
+}
+
+// Completeness reports the percentage, rounded to two decimals, of referenceFile's leaf paths
+// that targetFile translates. A leaf counts as translated when the target holds a value at its
+// path that isn't null, a blank string or an empty object or array. Arrays, such as lists of
+// plural forms, are compared as a single leaf, and empty ones hold nothing to translate. An
+// empty reference is reported as 100% complete.
+func Completeness(referenceFile, targetFile string) (*CompletenessReport, error) {
+	reference, err := jsonhandler.GetHandler(referenceFile).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+	target, err := jsonhandler.GetHandler(targetFile).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompletenessReport{}
+	pathresolver.Walk(reference, func(path string, value interface{}) bool {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			return true
+		case []interface{}:
+			if len(v) == 0 {
+				return false
+			}
+		}
+		report.Total++
+
+		translated, err := pathresolver.NavigateToKey(target, path)
+		switch {
+		case err != nil:
+			report.Missing++
+		case isEmptyTranslation(translated):
+			report.Empty++
+		default:
+			report.Translated++
+		}
+		return false
+	})
+
+	report.Percentage = 100
+	if report.Total > 0 {
+		report.Percentage = math.Round(float64(report.Translated)*10000/float64(report.Total)) / 100
+	}
+
+	return report, nil
+}
+
+// isEmptyTranslation reports whether value leaves a translation effectively unfilled
+func isEmptyTranslation(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case map[string]interface{}, []interface{}:
+		return containerLen(v) == 0
+	}
+	return false
+}
+
+// FillMissingFromReference adds to targetFile every leaf of referenceFile whose path is missing
+// there, copying the reference value with markPrefix prepended to its strings, and returns the
+// added paths in walk order. Arrays and empty objects are copied as single values. Existing
+// target values are never overwritten, and nothing is added beneath a target value that
+// isn't an object.
+func FillMissingFromReference(targetFile, referenceFile, markPrefix string) ([]string, error) {
+	reference, err := jsonhandler.GetHandler(referenceFile).LoadJSON(true)
+	if err != nil {
+		return nil, err
+	}
+
+	mark := func(s string) string { return markPrefix + s }
+
+	// fill copies what target lacks from reference, key by key; keys may contain dots, so
+	// the two documents are walked side by side rather than through joined paths
+	var added []string
+	var fill func(target, reference map[string]interface{}, prefix string) error
+	fill = func(target, reference map[string]interface{}, prefix string) error {
+		keys := make([]string, 0, len(reference))
+		for key := range reference {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := reference[key]
+			path := pathresolver.JoinPath(prefix, key)
+			existing, exists := target[key]
+
+			if object, ok := value.(map[string]interface{}); ok && len(object) > 0 {
+				// Descend into objects the target lacks or also has as an object; a
+				// non-object value in the target blocks everything beneath it
+				child, isObject := existing.(map[string]interface{})
+				if exists && !isObject {
+					continue
+				}
+				if !exists {
+					child = map[string]interface{}{}
+				}
+				before := len(added)
+				if err := fill(child, object, path); err != nil {
+					return err
+				}
+				if !exists && len(added) > before {
+					target[key] = child
+				}
+				continue
+			}
+			if exists {
+				continue
+			}
+
+			// The reference is the handler's cached document, so add a copy of it
+			filled, err := normalizeJSON(value)
+			if err != nil {
+				return fmt.Errorf("%w: Failed to copy '%s' from %s: %v", ErrAddKeyError, path, referenceFile, err)
+			}
+			if markPrefix != "" {
+				var changed int
+				filled = transformStringsInValue(filled, mark, &changed)
+			}
+			target[key] = filled
+			added = append(added, path)
+		}
+		return nil
+	}
+
+	err = editFile(targetFile, ErrAddKeyError, func(data map[string]interface{}) error {
+		added = []string{}
+		if err := fill(data, reference, ""); err != nil {
+			return err
+		}
+
+		if len(added) == 0 {
+			return errNoChange
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
+
+// PathPresence is a leaf path that some of the compared files lack
+type PathPresence struct {
+	Path        string   `json:"path"`
+	MissingFrom []string `json:"missing_from"`
+}
+
+// KeyMatrix summarizes which leaf paths each of a set of files provides
+type KeyMatrix struct {
+	Files        []string       `json:"files"`
+	TotalPaths   int            `json:"total_paths"`
+	MissingCount map[string]int `json:"missing_count"`
+	Missing      []PathPresence `json:"missing"`
+}
+
+// MultiFileKeyMatrix takes the union of the leaf paths of files and lists, sorted by path,
+// every path that some files lack together with those files, in the order given. Paths every
+// file has are only counted in TotalPaths. MissingCount gives each file's number of gaps.
+func MultiFileKeyMatrix(files []string) (*KeyMatrix, error) {
+	present, paths, err := leafPathSets(files)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &KeyMatrix{
+		Files:        files,
+		TotalPaths:   len(paths),
+		MissingCount: make(map[string]int, len(files)),
+		Missing:      []PathPresence{},
+	}
+	for _, file := range files {
+		matrix.MissingCount[file] = 0
+	}
+	for _, path := range paths {
+		var missingFrom []string
+		for i, file := range files {
+			if !present[i][path] {
+				missingFrom = append(missingFrom, file)
+				matrix.MissingCount[file]++
+			}
+		}
+		if len(missingFrom) > 0 {
+			matrix.Missing = append(matrix.Missing, PathPresence{Path: path, MissingFrom: missingFrom})
+		}
+	}
+
+	return matrix, nil
+}
+
+// leafPathSets loads files and returns the set of leaf paths of each, in the order given,
+// along with the sorted union of all of them
+func leafPathSets(files []string) ([]map[string]bool, []string, error) {
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("%w: No files given", ErrInvalidPath)
+	}
+
+	present := make([]map[string]bool, len(files))
+	union := map[string]bool{}
+	for i, file := range files {
+		data, err := jsonhandler.GetHandler(file).LoadJSON(true)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		present[i] = map[string]bool{}
+		pathresolver.Walk(data, func(path string, value interface{}) bool {
+			if isContainer(value) {
+				return true
+			}
+			present[i][path] = true
+			union[path] = true
+			return true
+		})
+	}
+
+	paths := make([]string, 0, len(union))
+	for path := range union {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return present, paths, nil
+}
+
+// FileParity lists how one file's leaf paths differ from the rest of a set. Missing are paths
+// another file has and this one lacks; Extra are paths this file has and another one lacks.
+type FileParity struct {
+	File    string   `json:"file"`
+	Missing []string `json:"missing"`
+	Extra   []string `json:"extra"`
+}
+
+// ParityReport says whether a set of files has identical leaf paths, and where not, how each
+// file differs
+type ParityReport struct {
+	Identical bool         `json:"identical"`
+	Files     []FileParity `json:"files,omitempty"`
+}
+
+// StrictKeyParity checks that every file has exactly the same set of leaf paths. When they
+// differ, Files lists every file that doesn't have the full union of paths or has paths some
+// other file lacks, in the order given, with sorted paths.
+func StrictKeyParity(files []string) (*ParityReport, error) {
+	present, paths, err := leafPathSets(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// Paths every file has are neither missing nor extra anywhere
+	shared := map[string]bool{}
+	for _, path := range paths {
+		shared[path] = true
+		for i := range files {
+			if !present[i][path] {
+				shared[path] = false
+				break
+			}
+		}
+	}
+
+	report := &ParityReport{Identical: true}
+	for i, file := range files {
+		parity := FileParity{File: file, Missing: []string{}, Extra: []string{}}
+		for _, path := range paths {
+			switch {
+			case shared[path]:
+			case present[i][path]:
+				parity.Extra = append(parity.Extra, path)
+			default:
+				parity.Missing = append(parity.Missing, path)
+			}
+		}
+		if len(parity.Missing) > 0 || len(parity.Extra) > 0 {
+			report.Identical = false
+			report.Files = append(report.Files, parity)
+		}
+	}
+
+	return report, nil
+}
+
+// PreviewSave returns exactly what saving filePath's current document with indent spaces per
+// level would write, including the file's escape-html setting and the trailing newline,
+// without writing anything. Edits made through the other operations save with an indent of 2.
+func PreviewSave(filePath string, indent int) (string, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := handler.EncodeJSON(data, indent)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// templateVarPattern matches a ${name} reference in a template string
+var templateVarPattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// ApplyTemplate replaces every ${name} in the string values of template with vars[name] and
+// sets the result at keyPath, creating parent objects as needed. Any reference to a variable
+// missing from vars fails with UNDEFINED_VARIABLE. An existing value at keyPath is only
+// replaced if it is null or an empty object or array, unless force is set.
+func ApplyTemplate(filePath, keyPath string, template map[string]interface{}, vars map[string]string, force bool) error {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	undefined := map[string]bool{}
+	value := substituteTemplateVars(template, vars, undefined)
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		for name := range undefined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%w: Template variables %s are not defined", ErrUndefinedVar, strings.Join(names, ", "))
+	}
+
+	return editFile(filePath, ErrAddKeyError, func(data map[string]interface{}) error {
+		if existing, err := pathresolver.NavigateToKey(data, keyPath); err == nil && !force {
+			if existing != nil && !(isContainer(existing) && containerLen(existing) == 0) {
+				return fmt.Errorf("%w: Key '%s' already holds a value in %s", ErrKeyExists, keyPath, filePath)
+			}
+		}
+
+		if err := pathresolver.SetValueAtPath(data, keyPath, value, true); err != nil {
+			return fmt.Errorf("%w: Failed to set '%s': %v", ErrAddKeyError, keyPath, err)
+		}
+		return nil
+	})
+}
+
+// substituteTemplateVars returns a copy of value with ${name} references replaced from vars
+// in every string, recording the names missing from vars in undefined
+func substituteTemplateVars(value interface{}, vars map[string]string, undefined map[string]bool) interface{} {
+	switch v := value.(type) {
+	case string:
+		return templateVarPattern.ReplaceAllStringFunc(v, func(match string) string {
+			name := match[2 : len(match)-1]
+			if resolved, ok := vars[name]; ok {
+				return resolved
+			}
+			undefined[name] = true
+			return match
+		})
+	case map[string]interface{}:
+		substituted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			substituted[key] = substituteTemplateVars(child, vars, undefined)
+		}
+		return substituted
+	case []interface{}:
+		substituted := make([]interface{}, len(v))
+		for i, child := range v {
+			substituted[i] = substituteTemplateVars(child, vars, undefined)
+		}
+		return substituted
+	}
+	return value
+}
+
+// GraftSubtree sets the value at sourceKeyPath in sourceFile, or the whole source document when
+// sourceKeyPath is empty, at keyPath in targetFile, creating parent objects as needed and
+// replacing any existing value. The grafted value is a copy, so later edits to either file
+// don't affect the other.
+func GraftSubtree(targetFile, keyPath, sourceFile, sourceKeyPath string) error {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	if sourceKeyPath != "" {
+		if err := pathresolver.ValidatePath(sourceKeyPath); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+	}
+
+	source, err := jsonhandler.GetHandler(sourceFile).LoadJSON(true)
+	if err != nil {
+		return err
+	}
+
+	var value interface{} = source
+	if sourceKeyPath != "" {
+		value, err = pathresolver.NavigateToKey(source, sourceKeyPath)
+		if err != nil {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, sourceKeyPath, sourceFile)
+		}
+	}
+
+	// The source is the handler's cached document, so graft a copy of it
+	grafted, err := normalizeJSON(value)
+	if err != nil {
+		return fmt.Errorf("%w: Failed to copy '%s' from %s: %v", ErrGraftError, sourceKeyPath, sourceFile, err)
+	}
+
+	return editFile(targetFile, ErrGraftError, func(data map[string]interface{}) error {
+		if err := pathresolver.SetValueAtPath(data, keyPath, grafted, true); err != nil {
+			return fmt.Errorf("%w: Failed to set '%s': %v", ErrGraftError, keyPath, err)
+		}
+		return nil
+	})
+}
+
+// CopyWithin sets destPath to a deep copy of the value at srcPath in the same file, creating
+// parent objects as needed. The source is left in place. An existing value at destPath is
+// only replaced when overwrite is set.
+func CopyWithin(filePath, srcPath, destPath string, overwrite bool) error {
+	for _, keyPath := range []string{srcPath, destPath} {
+		if err := pathresolver.ValidatePath(keyPath); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+	}
+
+	return editFile(filePath, ErrCopyError, func(data map[string]interface{}) error {
+		value, err := pathresolver.NavigateToKey(data, srcPath)
+		if err != nil {
+			return fmt.Errorf("%w: Key '%s' not found in %s", ErrKeyNotFound, srcPath, filePath)
+		}
+
+		if pathresolver.KeyExists(data, destPath) && !overwrite {
+			return fmt.Errorf("%w: Key '%s' already exists in %s", ErrKeyExists, destPath, filePath)
+		}
+
+		copied, err := normalizeJSON(value)
+		if err != nil {
+			return fmt.Errorf("%w: Failed to copy '%s': %v", ErrCopyError, srcPath, err)
+		}
+
+		if err := pathresolver.SetValueAtPath(data, destPath, copied, true); err != nil {
+			if errors.Is(err, pathresolver.ErrPathConflict) {
+				return fmt.Errorf("PATH_CONFLICT: %v", err)
+			}
+			return fmt.Errorf("%w: Failed to set '%s': %v", ErrCopyError, destPath, err)
+		}
+		return nil
+	})
+}
+
+// ReorderToMatch rewrites targetFile so its object keys appear in the order they have in
+// referenceFile, recursively, keeping keys the reference lacks after the matched ones in their
+// existing order. Values are written back with their original text. It returns how many
+// objects changed order. Other edits save keys sorted, so they undo this ordering.
+func ReorderToMatch(targetFile, referenceFile string) (int, error) {
+	reference, err := readOrderedObject(referenceFile)
+	if err != nil {
+		return 0, err
+	}
+
+	handler := jsonhandler.GetHandler(targetFile)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	target, err := readOrderedObject(targetFile)
+	if err != nil {
+		return 0, err
+	}
+
+	reordered := reorderValue(target, reference)
+	if reordered == 0 {
+		return 0, nil
+	}
+
+	content, err := jsonhandler.EncodeOrdered(target, 2, handler.EscapeHTML())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrReorderError, err)
+	}
+	if err := handler.ReplaceContents(content); err != nil {
+		return 0, fmt.Errorf("%w: Failed to save file: %w", ErrReorderError, err)
+	}
+
+	return reordered, nil
+}
+
+// readOrderedObject reads filePath keeping key order and checks that its root is an object
+func readOrderedObject(filePath string) (*jsonhandler.OrderedObject, error) {
+	content, err := jsonhandler.GetHandler(filePath).ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := jsonhandler.DecodeOrdered(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: File %s contains invalid JSON: %v", ErrInvalidJSON, filePath, err)
+	}
+	object, ok := value.(*jsonhandler.OrderedObject)
+	if !ok {
+		return nil, fmt.Errorf("%w: Root of %s is not a JSON object", ErrReorderError, filePath)
+	}
+	return object, nil
+}
+
+// reorderValue puts the keys of target's objects into reference's order in place, pairing
+// array elements by index, and returns how many objects changed order
+func reorderValue(target, reference interface{}) int {
+	reordered := 0
+
+	switch t := target.(type) {
+	case *jsonhandler.OrderedObject:
+		r, ok := reference.(*jsonhandler.OrderedObject)
+		if !ok {
+			return 0
+		}
+
+		keys := make([]string, 0, len(t.Keys))
+		for _, key := range r.Keys {
+			if _, exists := t.Values[key]; exists {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range t.Keys {
+			if _, exists := r.Values[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		for i, key := range keys {
+			if t.Keys[i] != key {
+				reordered++
+				break
+			}
+		}
+		t.Keys = keys
+
+		for _, key := range keys {
+			if referenceValue, exists := r.Values[key]; exists {
+				reordered += reorderValue(t.Values[key], referenceValue)
+			}
+		}
+	case []interface{}:
+		r, ok := reference.([]interface{})
+		if !ok {
+			return 0
+		}
+		for i := 0; i < len(t) && i < len(r); i++ {
+			reordered += reorderValue(t[i], r[i])
+		}
+	}
+
+	return reordered
+}
+
+// DiffContextLines is the number of unchanged lines shown around each change by UnifiedDiff
+const DiffContextLines = 3
+
+// diffLine is one line of an edit script: ' ' kept, '-' removed or '+' added
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// UnifiedDiff returns a line-based unified diff from before to after in the format of diff -u,
+// labelling the sides fromName and toName. It returns an empty string if the contents match.
+func UnifiedDiff(fromName, toName string, before, after []byte) string {
+	if bytes.Equal(before, after) {
+		return ""
+	}
+
+	script := removalsFirst(diffLines(splitDiffLines(before), splitDiffLines(after)))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+
+	// oldLine and newLine count the lines of each side before script[i]
+	oldLine, newLine := 0, 0
+	for i := 0; i < len(script); {
+		if script[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Grow the hunk while the next change is close enough for their context to touch
+		start := max(i-DiffContextLines, 0)
+		end := i
+		for j := i; j < len(script) && j <= end+2*DiffContextLines+1; j++ {
+			if script[j].kind != ' ' {
+				end = j
+			}
+		}
+		end = min(end+DiffContextLines+1, len(script))
+
+		oldStart, newStart := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		for _, line := range script[start:end] {
+			if line.kind != '+' {
+				oldCount++
+			}
+			if line.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, line := range script[start:end] {
+			builder.WriteByte(line.kind)
+			builder.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				builder.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		for _, line := range script[i:end] {
+			if line.kind != '+' {
+				oldLine++
+			}
+			if line.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return builder.String()
+}
+
+// removalsFirst reorders each run of changed lines so its removals come before its additions,
+// as diff -u prints them
+func removalsFirst(script []diffLine) []diffLine {
+	for start := 0; start < len(script); {
+		if script[start].kind == ' ' {
+			start++
+			continue
+		}
+		end := start
+		for end < len(script) && script[end].kind != ' ' {
+			end++
+		}
+		sort.SliceStable(script[start:end], func(i, j int) bool {
+			return script[start+i].kind == '-' && script[start+j].kind == '+'
+		})
+		start = end
+	}
+	return script
+}
+
+// hunkRange formats one side of a hunk header from the number of lines before it and its length
+func hunkRange(linesBefore, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", linesBefore)
+	case 1:
+		return strconv.Itoa(linesBefore + 1)
+	}
+	return fmt.Sprintf("%d,%d", linesBefore+1, count)
+}
+
+// splitDiffLines splits content into lines, each keeping its newline
+func splitDiffLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script turning a into b with Myers' algorithm,
+// after setting aside the common prefix and suffix
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var script []diffLine
+	for _, line := range a[:prefix] {
+		script = append(script, diffLine{' ', line})
+	}
+	script = append(script, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		script = append(script, diffLine{' ', line})
+	}
+	return script
+}
+
+// myersDiff finds the edit script with the linear-space variant of Myers' algorithm: it
+// searches forward from the start and backward from the end at once, splits both sides
+// where the two searches meet and diffs the halves separately. Only one row of furthest
+// reaching points per direction is kept, so memory stays O(N+M) however many lines change.
+func myersDiff(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceLines(a, b)
+	}
+
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	// With an odd delta the searches can only meet while extending forward, otherwise backward
+	delta := n - m
+	checkForward := delta%2 != 0
+
+	// Diagonals that ran off the edit graph are skipped from then on
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case checkForward:
+				if i := offset + delta - k; i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return splitDiff(a, b, x, y)
+				}
+			}
+		}
+
+		// Backward paths count x and y from the ends of a and b
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !checkForward:
+				if i := offset + delta - k; i >= 0 && i < len(forward) && forward[i] != -1 {
+					forwardX := forward[i]
+					if forwardX >= n-x {
+						return splitDiff(a, b, forwardX, offset+forwardX-i)
+					}
+				}
+			}
+		}
+	}
+
+	// No line in common
+	return replaceLines(a, b)
+}
+
+// splitDiff diffs a[:x] against b[:y] and a[x:] against b[y:] and joins the scripts
+func splitDiff(a, b []string, x, y int) []diffLine {
+	return append(diffLines(a[:x], b[:y]), diffLines(a[x:], b[y:])...)
+}
+
+// replaceLines is the edit script removing every line of a and then adding every line of b
+func replaceLines(a, b []string) []diffLine {
+	script := make([]diffLine, 0, len(a)+len(b))
+	for _, line := range a {
+		script = append(script, diffLine{'-', line})
+	}
+	for _, line := range b {
+		script = append(script, diffLine{'+', line})
+	}
+	return script
+}
+
+// NestFlatKeys expands the top-level keys of filePath that contain delimiter into nested
+// objects, so {"a.b.c": 1} becomes {"a": {"b": {"c": 1}}}. Objects meeting at the same path
+// are merged; any other collision is an error and leaves the file untouched. Returns the
+// expanded keys in sorted order.
+func NestFlatKeys(filePath, delimiter string) ([]string, error) {
+	if delimiter == "" {
+		return nil, fmt.Errorf("%w: Delimiter must not be empty", ErrInvalidPath)
+	}
+
+	var nested []string
+	err := editFile(filePath, ErrNestError, func(data map[string]interface{}) error {
+		nested = nil
+		for key := range data {
+			if strings.Contains(key, delimiter) {
+				nested = append(nested, key)
+			}
+		}
+		if len(nested) == 0 {
+			return errNoChange
+		}
+		sort.Strings(nested)
+
+		for _, key := range nested {
+			value := data[key]
+			delete(data, key)
+
+			segments := strings.Split(key, delimiter)
+			for _, segment := range segments {
+				if segment == "" {
+					return fmt.Errorf("%w: Key '%s' has an empty segment", ErrInvalidPath, key)
+				}
+			}
+
+			current := data
+			for i, segment := range segments[:len(segments)-1] {
+				child, exists := current[segment]
+				if !exists {
+					child = make(map[string]interface{})
+					current[segment] = child
+				}
+				object, ok := child.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%w: Cannot nest '%s': '%s' already holds a non-object value in %s", ErrKeyExists, key, strings.Join(segments[:i+1], "."), filePath)
+				}
+				current = object
+			}
+
+			if err := mergeNestedValue(current, segments[len(segments)-1], value, strings.Join(segments, ".")); err != nil {
+				return fmt.Errorf("%w: Cannot nest '%s': %v in %s", ErrKeyExists, key, err, filePath)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nested, nil
+}
+
+// mergeNestedValue sets target[key] to value, merging objects that meet at the same key.
+// path names target[key] in errors.
+func mergeNestedValue(target map[string]interface{}, key string, value interface{}, path string) error {
+	existing, exists := target[key]
+	if !exists {
+		target[key] = value
+		return nil
+	}
+
+	existingObject, ok := existing.(map[string]interface{})
+	valueObject, ok2 := value.(map[string]interface{})
+	if !ok || !ok2 {
+		return fmt.Errorf("'%s' already holds a value", path)
+	}
+
+	keys := make([]string, 0, len(valueObject))
+	for child := range valueObject {
+		keys = append(keys, child)
+	}
+	sort.Strings(keys)
+	for _, child := range keys {
+		if err := mergeNestedValue(existingObject, child, valueObject[child], path+"."+child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tsIdentifierPattern matches names usable unquoted as TypeScript identifiers
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// GenerateTSInterface describes the document in filePath as TypeScript interfaces, starting
+// with one named rootName. Each object becomes an interface named after its path, arrays
+// become T[] with the element types merged (object elements share one interface, and keys
+// not present in every element are optional), and a value that can be null is typed T | null.
+func GenerateTSInterface(filePath, rootName string) (string, error) {
+	if !tsIdentifierPattern.MatchString(rootName) {
+		return "", fmt.Errorf("%w: '%s' is not a valid TypeScript identifier", ErrInvalidName, rootName)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSON(true)
+	if err != nil {
+		return "", err
+	}
+
+	generator := &tsGenerator{used: map[string]bool{}}
+	generator.objectType([]map[string]interface{}{data}, rootName)
+	return strings.Join(generator.interfaces, "\n"), nil
+}
+
+// tsGenerator collects the interfaces emitted by GenerateTSInterface
+type tsGenerator struct {
+	interfaces []string
+	used       map[string]bool
+}
+
+// objectType emits one interface covering all of objects and returns its name
+func (g *tsGenerator) objectType(objects []map[string]interface{}, name string) string {
+	for base, n := name, 2; g.used[name]; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	g.used[name] = true
+
+	// Reserve the slot first so an interface comes before those nested in it
+	slot := len(g.interfaces)
+	g.interfaces = append(g.interfaces, "")
+
+	values := map[string][]interface{}{}
+	for _, object := range objects {
+		for key, value := range object {
+			values[key] = append(values[key], value)
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "export interface %s {", name)
+	if len(keys) > 0 {
+		builder.WriteString("\n")
+	}
+	for _, key := range keys {
+		property := key
+		if !tsIdentifierPattern.MatchString(key) {
+			quoted, _ := json.Marshal(key)
+			property = string(quoted)
+		}
+		if len(values[key]) < len(objects) {
+			property += "?"
+		}
+		fmt.Fprintf(&builder, "  %s: %s;\n", property, g.valueType(values[key], name+tsTypeName(key)))
+	}
+	builder.WriteString("}\n")
+
+	g.interfaces[slot] = builder.String()
+	return name
+}
+
+// valueType returns the TypeScript type covering all of values, naming any interface it
+// needs after name
+func (g *tsGenerator) valueType(values []interface{}, name string) string {
+	var objects []map[string]interface{}
+	var elements []interface{}
+	present := map[string]bool{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			objects = append(objects, v)
+		case []interface{}:
+			elements = append(elements, v...)
+		}
+		present[jsonTypeOf(value)] = true
+	}
+
+	var types []string
+	if len(objects) > 0 {
+		types = append(types, g.objectType(objects, name))
+	}
+	if present["array"] {
+		element := "unknown"
+		if len(elements) > 0 {
+			element = g.valueType(elements, name+"Item")
+		}
+		if strings.Contains(element, " | ") {
+			element = "(" + element + ")"
+		}
+		types = append(types, element+"[]")
+	}
+	for _, scalar := range []string{"string", "number", "boolean", "null"} {
+		if present[scalar] {
+			types = append(types, scalar)
+		}
+	}
+
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, " | ")
+}
+
+// tsTypeName turns a key into a PascalCase fragment of an interface name
+func tsTypeName(key string) string {
+	var builder strings.Builder
+	for _, word := range splitKeyWords(key) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	if builder.Len() == 0 {
+		return "Field"
+	}
+	return builder.String()
+}
+
+// ListKeys lists all immediate child keys at the specified path
+func ListKeys(filePath string, keyPath *string) ([]string, error) {
+	accessed := ""
+	if keyPath != nil {
+		accessed = *keyPath
+	}
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(accessed, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := pathresolver.GetAllKeysAtPath(data, keyPath)
+	if err != nil {
+		if errors.Is(err, pathresolver.ErrKeyNotFound) {
+			pathDesc := "root"
+			if keyPath != nil {
+				pathDesc = fmt.Sprintf("'%s'", *keyPath)
+			}
+			return nil, fmt.Errorf("%w: Key %s not found in %s", ErrKeyNotFound, pathDesc, filePath)
+		}
+		if errors.Is(err, pathresolver.ErrNotObject) {
+			return nil, fmt.Errorf("NOT_OBJECT: %v", err)
+		}
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// KeyPage is one page of the sorted keys of an object
+type KeyPage struct {
+	Keys    []string `json:"keys"`
+	Offset  int      `json:"offset"`
+	Total   int      `json:"total"`
+	HasMore bool     `json:"has_more"`
+}
+
+// ListKeysPage returns up to limit immediate child keys at keyPath, in sorted order, starting
+// at offset. A limit of 0 returns every key from offset on. An offset past the end yields an
+// empty page.
+func ListKeysPage(filePath string, keyPath *string, offset, limit int) (*KeyPage, error) {
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("%w: Offset and limit must not be negative", ErrInvalidRange)
+	}
+
+	keys, err := ListKeys(filePath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	start := min(offset, len(keys))
+	end := len(keys)
+	if limit > 0 {
+		end = min(start+limit, len(keys))
+	}
+
+	return &KeyPage{
+		Keys:    keys[start:end],
+		Offset:  offset,
+		Total:   len(keys),
+		HasMore: end < len(keys),
+	}, nil
+}
+
+// KeyPreview is a child key together with its value's type and a short preview of it
+type KeyPreview struct {
+	Key     string `json:"key"`
+	Type    string `json:"type"`
+	Preview string `json:"preview"`
+}
+
+// ListKeysWithValues lists the immediate child keys at keyPath like ListKeys, sorted, each
+// with a preview of its value: containers are summarized as {n keys} or [n items], and scalars are
+// shown as compact JSON truncated to previewLen characters (zero or less disables the limit).
+func ListKeysWithValues(filePath string, keyPath *string, previewLen int) ([]KeyPreview, error) {
+	accessed := ""
+	if keyPath != nil {
+		accessed = *keyPath
+	}
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(accessed, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent interface{} = data
+	pathDesc := "root"
+	if keyPath != nil {
+		pathDesc = fmt.Sprintf("'%s'", *keyPath)
+		if parent, err = pathresolver.NavigateToKey(data, *keyPath); err != nil {
+			if errors.Is(err, pathresolver.ErrInvalidPath) {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+			}
+			return nil, fmt.Errorf("%w: Key %s not found in %s", ErrKeyNotFound, pathDesc, filePath)
+		}
+	}
+	object, ok := parent.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: Value at %s is not an object, cannot list keys", ErrInvalidPath, pathDesc)
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	previews := make([]KeyPreview, 0, len(keys))
+	for _, key := range keys {
+		value := object[key]
+		preview := renderTreeValue(value, previewLen)
+		if isContainer(value) {
+			preview = summarizeContainer(value)
+		}
+		previews = append(previews, KeyPreview{Key: key, Type: jsonTypeOf(value), Preview: preview})
+	}
+	return previews, nil
+}
+
+// KeyExists checks if a key exists at the specified path
+func KeyExists(filePath, keyPath string) (bool, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(keyPath, true)
+	if err != nil {
+		return false, err
+	}
+
+	return pathresolver.KeyExists(data, keyPath), nil
+}
+
+// ProbeResult tells a missing key, a key holding null and a key holding a value apart
+type ProbeResult struct {
+	Exists bool        `json:"exists"`
+	IsNull bool        `json:"is_null"`
+	Value  interface{} `json:"value"`
+}
+
+// Probe looks up keyPath without treating a missing key as an error. A path that runs
+// through a value that isn't an object counts as missing.
+func Probe(filePath, keyPath string) (*ProbeResult, error) {
+	if err := pathresolver.ValidatePath(keyPath); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+
+	handler := jsonhandler.GetHandler(filePath)
+	data, err := handler.LoadJSONAt(keyPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := pathresolver.NavigateToKey(data, keyPath)
+	if err != nil {
+		return &ProbeResult{}, nil
+	}
+
+	return &ProbeResult{Exists: true, IsNull: value == nil, Value: value}, nil
+}
+
+// BenchmarkResult is the cost of one load-walk-save cycle on a file. Times are in seconds.
+type BenchmarkResult struct {
+	FileSize      int64   `json:"file_size"`
+	Values        int     `json:"values"`
+	ParseTime     float64 `json:"parse_time"`
+	WalkTime      float64 `json:"walk_time"`
+	SerializeTime float64 `json:"serialize_time"`
+	WriteTime     float64 `json:"write_time"`
+	MemoryBytes   int64   `json:"memory_bytes"`
+}
+
+// Benchmark times a fresh load of filePath, a walk over every value and a save of the loaded
+// document. The save is written to a temporary file next to filePath and then removed, so
+// the file itself is never touched. MemoryBytes estimates the decoded document's footprint.
+func Benchmark(filePath string) (*BenchmarkResult, error) {
+	handler := jsonhandler.GetHandler(filePath)
+	result := &BenchmarkResult{}
+
+	start := time.Now()
+	data, err := handler.LoadJSON(false)
+	if err != nil {
+		return nil, err
+	}
+	result.ParseTime = time.Since(start).Seconds()
+
+	if fileInfo, err := os.Stat(filePath); err == nil {
+		result.FileSize = fileInfo.Size()
+	}
+
+	start = time.Now()
+	pathresolver.Walk(data, func(path string, value interface{}) bool {
+		result.Values++
+		return true
+	})
+	result.WalkTime = time.Since(start).Seconds()
+
+	start = time.Now()
+	content, err := handler.EncodeJSON(data, 2)
+	if err != nil {
+		return nil, err
+	}
+	result.SerializeTime = time.Since(start).Seconds()
+
+	tempFile, err := os.CreateTemp(filepath.Dir(filePath), "*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to create temp file: %v", jsonhandler.ErrFileWriteError, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	start = time.Now()
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: Failed to write temp file: %v", jsonhandler.ErrFileWriteError, err)
+	}
+	result.WriteTime = time.Since(start).Seconds()
+
+	result.MemoryBytes = approxMemory(data)
+	return result, nil
+}
+
+// approxMemory estimates the bytes a decoded value occupies on a 64-bit platform: interface
+// and string headers, map entries and slice elements, and the string data itself
+func approxMemory(value interface{}) int64 {
+	const ifaceSize, stringHeader, sliceHeader, mapOverhead = 16, 16, 24, 48
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		size := int64(mapOverhead)
+		for key, child := range v {
+			size += stringHeader + int64(len(key)) + ifaceSize + approxMemory(child)
+		}
+		return size
+	case []interface{}:
+		size := int64(sliceHeader)
+		for _, child := range v {
+			size += ifaceSize + approxMemory(child)
+		}
+		return size
+	case string:
+		return stringHeader + int64(len(v))
+	case float64:
+		return 8
+	case bool:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ValidationResult represents the result of JSON validation
+type ValidationResult struct {
+	Valid       bool                                   `json:"valid"`
+	File        string                                 `json:"file"`
+	Error       *jsonhandler.ValidationError           `json:"error,omitempty"`
+	ErrorType   string                                 `json:"error_type,omitempty"`
+	Performance *jsonhandler.PerformanceMetrics        `json:"performance,omitempty"`
+	Errors      []jsonhandler.ValidationError          `json:"errors,omitempty"`
+}
+
+// ValidateJSON validates JSON file syntax and structure
+func ValidateJSON(filePath string) (*ValidationResult, error) {
+	return ValidateJSONWithOptions(filePath, false)
+}
+
+// ValidateJSONWithOptions validates like ValidateJSON; with collectAllErrors set, a file
+// with syntax errors is scanned past the first one and Errors lists all that were found
+func ValidateJSONWithOptions(filePath string, collectAllErrors bool) (*ValidationResult, error) {
+	startTime := time.Now()
+	handler := jsonhandler.GetHandler(filePath)
+
+	// Get basic file info
+	fileInfo := handler.GetFileInfo()
+
+	var result *jsonhandler.ValidationResult
+	if collectAllErrors {
+		result = handler.ValidateJSONSyntaxAll()
+	} else {
+		result = handler.ValidateJSONSyntax()
+	}
+
+	// Add performance metrics if valid
+	if result.Valid && result.Performance != nil {
+		result.Performance.ParseTime = time.Since(startTime).Seconds()
+		if fileInfo.Exists {
+			result.Performance.FileSize = fileInfo.SizeBytes
+		}
+	}
+
+	// Convert to our result type
+	validationResult := &ValidationResult{
+		Valid:       result.Valid,
+		File:        result.File,
+		Error:       result.Error,
+		ErrorType:   result.ErrorType,
+		Performance: result.Performance,
+		Errors:      result.Errors,
+	}
+
+	return validationResult, nil
+}
+
+// ValidateJSONStream validates like ValidateJSON, but scans the file's tokens as they are read
+// instead of loading it, so it works on files of any size. progress, if not nil, receives the
+// bytes processed so far and the file size as the scan goes.
+func ValidateJSONStream(filePath string, progress jsonhandler.ProgressFunc) (*ValidationResult, error) {
+	result := jsonhandler.GetHandler(filePath).ValidateJSONStream(progress)
+
+	return &ValidationResult{
+		Valid:       result.Valid,
+		File:        result.File,
+		Error:       result.Error,
+		ErrorType:   result.ErrorType,
+		Performance: result.Performance,
+	}, nil
+}
+
+// Usability tells whether a file can be operated on: it exists, holds valid JSON, and that
+// JSON is an object
+type Usability struct {
+	Exists    bool `json:"exists"`
+	ValidJSON bool `json:"valid_json"`
+	IsObject  bool `json:"is_object"`
+}
+
+// IsUsable checks in one read whether filePath exists and holds a valid JSON object. Problems
+// that stop the file being read at all, such as exceeding the size limit, are returned as
+// errors.
+func IsUsable(filePath string) (*Usability, error) {
+	content, err := jsonhandler.GetHandler(filePath).ReadContents()
+	switch {
+	case errors.Is(err, jsonhandler.ErrFileNotFound):
+		return &Usability{}, nil
+	case errors.Is(err, jsonhandler.ErrInvalidJSON):
+		// The content could not be transcoded to UTF-8
+		return &Usability{Exists: true}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		return &Usability{Exists: true}, nil
+	}
+	_, isObject := value.(map[string]interface{})
+
+	return &Usability{Exists: true, ValidJSON: true, IsObject: isObject}, nil
+}
+
+// SyntaxDiagnosis lists the common hand-editing mistakes found in a file
+type SyntaxDiagnosis struct {
+	Valid  bool                      `json:"valid"`
+	Issues []jsonhandler.SyntaxIssue `json:"issues"`
+}
+
+// DiagnoseJSON reports the trailing commas, single-quoted strings and unquoted object keys in
+// filePath, each with its location and a suggested fix. Only these mistakes are recognized; a
+// file can be invalid for other reasons with no issues listed, which Valid shows.
+func DiagnoseJSON(filePath string) (*SyntaxDiagnosis, error) {
+	content, err := jsonhandler.GetHandler(filePath).ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := jsonhandler.DiagnoseSyntax(content)
+	if issues == nil {
+		issues = []jsonhandler.SyntaxIssue{}
+	}
+
+	return &SyntaxDiagnosis{Valid: json.Valid(content), Issues: issues}, nil
+}
+
+// RepairResult lists the repairs made to a file and, when not written out, the repaired text
+type RepairResult struct {
+	Changes []jsonhandler.SyntaxIssue `json:"changes"`
+	Content string                    `json:"content,omitempty"`
+}
+
+// RepairJSON fixes the mistakes DiagnoseJSON finds, removing trailing commas, double-quoting
+// single-quoted strings and quoting bareword keys, and leaves the rest of the text untouched.
+// The repair must produce valid JSON or nothing is written. The result goes to outputFile,
+// which must differ from filePath, or with an empty outputFile is returned as Content.
+func RepairJSON(filePath, outputFile string, createDirs bool) (*RepairResult, error) {
+	content, err := jsonhandler.GetHandler(filePath).ReadContents()
+	if err != nil {
+		return nil, err
+	}
+
+	repaired, changes := jsonhandler.RepairSyntax(content)
+	if changes == nil {
+		changes = []jsonhandler.SyntaxIssue{}
+	}
+	var probe interface{}
+	if err := json.Unmarshal(repaired, &probe); err != nil {
+		return nil, fmt.Errorf("%w: %s is still not valid JSON after %d repair(s): %v", ErrRepairError, filePath, len(changes), err)
+	}
+
+	result := &RepairResult{Changes: changes}
+	if outputFile == "" {
+		result.Content = string(repaired)
+		return result, nil
+	}
+
+	same, err := sameFile(filePath, outputFile)
+	if err != nil {
+		return nil, err
+	}
+	if same {
+		return nil, fmt.Errorf("%w: Output file must differ from %s so the original is kept", ErrRepairError, filePath)
+	}
+	if err := ensureOutputDir(outputFile, createDirs, ErrRepairError); err != nil {
+		return nil, err
+	}
+	if err := jsonhandler.GetHandler(outputFile).ReplaceContents(repaired); err != nil {
+		return nil, fmt.Errorf("%w: Failed to write %s: %w", ErrRepairError, outputFile, err)
+	}
+
+	return result, nil
+}
+
+// errNoChange lets an editFile mutation report that nothing needs to be written
+var errNoChange = errors.New("no change")
+
+// maxEditAttempts bounds how often an edit is retried after a concurrent-modification conflict
+const maxEditAttempts = 3
+
+// editFile runs a load-modify-save cycle under the file's edit lock. If another process changes
+// the file between load and save, the edit is re-applied to freshly loaded data; once attempts
+// run out the CONFLICT error is returned. Save failures are wrapped with saveErr.
+func editFile(filePath string, saveErr error, mutate func(data map[string]interface{}) error) error {
+	handler := jsonhandler.GetHandler(filePath)
+	handler.BeginEdit()
+	defer handler.EndEdit()
+
+	for attempt := 1; ; attempt++ {
+		data, err := handler.LoadJSONForEdit()
+		if err != nil {
+			return err
+		}
+
+		if err := mutate(data); err != nil {
+			if errors.Is(err, errNoChange) {
+				return nil
+			}
+			return err
+		}
+
+		// Save the updated data
+		err = handler.SaveJSONForEdit(data, 2)
+		if errors.Is(err, jsonhandler.ErrConflict) && attempt < maxEditAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%w: Failed to save file: %w", saveErr, err)
+		}
+		return nil
+	}
+}
+
+// isContainer reports whether a decoded value is an object or array
+func isContainer(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// containerLen returns the number of children of an object or array
+func containerLen(value interface{}) int {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v)
+	case []interface{}:
+		return len(v)
+	}
+	return 0
+}
+
+// truncateString shortens s to at most maxLen characters, marking the cut with an ellipsis
+func truncateString(s string, maxLen int) string {
+	runes := []rune(s)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen]) + "…"
+}
+
+// normalizeJSON round-trips a value through encoding/json so it compares equal to decoded file data
+func normalizeJSON(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// jsonTypeOf returns the JSON type name of a decoded value
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64, float32, int, int64, int32:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
 }
\ No newline at end of file
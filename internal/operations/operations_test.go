@@ -1,9 +1,18 @@
 package operations
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+
+	"jsonmcptool/internal/jsonhandler"
 )
 
 // Test data similar to original Python fixtures
@@ -272,7 +281,7 @@ func TestUpdateKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := UpdateKey(tempFile, tt.path, tt.value)
+			err := UpdateKey(tempFile, tt.path, tt.value, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateKey() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -292,6 +301,36 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
+func TestUpdateKeyEnforceType(t *testing.T) {
+	tempFile := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name    string
+		path    string
+		value   interface{}
+		wantErr bool
+	}{
+		{"same type boolean", "booleanTrue", false, false},
+		{"same type number", "number", float64(7), false},
+		{"boolean to string should fail", "booleanFalse", "true", true},
+		{"number to string should fail", "float", "3.14", true},
+		{"object to array should fail", "nested", []interface{}{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UpdateKey(tempFile, tt.path, tt.value, true)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrTypeMismatch) {
+				t.Errorf("UpdateKey() error = %v, want TYPE_MISMATCH", err)
+			}
+		})
+	}
+}
+
 func TestRenameKey(t *testing.T) {
 	tempFile := createTempJSONFile(t, sampleI18nData)
 	defer os.Remove(tempFile)
@@ -535,6 +574,35 @@ func TestValidateJSON(t *testing.T) {
 	}
 }
 
+func TestValidateJSONCollectAllErrors(t *testing.T) {
+	invalidFile, err := os.CreateTemp("", "invalid_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(invalidFile.Name())
+
+	invalidFile.WriteString("{\n  \"a\": 1\n  \"b\": [1,],\n  \"c\": nul }")
+	invalidFile.Close()
+
+	result, err := ValidateJSONWithOptions(invalidFile.Name(), true)
+	if err != nil {
+		t.Fatalf("ValidateJSONWithOptions() error = %v", err)
+	}
+	if result.Valid || len(result.Errors) != 3 {
+		t.Fatalf("ValidateJSONWithOptions() = %+v, want 3 errors", result)
+	}
+	for i, line := range []int{3, 3, 4} {
+		if result.Errors[i].Line != line {
+			t.Errorf("Errors[%d] = %+v, want line %d", i, result.Errors[i], line)
+		}
+	}
+
+	result, _ = ValidateJSONWithOptions(invalidFile.Name(), false)
+	if result.Errors != nil {
+		t.Errorf("ValidateJSONWithOptions() without collecting should leave Errors empty, got %+v", result.Errors)
+	}
+}
+
 func TestFileNotFoundErrors(t *testing.T) {
 	nonexistentFile := "nonexistent.json"
 
@@ -549,7 +617,7 @@ func TestFileNotFoundErrors(t *testing.T) {
 		t.Error("AddKey() should fail for nonexistent file")
 	}
 
-	err = UpdateKey(nonexistentFile, "any.key", "value")
+	err = UpdateKey(nonexistentFile, "any.key", "value", false)
 	if err == nil {
 		t.Error("UpdateKey() should fail for nonexistent file")
 	}
@@ -575,6 +643,4665 @@ func TestFileNotFoundErrors(t *testing.T) {
 	}
 }
 
+func TestClearKey(t *testing.T) {
+	tempFile := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantErr  bool
+		expected interface{}
+		cleared  interface{}
+	}{
+		{
+			name:     "clear object",
+			path:     "nested",
+			expected: map[string]interface{}{"key": "nested value"},
+			cleared:  map[string]interface{}{},
+		},
+		{
+			name:     "clear array",
+			path:     "array",
+			expected: simpleTestData["array"],
+			cleared:  []interface{}{},
+		},
+		{
+			name:    "clear scalar should fail",
+			path:    "simple",
+			wantErr: true,
+		},
+		{
+			name:    "clear nonexistent key should fail",
+			path:    "nonexistent.key",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ClearKey(tempFile, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClearKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				if !deepEqual(result, tt.expected) {
+					t.Errorf("ClearKey() returned = %v, want %v", result, tt.expected)
+				}
+
+				// Verify key still exists but is now empty
+				value, err := GetKey(tempFile, tt.path)
+				if err != nil {
+					t.Errorf("Failed to verify cleared key: %v", err)
+					return
+				}
+				if !deepEqual(value, tt.cleared) {
+					t.Errorf("Cleared key value = %v, want %v", value, tt.cleared)
+				}
+			}
+		})
+	}
+
+	_, err := ClearKey(tempFile, "simple")
+	if !errors.Is(err, ErrNotContainer) {
+		t.Errorf("ClearKey() on scalar error = %v, want NOT_CONTAINER", err)
+	}
+}
+
+func TestGlobGet(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	result, err := GlobGet(tempFile, "auth.*.title")
+	if err != nil {
+		t.Fatalf("GlobGet() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"auth.login.title":    "Sign In",
+		"auth.register.title": "Create Account",
+	}
+	if !deepEqual(result, want) {
+		t.Errorf("GlobGet() = %v, want %v", result, want)
+	}
+
+	result, err = GlobGet(tempFile, "nonexistent.*")
+	if err != nil {
+		t.Errorf("GlobGet() non-matching pattern error = %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("GlobGet() non-matching pattern = %v, want empty", result)
+	}
+
+	// A root key spelled like the pattern is matched literally, as GetKey does
+	dottedFile := createTempJSONFile(t, map[string]interface{}{
+		"a.b": "literal",
+		"a":   map[string]interface{}{"b": "nested"},
+	})
+	defer os.Remove(dottedFile)
+	defer jsonhandler.EvictHandler(dottedFile)
+
+	result, err = GlobGet(dottedFile, "a.b")
+	if err != nil || !deepEqual(result, map[string]interface{}{"a.b": "literal"}) {
+		t.Errorf("GlobGet(a.b) = %v, %v, want the literal root key", result, err)
+	}
+}
+
+func TestRemoveMatching(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	want := map[string]interface{}{
+		"auth.login.title":    "Sign In",
+		"auth.register.title": "Create Account",
+	}
+
+	// Dry run reports matches without touching the file
+	preview, err := RemoveMatching(tempFile, "auth.*.title", true)
+	if err != nil {
+		t.Fatalf("RemoveMatching() dry run error = %v", err)
+	}
+	if !deepEqual(preview, want) {
+		t.Errorf("RemoveMatching() dry run = %v, want %v", preview, want)
+	}
+	if exists, _ := KeyExists(tempFile, "auth.login.title"); !exists {
+		t.Error("Dry run should not remove keys")
+	}
+
+	removed, err := RemoveMatching(tempFile, "auth.*.title", false)
+	if err != nil {
+		t.Fatalf("RemoveMatching() error = %v", err)
+	}
+	if !deepEqual(removed, want) {
+		t.Errorf("RemoveMatching() = %v, want %v", removed, want)
+	}
+	for path := range want {
+		if exists, _ := KeyExists(tempFile, path); exists {
+			t.Errorf("Key '%s' should not exist after removal", path)
+		}
+	}
+	if exists, _ := KeyExists(tempFile, "auth.login.email"); !exists {
+		t.Error("Non-matching sibling key should be kept")
+	}
+
+	removed, err = RemoveMatching(tempFile, "legacy.*", false)
+	if err != nil {
+		t.Errorf("RemoveMatching() non-matching pattern error = %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("RemoveMatching() non-matching pattern = %v, want empty", removed)
+	}
+
+	// Keys containing dots are removed from the object they were found in
+	dottedFile := createTempJSONFile(t, map[string]interface{}{
+		"legacy": map[string]interface{}{"x.y": 1.0, "z": 2.0},
+	})
+	defer os.Remove(dottedFile)
+	defer jsonhandler.EvictHandler(dottedFile)
+
+	preview, err = RemoveMatching(dottedFile, "legacy.*", true)
+	if err != nil || len(preview) != 2 {
+		t.Errorf("RemoveMatching() dotted dry run = %v, %v, want 2 matches", preview, err)
+	}
+	if _, err := RemoveMatching(dottedFile, "legacy.*", false); err != nil {
+		t.Fatalf("RemoveMatching() dotted keys error = %v", err)
+	}
+	legacy, err := GetKey(dottedFile, "legacy")
+	if err != nil || !deepEqual(legacy, map[string]interface{}{}) {
+		t.Errorf("legacy after removal = %v, %v, want empty object", legacy, err)
+	}
+}
+
+func TestCountValue(t *testing.T) {
+	data := map[string]interface{}{
+		"forms": map[string]interface{}{
+			"cancel": "Cancel",
+			"dialog": map[string]interface{}{"ok": "OK", "cancel": "Cancel"},
+		},
+		"modal":  map[string]interface{}{"ok": "OK", "cancel": "Cancel"},
+		"list":   []interface{}{"Cancel", float64(1)},
+		"number": float64(1),
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantCount int
+		wantPaths []string
+	}{
+		{
+			name:      "string leaves",
+			value:     "Cancel",
+			wantCount: 4,
+			wantPaths: []string{"forms.cancel", "forms.dialog.cancel", "list.0", "modal.cancel"},
+		},
+		{
+			name:      "duplicated object",
+			value:     map[string]interface{}{"cancel": "Cancel", "ok": "OK"},
+			wantCount: 2,
+			wantPaths: []string{"forms.dialog", "modal"},
+		},
+		{
+			name:      "integer matches decoded number",
+			value:     1,
+			wantCount: 2,
+			wantPaths: []string{"list.1", "number"},
+		},
+		{
+			name:      "no match",
+			value:     "missing",
+			wantCount: 0,
+			wantPaths: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CountValue(tempFile, tt.value)
+			if err != nil {
+				t.Fatalf("CountValue() error = %v", err)
+			}
+			if result.Count != tt.wantCount {
+				t.Errorf("CountValue() count = %d, want %d", result.Count, tt.wantCount)
+			}
+			if !deepEqual(result.Paths, tt.wantPaths) {
+				t.Errorf("CountValue() paths = %v, want %v", result.Paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestGeneratePatch(t *testing.T) {
+	dataA := map[string]interface{}{
+		"keep":    "same",
+		"change":  "old",
+		"drop":    "gone",
+		"a/b":     "slash",
+		"nested":  map[string]interface{}{"x": float64(1), "y": true},
+		"grow":    []interface{}{"a"},
+		"shrink":  []interface{}{"a", "b", "c"},
+		"retype":  map[string]interface{}{"k": "v"},
+		"toNull":  "value",
+		"element": []interface{}{map[string]interface{}{"id": "1"}},
+	}
+	dataB := map[string]interface{}{
+		"keep":    "same",
+		"change":  "new",
+		"a/b":     "slashed",
+		"nested":  map[string]interface{}{"x": float64(2), "z": false},
+		"grow":    []interface{}{"a", "b", "c"},
+		"shrink":  []interface{}{"a"},
+		"retype":  []interface{}{"k"},
+		"toNull":  nil,
+		"added":   map[string]interface{}{"deep": "value"},
+		"element": []interface{}{map[string]interface{}{"id": "2"}},
+	}
+	fileA := createTempJSONFile(t, dataA)
+	defer os.Remove(fileA)
+	fileB := createTempJSONFile(t, dataB)
+	defer os.Remove(fileB)
+
+	patch, err := GeneratePatch(fileA, fileB)
+	if err != nil {
+		t.Fatalf("GeneratePatch() error = %v", err)
+	}
+
+	// Applying the patch to A must yield B exactly
+	var result interface{}
+	encoded, _ := json.Marshal(dataA)
+	json.Unmarshal(encoded, &result)
+	for _, op := range patch {
+		result = applyPatchOperation(t, result, op)
+	}
+	if !deepEqual(result, dataB) {
+		t.Errorf("Patched A = %v, want %v", result, dataB)
+	}
+
+	// Identical files produce an empty patch
+	patch, err = GeneratePatch(fileA, fileA)
+	if err != nil {
+		t.Fatalf("GeneratePatch() error = %v", err)
+	}
+	if len(patch) != 0 {
+		t.Errorf("GeneratePatch() on identical files = %v, want empty", patch)
+	}
+
+	// Remove operations carry no value member
+	encoded, _ = json.Marshal(PatchOperation{Op: "remove", Path: "/drop"})
+	if string(encoded) != `{"op":"remove","path":"/drop"}` {
+		t.Errorf("remove operation JSON = %s", encoded)
+	}
+}
+
+// applyPatchOperation applies a single add/remove/replace operation for round-trip checks
+func applyPatchOperation(t *testing.T, doc interface{}, op PatchOperation) interface{} {
+	t.Helper()
+	if op.Path == "" {
+		return op.Value
+	}
+
+	tokens := strings.Split(op.Path, "/")[1:]
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	var apply func(node interface{}, tokens []string) interface{}
+	apply = func(node interface{}, tokens []string) interface{} {
+		last := len(tokens) == 1
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if !last {
+				n[tokens[0]] = apply(n[tokens[0]], tokens[1:])
+			} else if op.Op == "remove" {
+				delete(n, tokens[0])
+			} else {
+				n[tokens[0]] = op.Value
+			}
+			return n
+		case []interface{}:
+			index, err := strconv.Atoi(tokens[0])
+			if err != nil {
+				t.Fatalf("invalid array index in %s", op.Path)
+			}
+			if !last {
+				n[index] = apply(n[index], tokens[1:])
+				return n
+			}
+			switch op.Op {
+			case "add":
+				return append(n[:index], append([]interface{}{op.Value}, n[index:]...)...)
+			case "remove":
+				return append(n[:index], n[index+1:]...)
+			default:
+				n[index] = op.Value
+				return n
+			}
+		}
+		t.Fatalf("cannot apply %s at %s", op.Op, op.Path)
+		return nil
+	}
+
+	return apply(doc, tokens)
+}
+
+func TestApplyTransaction(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	err := ApplyTransaction(tempFile, []Operation{
+		{Action: "rename", KeyPath: "dashboard.title", NewPath: "dashboard.heading"},
+		{Action: "update", KeyPath: "dashboard.heading", Value: "Overview"},
+		{Action: "add", KeyPath: "alerts.info", Value: "For your information"},
+		{Action: "remove", KeyPath: "navigation.about"},
+		{Action: "move", KeyPath: "alerts.warning", NewPath: "warnings.input"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyTransaction() error = %v", err)
+	}
+
+	checks := map[string]interface{}{
+		"dashboard.heading": "Overview",
+		"alerts.info":       "For your information",
+		"warnings.input":    "Please check your input",
+	}
+	for path, want := range checks {
+		got, err := GetKey(tempFile, path)
+		if err != nil || got != want {
+			t.Errorf("GetKey(%s) = %v, %v, want %v", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"dashboard.title", "navigation.about", "alerts.warning"} {
+		if exists, _ := KeyExists(tempFile, path); exists {
+			t.Errorf("Key '%s' should not exist after transaction", path)
+		}
+	}
+
+	// A failing operation must leave the file untouched
+	before, _ := os.ReadFile(tempFile)
+	err = ApplyTransaction(tempFile, []Operation{
+		{Action: "remove", KeyPath: "alerts.success"},
+		{Action: "update", KeyPath: "nonexistent.key", Value: "value"},
+	})
+	if !errors.Is(err, ErrTransaction) || !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("ApplyTransaction() error = %v, want TRANSACTION_ERROR wrapping KEY_NOT_FOUND", err)
+	}
+	after, _ := os.ReadFile(tempFile)
+	if string(before) != string(after) {
+		t.Error("Failed transaction should not modify the file")
+	}
+
+	err = ApplyTransaction(tempFile, []Operation{{Action: "explode", KeyPath: "alerts"}})
+	if !errors.Is(err, ErrTransaction) {
+		t.Errorf("ApplyTransaction() with unknown action error = %v, want TRANSACTION_ERROR", err)
+	}
+}
+
+func TestLocateKey(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	location, err := LocateKey(tempFile, "auth.login.title")
+	if err != nil {
+		t.Fatalf("LocateKey() error = %v", err)
+	}
+	if location.Value != "Sign In" {
+		t.Errorf("LocateKey() value = %v, want 'Sign In'", location.Value)
+	}
+
+	// Verify the reported line actually contains the key
+	content, _ := os.ReadFile(tempFile)
+	lines := strings.Split(string(content), "\n")
+	if location.Line < 1 || location.Line > len(lines) || !strings.HasPrefix(lines[location.Line-1][location.Column-1:], `"title"`) {
+		t.Errorf("LocateKey() reported line %d column %d, which does not hold the key", location.Line, location.Column)
+	}
+
+	if _, err := LocateKey(tempFile, "auth.login.missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("LocateKey() for missing key error = %v, want KEY_NOT_FOUND", err)
+	}
+}
+
+func TestLargestSubtrees(t *testing.T) {
+	data := map[string]interface{}{
+		"small":  "x",
+		"medium": map[string]interface{}{"a": "12345"},
+		"large": map[string]interface{}{
+			"big":   "01234567890123456789012345678901234567890123456789",
+			"other": "y",
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	sizes, err := LargestSubtrees(tempFile, 2, 1)
+	if err != nil {
+		t.Fatalf("LargestSubtrees() error = %v", err)
+	}
+	if len(sizes) != 2 || sizes[0].Path != "large" || sizes[1].Path != "medium" {
+		t.Errorf("LargestSubtrees() = %v, want large then medium", sizes)
+	}
+	encoded, _ := json.Marshal(data["medium"])
+	if sizes[1].Size != len(encoded) {
+		t.Errorf("LargestSubtrees() medium size = %d, want %d", sizes[1].Size, len(encoded))
+	}
+
+	// Deeper scans include nested paths
+	sizes, err = LargestSubtrees(tempFile, 3, 2)
+	if err != nil {
+		t.Fatalf("LargestSubtrees() error = %v", err)
+	}
+	if len(sizes) != 3 || sizes[0].Path != "large" || sizes[1].Path != "large.big" {
+		t.Errorf("LargestSubtrees() with depth 2 = %v, want large, large.big first", sizes)
+	}
+}
+
+func TestConcurrentEditsAreSerialized(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{})
+	defer os.Remove(tempFile)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := AddKey(tempFile, fmt.Sprintf("keys.k%d", i), i); err != nil {
+				t.Errorf("AddKey() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	keys, err := ListKeys(tempFile, stringPtr("keys"))
+	if err != nil {
+		t.Fatalf("ListKeys() error = %v", err)
+	}
+	if len(keys) != writers {
+		t.Errorf("ListKeys() returned %d keys, want %d (lost updates)", len(keys), writers)
+	}
+}
+
+func TestRenderTree(t *testing.T) {
+	data := map[string]interface{}{
+		"dashboard": map[string]interface{}{
+			"title": "Dashboard",
+			"stats": map[string]interface{}{"users": "Total Users"},
+		},
+		"list":  []interface{}{"a", float64(1)},
+		"empty": map[string]interface{}{},
+		"long":  "abcdefghijklmnopqrstuvwxyz",
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	tree, err := RenderTree(tempFile, stringPtr("dashboard"), 0, 0)
+	if err != nil {
+		t.Fatalf("RenderTree() error = %v", err)
+	}
+	want := "dashboard\n" +
+		"├─ stats\n" +
+		"│  └─ users: \"Total Users\"\n" +
+		"└─ title: \"Dashboard\"\n"
+	if tree != want {
+		t.Errorf("RenderTree() =\n%s\nwant\n%s", tree, want)
+	}
+
+	tree, err = RenderTree(tempFile, nil, 1, 10)
+	if err != nil {
+		t.Fatalf("RenderTree() error = %v", err)
+	}
+	for _, line := range []string{"├─ dashboard: {2 keys}", "├─ empty: {}", "├─ list: [2 items]", "└─ long: \"abcdefghi…"} {
+		if !strings.Contains(tree, line) {
+			t.Errorf("RenderTree() missing line %q in\n%s", line, tree)
+		}
+	}
+
+	tree, err = RenderTree(tempFile, stringPtr("list"), 0, 0)
+	if err != nil {
+		t.Fatalf("RenderTree() error = %v", err)
+	}
+	if !strings.Contains(tree, "├─ [0]: \"a\"") || !strings.Contains(tree, "└─ [1]: 1") {
+		t.Errorf("RenderTree() should render array indices, got\n%s", tree)
+	}
+
+	if _, err := RenderTree(tempFile, stringPtr("missing"), 0, 0); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("RenderTree() for missing key error = %v, want KEY_NOT_FOUND", err)
+	}
+}
+
+func TestEditFileRetriesOnConflict(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{"counter": "start"})
+	defer os.Remove(tempFile)
+
+	// The first attempt races with an external writer; the retry sees its change
+	attempts := 0
+	err := editFile(tempFile, ErrUpdateKeyError, func(data map[string]interface{}) error {
+		attempts++
+		if attempts == 1 {
+			os.WriteFile(tempFile, []byte(`{"counter": "external", "extra": true}`), 0644)
+		}
+		data["edited"] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("editFile() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("editFile() attempts = %d, want 2", attempts)
+	}
+	if extra, _ := GetKey(tempFile, "extra"); extra != true {
+		t.Error("Retried edit should preserve the external change")
+	}
+
+	// A writer that keeps interfering exhausts the retries
+	attempts = 0
+	err = editFile(tempFile, ErrUpdateKeyError, func(data map[string]interface{}) error {
+		attempts++
+		os.WriteFile(tempFile, []byte(fmt.Sprintf(`{"attempt": %d, "padding": "%s"}`, attempts, strings.Repeat("x", attempts))), 0644)
+		return nil
+	})
+	if !errors.Is(err, jsonhandler.ErrConflict) {
+		t.Errorf("editFile() error = %v, want CONFLICT", err)
+	}
+	if attempts != maxEditAttempts {
+		t.Errorf("editFile() attempts = %d, want %d", attempts, maxEditAttempts)
+	}
+}
+
+func TestInsertArrayElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		index   int
+		want    []interface{}
+		wantErr error
+	}{
+		{"insert at start", "list", 0, []interface{}{"new", "a", "b", "c"}, nil},
+		{"insert in middle", "list", 1, []interface{}{"a", "new", "b", "c"}, nil},
+		{"index equal to length appends", "list", 3, []interface{}{"a", "b", "c", "new"}, nil},
+		{"negative index counts from end", "list", -1, []interface{}{"a", "b", "new", "c"}, nil},
+		{"index beyond length", "list", 4, nil, ErrIndexOutOfRange},
+		{"negative index beyond start", "list", -4, nil, ErrIndexOutOfRange},
+		{"not an array", "object", 0, nil, ErrNotArray},
+		{"missing key", "missing", 0, nil, ErrKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, map[string]interface{}{
+				"list":   []interface{}{"a", "b", "c"},
+				"object": map[string]interface{}{},
+			})
+			defer os.Remove(tempFile)
+
+			err := InsertArrayElement(tempFile, tt.path, tt.index, "new")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("InsertArrayElement() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InsertArrayElement() error = %v", err)
+			}
+
+			result, _ := GetKey(tempFile, tt.path)
+			if !deepEqual(result, tt.want) {
+				t.Errorf("Array after insert = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateArrayElement(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		index   int
+		want    []interface{}
+		wantErr error
+	}{
+		{"update first", "list", 0, []interface{}{"new", "b", "c"}, nil},
+		{"update last by negative index", "list", -1, []interface{}{"a", "b", "new"}, nil},
+		{"index equal to length", "list", 3, nil, ErrIndexOutOfRange},
+		{"negative index beyond start", "list", -4, nil, ErrIndexOutOfRange},
+		{"not an array", "scalar", 0, nil, ErrNotArray},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, map[string]interface{}{
+				"list":   []interface{}{"a", "b", "c"},
+				"scalar": "value",
+			})
+			defer os.Remove(tempFile)
+
+			err := UpdateArrayElement(tempFile, tt.path, tt.index, "new")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("UpdateArrayElement() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateArrayElement() error = %v", err)
+			}
+
+			result, _ := GetKey(tempFile, tt.path)
+			if !deepEqual(result, tt.want) {
+				t.Errorf("Array after update = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindInArray(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"host": "db1", "port": float64(5432)},
+			map[string]interface{}{"host": "web1", "port": float64(80)},
+			"not an object",
+			map[string]interface{}{"host": "db1", "port": float64(5433)},
+		},
+		"tags": []interface{}{"a", "b", "a"},
+		"name": "cluster",
+	})
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name       string
+		path       string
+		matchField string
+		matchValue interface{}
+		want       []int
+	}{
+		{"match by field", "servers", "host", "db1", []int{0, 3}},
+		{"match numeric field", "servers", "port", 80, []int{1}},
+		{"match whole element", "tags", "", "a", []int{0, 2}},
+		{"match whole object", "servers", "", map[string]interface{}{"host": "web1", "port": 80}, []int{1}},
+		{"no match", "servers", "host", "missing", []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indices, err := FindInArray(tempFile, tt.path, tt.matchField, tt.matchValue)
+			if err != nil {
+				t.Fatalf("FindInArray() error = %v", err)
+			}
+			if !deepEqual(indices, tt.want) {
+				t.Errorf("FindInArray() = %v, want %v", indices, tt.want)
+			}
+		})
+	}
+
+	if _, err := FindInArray(tempFile, "name", "", "cluster"); !errors.Is(err, ErrNotArray) {
+		t.Errorf("FindInArray() on non-array error = %v, want NOT_ARRAY", err)
+	}
+}
+
+func TestPathsEqual(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"a": map[string]interface{}{"title": "Same", "list": []interface{}{float64(1), float64(2)}},
+		"b": map[string]interface{}{"title": "Same", "list": []interface{}{float64(1), float64(2)}},
+		"c": map[string]interface{}{"title": "Other", "list": []interface{}{float64(1), float64(2)}},
+		"d": map[string]interface{}{"title": "Same"},
+	})
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name           string
+		pathA          string
+		pathB          string
+		wantEqual      bool
+		wantDifference string
+	}{
+		{"equal objects", "a", "b", true, ""},
+		{"equal leaves", "a.title", "d.title", true, ""},
+		{"different leaf", "a", "c", false, `'title' differs: "Same" vs "Other"`},
+		{"missing in second", "a", "d", false, "'list' exists only in the first value"},
+		{"extra in second", "d", "a", false, "'list' exists only in the second value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PathsEqual(tempFile, tt.pathA, tt.pathB)
+			if err != nil {
+				t.Fatalf("PathsEqual() error = %v", err)
+			}
+			if result.Equal != tt.wantEqual || result.Difference != tt.wantDifference {
+				t.Errorf("PathsEqual() = %+v, want equal=%v difference=%q", result, tt.wantEqual, tt.wantDifference)
+			}
+		})
+	}
+
+	_, err := PathsEqual(tempFile, "a", "missing")
+	if !errors.Is(err, ErrKeyNotFound) || !strings.Contains(err.Error(), "path_b") {
+		t.Errorf("PathsEqual() with missing path error = %v, want KEY_NOT_FOUND naming path_b", err)
+	}
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer os.RemoveAll(tempFile + ".snapshots")
+
+	snapshots, err := ListSnapshots(tempFile)
+	if err != nil || len(snapshots) != 0 {
+		t.Fatalf("ListSnapshots() = %v, %v, want empty", snapshots, err)
+	}
+
+	if err := Snapshot(tempFile, "before-edit"); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if err := Snapshot(tempFile, "before-edit"); !errors.Is(err, ErrSnapshotExists) {
+		t.Errorf("Snapshot() with existing label error = %v, want SNAPSHOT_EXISTS", err)
+	}
+	if err := Snapshot(tempFile, "../escape"); !errors.Is(err, ErrInvalidLabel) {
+		t.Errorf("Snapshot() with unsafe label error = %v, want INVALID_LABEL", err)
+	}
+
+	// Edit, then restore the original
+	if err := UpdateKey(tempFile, "dashboard.title", "Changed", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	if err := Restore(tempFile, "before-edit"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if value, _ := GetKey(tempFile, "dashboard.title"); value != "Dashboard" {
+		t.Errorf("Restored dashboard.title = %v, want Dashboard", value)
+	}
+
+	if err := Restore(tempFile, "missing"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Restore() with unknown label error = %v, want SNAPSHOT_NOT_FOUND", err)
+	}
+
+	snapshots, err = ListSnapshots(tempFile)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Label != "before-edit" || snapshots[0].SizeBytes == 0 {
+		t.Errorf("ListSnapshots() = %v, want single before-edit snapshot", snapshots)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	if err := Undo(tempFile); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo() without history error = %v, want NOTHING_TO_UNDO", err)
+	}
+
+	if err := UpdateKey(tempFile, "dashboard.title", "First", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	if err := UpdateKey(tempFile, "dashboard.title", "Second", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+
+	if err := Undo(tempFile); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if value, _ := GetKey(tempFile, "dashboard.title"); value != "First" {
+		t.Errorf("After undo dashboard.title = %v, want First", value)
+	}
+	if err := Undo(tempFile); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if value, _ := GetKey(tempFile, "dashboard.title"); value != "Dashboard" {
+		t.Errorf("After second undo dashboard.title = %v, want Dashboard", value)
+	}
+
+	if err := Redo(tempFile); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if value, _ := GetKey(tempFile, "dashboard.title"); value != "First" {
+		t.Errorf("After redo dashboard.title = %v, want First", value)
+	}
+
+	// A new edit discards the redo history
+	if err := AddKey(tempFile, "dashboard.subtitle", "New"); err != nil {
+		t.Fatalf("AddKey() error = %v", err)
+	}
+	if err := Redo(tempFile); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo() after new edit error = %v, want NOTHING_TO_REDO", err)
+	}
+
+	// External modification discards the whole history
+	if err := os.WriteFile(tempFile, []byte(`{"replaced": true}`), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	if err := Undo(tempFile); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo() after external change error = %v, want NOTHING_TO_UNDO", err)
+	}
+	if value, _ := GetKey(tempFile, "replaced"); value != true {
+		t.Errorf("External change was overwritten, replaced = %v", value)
+	}
+}
+
+func TestDetectEncodingFile(t *testing.T) {
+	tempFile := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(tempFile)
+
+	info, err := DetectEncoding(tempFile)
+	if err != nil {
+		t.Fatalf("DetectEncoding() error = %v", err)
+	}
+	if info.Encoding != "UTF-8" || info.BOM {
+		t.Errorf("DetectEncoding() = %+v, want UTF-8 without BOM", info)
+	}
+
+	if err := os.WriteFile(tempFile, []byte("\xFE\xFF\x00{\x00}"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	info, err = DetectEncoding(tempFile)
+	if err != nil {
+		t.Fatalf("DetectEncoding() error = %v", err)
+	}
+	if info.Encoding != "UTF-16BE" || !info.BOM {
+		t.Errorf("DetectEncoding() = %+v, want UTF-16BE with BOM", info)
+	}
+
+	if _, err := DetectEncoding("/nonexistent/file.json"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("DetectEncoding() on missing file error = %v, want FILE_NOT_FOUND", err)
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyPath   string
+		newParent string
+		wantPath  string
+		wantValue interface{}
+		wantErr   error
+	}{
+		{"wrap nested key", "dashboard.title", "header", "dashboard.header.title", "Dashboard", nil},
+		{"wrap root key", "dashboard", "pages", "pages.dashboard.stats.users", "Total Users", nil},
+		{"wrap into existing object", "dashboard.title", "stats", "dashboard.stats.title", "Dashboard", nil},
+		{"wrap under own name", "dashboard.title", "title", "dashboard.title.title", "Dashboard", nil},
+		{"destination exists", "dashboard.users", "stats", "", nil, ErrKeyExists},
+		{"nonexistent key", "dashboard.missing", "header", "", nil, ErrKeyNotFound},
+		{"empty parent", "dashboard.title", "", "", nil, ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{
+				"dashboard": map[string]interface{}{
+					"title": "Dashboard",
+					"users": "Users",
+					"stats": map[string]interface{}{
+						"users": "Total Users",
+					},
+				},
+			}
+			tempFile := createTempJSONFile(t, data)
+			defer os.Remove(tempFile)
+
+			newPath, err := WrapKey(tempFile, tt.keyPath, tt.newParent)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("WrapKey() error = %v, want %v", err, tt.wantErr)
+				}
+				if exists, _ := KeyExists(tempFile, tt.keyPath); tt.wantErr != ErrKeyNotFound && !exists {
+					t.Errorf("Failed WrapKey() should leave '%s' in place", tt.keyPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WrapKey() error = %v", err)
+			}
+			if !strings.HasPrefix(tt.wantPath, newPath) {
+				t.Errorf("WrapKey() = %s, want prefix of %s", newPath, tt.wantPath)
+			}
+
+			value, err := GetKey(tempFile, tt.wantPath)
+			if err != nil || value != tt.wantValue {
+				t.Errorf("GetKey(%s) = %v, %v, want %v", tt.wantPath, value, err, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestPromoteChildren(t *testing.T) {
+	newData := func() map[string]interface{} {
+		return map[string]interface{}{
+			"page": map[string]interface{}{
+				"title": "Old Title",
+				"header": map[string]interface{}{
+					"title":    "Title",
+					"subtitle": "Subtitle",
+				},
+			},
+			"name": "scalar",
+		}
+	}
+
+	t.Run("collision without overwrite", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, newData())
+		defer os.Remove(tempFile)
+
+		_, err := PromoteChildren(tempFile, "page.header", false)
+		if !errors.Is(err, ErrKeyExists) || !strings.Contains(err.Error(), "title") {
+			t.Errorf("PromoteChildren() error = %v, want KEY_EXISTS naming title", err)
+		}
+		if exists, _ := KeyExists(tempFile, "page.header.subtitle"); !exists {
+			t.Error("Failed PromoteChildren() should leave the file unchanged")
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, newData())
+		defer os.Remove(tempFile)
+
+		promoted, err := PromoteChildren(tempFile, "page.header", true)
+		if err != nil {
+			t.Fatalf("PromoteChildren() error = %v", err)
+		}
+		if strings.Join(promoted, ",") != "subtitle,title" {
+			t.Errorf("PromoteChildren() = %v, want [subtitle title]", promoted)
+		}
+
+		page, _ := GetKey(tempFile, "page")
+		want := map[string]interface{}{"title": "Title", "subtitle": "Subtitle"}
+		if !deepEqual(page, want) {
+			t.Errorf("page = %v, want %v", page, want)
+		}
+	})
+
+	t.Run("root level", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, newData())
+		defer os.Remove(tempFile)
+
+		if _, err := PromoteChildren(tempFile, "page", false); err != nil {
+			t.Fatalf("PromoteChildren() error = %v", err)
+		}
+		if exists, _ := KeyExists(tempFile, "header.subtitle"); !exists {
+			t.Error("header should have been promoted to the root")
+		}
+		if exists, _ := KeyExists(tempFile, "page"); exists {
+			t.Error("page should have been removed")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, newData())
+		defer os.Remove(tempFile)
+
+		if _, err := PromoteChildren(tempFile, "name", false); !errors.Is(err, ErrNotContainer) {
+			t.Errorf("PromoteChildren() on scalar error = %v, want NOT_CONTAINER", err)
+		}
+		if _, err := PromoteChildren(tempFile, "page.missing", false); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("PromoteChildren() on missing key error = %v, want KEY_NOT_FOUND", err)
+		}
+	})
+}
+
+func TestShowSubtree(t *testing.T) {
+	data := map[string]interface{}{
+		"forms": map[string]interface{}{
+			"validation": map[string]interface{}{
+				"required": "<b>Required</b>",
+				"limits":   []interface{}{1.0, 2.0},
+			},
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		name   string
+		indent int
+		want   string
+	}{
+		{"two spaces", 2, "{\n  \"limits\": [\n    1,\n    2\n  ],\n  \"required\": \"<b>Required</b>\"\n}"},
+		{"compact", 0, `{"limits":[1,2],"required":"<b>Required</b>"}`},
+		{"negative is compact", -3, `{"limits":[1,2],"required":"<b>Required</b>"}`},
+		{"capped", 20, "{\n        \"limits\": [\n                1,\n                2\n        ],\n        \"required\": \"<b>Required</b>\"\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ShowSubtree(tempFile, "forms.validation", tt.indent)
+			if err != nil {
+				t.Fatalf("ShowSubtree() error = %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("ShowSubtree() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+
+	if result, _ := ShowSubtree(tempFile, "forms.validation.required", 2); result != `"<b>Required</b>"` {
+		t.Errorf("ShowSubtree() on scalar = %q", result)
+	}
+	if _, err := ShowSubtree(tempFile, "forms.missing", 2); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("ShowSubtree() on missing key error = %v, want KEY_NOT_FOUND", err)
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	data := map[string]interface{}{
+		"version": 4,
+		"meta": map[string]interface{}{
+			"tags":  []interface{}{"a", "b"},
+			"owner": nil,
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	swapped, err := CompareAndSet(tempFile, "version", 3, 5)
+	if err != nil || swapped {
+		t.Errorf("CompareAndSet() with stale expected = %v, %v, want false, nil", swapped, err)
+	}
+	if value, _ := GetKey(tempFile, "version"); value != 4.0 {
+		t.Errorf("version = %v, want unchanged 4", value)
+	}
+
+	swapped, err = CompareAndSet(tempFile, "version", 4, 5.0)
+	if err != nil || !swapped {
+		t.Errorf("CompareAndSet() with current expected = %v, %v, want true, nil", swapped, err)
+	}
+	if value, _ := GetKey(tempFile, "version"); value != 5.0 {
+		t.Errorf("version = %v, want 5", value)
+	}
+
+	// Containers and null are compared deeply
+	swapped, err = CompareAndSet(tempFile, "meta.tags", []string{"a", "b"}, []string{"c"})
+	if err != nil || !swapped {
+		t.Errorf("CompareAndSet() on array = %v, %v, want true, nil", swapped, err)
+	}
+	swapped, err = CompareAndSet(tempFile, "meta.owner", nil, "alice")
+	if err != nil || !swapped {
+		t.Errorf("CompareAndSet() on null = %v, %v, want true, nil", swapped, err)
+	}
+
+	if _, err := CompareAndSet(tempFile, "missing", 1, 2); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("CompareAndSet() on missing key error = %v, want KEY_NOT_FOUND", err)
+	}
+}
+
+func TestGetKeyExpanded(t *testing.T) {
+	t.Setenv("JSONMCPTOOL_TEST_HOME", "/home/tester")
+	t.Setenv("JSONMCPTOOL_TEST_EMPTY", "")
+
+	data := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"home":    "${JSONMCPTOOL_TEST_HOME}/app",
+			"short":   "$JSONMCPTOOL_TEST_HOME",
+			"empty":   "[${JSONMCPTOOL_TEST_EMPTY}]",
+			"list":    []interface{}{"$JSONMCPTOOL_TEST_HOME/a", 1.0},
+			"missing": "${JSONMCPTOOL_TEST_UNSET}/x",
+			"bare":    "$JSONMCPTOOL_TEST_UNSET and $JSONMCPTOOL_TEST_HOME",
+			"price":   "costs $5.00",
+			"dollars": "$$ and $ and ${not valid}",
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	value, err := GetKeyExpanded(tempFile, "paths", false)
+	if err != nil {
+		t.Fatalf("GetKeyExpanded() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"home":    "/home/tester/app",
+		"short":   "/home/tester",
+		"empty":   "[]",
+		"list":    []interface{}{"/home/tester/a", 1.0},
+		"missing": "${JSONMCPTOOL_TEST_UNSET}/x",
+		"bare":    "$JSONMCPTOOL_TEST_UNSET and /home/tester",
+		"price":   "costs $5.00",
+		"dollars": "$$ and $ and ${not valid}",
+	}
+	if !deepEqual(value, want) {
+		t.Errorf("GetKeyExpanded() = %v, want %v", value, want)
+	}
+
+	if _, err := GetKeyExpanded(tempFile, "paths", true); !errors.Is(err, ErrUndefinedVar) || !strings.Contains(err.Error(), "JSONMCPTOOL_TEST_UNSET") {
+		t.Errorf("GetKeyExpanded() strict error = %v, want UNDEFINED_VARIABLE naming the variable", err)
+	}
+	if value, err := GetKeyExpanded(tempFile, "paths.home", true); err != nil || value != "/home/tester/app" {
+		t.Errorf("GetKeyExpanded() strict on defined = %v, %v", value, err)
+	}
+	for _, keyPath := range []string{"paths.price", "paths.dollars"} {
+		if _, err := GetKeyExpanded(tempFile, keyPath, true); err != nil {
+			t.Errorf("GetKeyExpanded(%s) strict error = %v, want a bare $ to be plain text", keyPath, err)
+		}
+	}
+
+	// The stored value is untouched
+	if value, _ := GetKey(tempFile, "paths.home"); value != "${JSONMCPTOOL_TEST_HOME}/app" {
+		t.Errorf("Stored paths.home = %v, want unexpanded", value)
+	}
+}
+
+func TestListPlaceholders(t *testing.T) {
+	data := map[string]interface{}{
+		"greeting": "Hello {{name}}, you have {{count, number}} messages",
+		"farewell": "Bye {{- name}}!",
+		"icu": map[string]interface{}{
+			"cart":   "{count, plural, one {# item for {user}} other {# items}}",
+			"quoted": "Use '{braces}' for {value}",
+			"gender": "{gender, select, male {He} female {She} other {They}} replied",
+		},
+		"printf": []interface{}{"%s of %d done", "%(user)s is 100% sure, 50%% off", "%2$s then %1$s"},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	tests := []struct {
+		style string
+		want  map[string][]string
+	}{
+		{
+			style: "handlebars",
+			want: map[string][]string{
+				"count": {"greeting"},
+				"name":  {"farewell", "greeting"},
+			},
+		},
+		{
+			style: "icu",
+			want: map[string][]string{
+				"count":  {"icu.cart"},
+				"gender": {"icu.gender"},
+				"user":   {"icu.cart"},
+				"value":  {"icu.quoted"},
+			},
+		},
+		{
+			style: "printf",
+			want: map[string][]string{
+				"1":    {"printf.0", "printf.2"},
+				"2":    {"printf.0", "printf.2"},
+				"user": {"printf.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			result, err := ListPlaceholders(tempFile, tt.style)
+			if err != nil {
+				t.Fatalf("ListPlaceholders() error = %v", err)
+			}
+
+			got := map[string][]string{}
+			for i, usage := range result {
+				if i > 0 && result[i-1].Name >= usage.Name {
+					t.Errorf("ListPlaceholders() not sorted: %v", result)
+				}
+				got[usage.Name] = usage.Paths
+			}
+			if !deepEqual(got, tt.want) {
+				t.Errorf("ListPlaceholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ListPlaceholders(tempFile, "mustache"); !errors.Is(err, ErrInvalidStyle) {
+		t.Errorf("ListPlaceholders() with unknown style error = %v, want INVALID_STYLE", err)
+	}
+}
+
+func TestWithoutKey(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+
+	before, err := os.ReadFile(tempFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Warm the cache so a leaked mutation would be visible to later reads
+	if _, err := GetKey(tempFile, "dashboard.title"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := WithoutKey(tempFile, "dashboard.title")
+	if err != nil {
+		t.Fatalf("WithoutKey() error = %v", err)
+	}
+	dashboard, _ := result["dashboard"].(map[string]interface{})
+	if _, exists := dashboard["title"]; exists {
+		t.Error("WithoutKey() result still contains dashboard.title")
+	}
+	if _, exists := result["forms"]; !exists {
+		t.Error("WithoutKey() result should keep the rest of the document")
+	}
+
+	after, _ := os.ReadFile(tempFile)
+	if string(before) != string(after) {
+		t.Error("WithoutKey() must not modify the file")
+	}
+	if exists, _ := KeyExists(tempFile, "dashboard.title"); !exists {
+		t.Error("WithoutKey() must not modify cached data")
+	}
+
+	if _, err := WithoutKey(tempFile, "missing.key"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("WithoutKey() on missing key error = %v, want KEY_NOT_FOUND", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	data := map[string]interface{}{
+		"database": map[string]interface{}{
+			"host":     "db.local",
+			"Password": "hunter2",
+		},
+		"apiToken": "abc123",
+		"secrets": map[string]interface{}{
+			"keys": []interface{}{"k1", "k2"},
+		},
+		"tokenCount": 3.0,
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	result, err := Redact(tempFile, "", nil, false)
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"database": map[string]interface{}{
+			"host":     "db.local",
+			"Password": RedactedValue,
+		},
+		"apiToken": RedactedValue,
+		"secrets": map[string]interface{}{
+			"keys": []interface{}{RedactedValue, RedactedValue},
+		},
+		"tokenCount": RedactedValue,
+	}
+	if !deepEqual(result.Document, want) {
+		t.Errorf("Redact() document = %v, want %v", result.Document, want)
+	}
+	wantPaths := []string{"apiToken", "database.Password", "secrets.keys.0", "secrets.keys.1", "tokenCount"}
+	if strings.Join(result.RedactedPaths, ",") != strings.Join(wantPaths, ",") {
+		t.Errorf("Redact() paths = %v, want %v", result.RedactedPaths, wantPaths)
+	}
+
+	// The source is left alone
+	if value, _ := GetKey(tempFile, "database.Password"); value != "hunter2" {
+		t.Errorf("Source database.Password = %v, want hunter2", value)
+	}
+
+	// Custom patterns and output file
+	outputFile := tempFile + ".redacted.json"
+	defer os.Remove(outputFile)
+	result, err = Redact(tempFile, outputFile, []string{"HOST"}, false)
+	if err != nil {
+		t.Fatalf("Redact() to file error = %v", err)
+	}
+	if len(result.RedactedPaths) != 1 {
+		t.Errorf("Redact() with custom pattern paths = %v, want only database.host", result.RedactedPaths)
+	}
+	if value, _ := GetKey(outputFile, "database.host"); value != RedactedValue {
+		t.Errorf("Output database.host = %v, want %s", value, RedactedValue)
+	}
+
+	if _, err := Redact(tempFile, tempFile, nil, false); !errors.Is(err, ErrRedactError) {
+		t.Errorf("Redact() onto the source error = %v, want REDACT_ERROR", err)
+	}
+	if _, err := Redact(tempFile, "", []string{"[bad"}, false); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Redact() with bad pattern error = %v, want INVALID_PATTERN", err)
+	}
+}
+
+func TestPick(t *testing.T) {
+	data := map[string]interface{}{
+		"database": map[string]interface{}{
+			"url":  "postgres://db",
+			"pool": map[string]interface{}{"size": 10.0, "timeout": 30.0},
+		},
+		"auth": map[string]interface{}{
+			"issuer": "me",
+			"secret": "s3cr3t",
+		},
+		"key.with.dots": "dotted",
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	result, err := Pick(tempFile, []string{"database.url", "database.pool.size", "auth.issuer", "key.with.dots", "auth.missing"}, "", false)
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"database": map[string]interface{}{
+			"url":  "postgres://db",
+			"pool": map[string]interface{}{"size": 10.0},
+		},
+		"auth":          map[string]interface{}{"issuer": "me"},
+		"key.with.dots": "dotted",
+	}
+	if !deepEqual(result.Document, want) {
+		t.Errorf("Pick() document = %v, want %v", result.Document, want)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "auth.missing" {
+		t.Errorf("Pick() missing = %v, want [auth.missing]", result.Missing)
+	}
+
+	outputFile := tempFile + ".picked.json"
+	defer os.Remove(outputFile)
+	if _, err := Pick(tempFile, []string{"auth"}, outputFile, false); err != nil {
+		t.Fatalf("Pick() to file error = %v", err)
+	}
+	if keys, _ := ListKeys(outputFile, nil); len(keys) != 1 || keys[0] != "auth" {
+		t.Errorf("Picked file keys = %v, want [auth]", keys)
+	}
+
+	if _, err := Pick(tempFile, []string{"auth"}, tempFile, false); !errors.Is(err, ErrPickError) {
+		t.Errorf("Pick() onto the source error = %v, want PICK_ERROR", err)
+	}
+	if exists, _ := KeyExists(tempFile, "database.pool.timeout"); !exists {
+		t.Error("Pick() must not modify the source file")
+	}
+}
+
+func TestOmit(t *testing.T) {
+	data := map[string]interface{}{
+		"debug":  true,
+		"legacy": map[string]interface{}{"old": "value"},
+		"app":    map[string]interface{}{"name": "demo", "internal": "x"},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	result, err := Omit(tempFile, []string{"debug", "app.internal", "nope"}, "", false, false)
+	if err != nil {
+		t.Fatalf("Omit() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"legacy": map[string]interface{}{"old": "value"},
+		"app":    map[string]interface{}{"name": "demo"},
+	}
+	if !deepEqual(result.Document, want) {
+		t.Errorf("Omit() document = %v, want %v", result.Document, want)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "nope" {
+		t.Errorf("Omit() missing = %v, want [nope]", result.Missing)
+	}
+	if exists, _ := KeyExists(tempFile, "debug"); !exists {
+		t.Error("Omit() without in_place must not modify the source")
+	}
+
+	outputFile := tempFile + ".omitted.json"
+	defer os.Remove(outputFile)
+	if _, err := Omit(tempFile, []string{"legacy"}, outputFile, false, false); err != nil {
+		t.Fatalf("Omit() to file error = %v", err)
+	}
+	if exists, _ := KeyExists(outputFile, "legacy"); exists {
+		t.Error("Output file should not contain legacy")
+	}
+
+	if _, err := Omit(tempFile, []string{"legacy"}, outputFile, true, false); !errors.Is(err, ErrOmitError) {
+		t.Errorf("Omit() with output file and in_place error = %v, want OMIT_ERROR", err)
+	}
+
+	if _, err := Omit(tempFile, []string{"legacy", "debug"}, "", true, false); err != nil {
+		t.Fatalf("Omit() in place error = %v", err)
+	}
+	keys, _ := ListKeys(tempFile, nil)
+	if len(keys) != 1 || keys[0] != "app" {
+		t.Errorf("Keys after in-place omit = %v, want [app]", keys)
+	}
+}
+
+func TestRequireKeys(t *testing.T) {
+	data := map[string]interface{}{
+		"database": map[string]interface{}{"url": "postgres://db"},
+		"auth":     map[string]interface{}{"secret": nil},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	report, err := RequireKeys(tempFile, []string{"database.url", "auth.secret"}, false)
+	if err != nil {
+		t.Fatalf("RequireKeys() error = %v", err)
+	}
+	if !report.Passed || len(report.Missing) != 0 || len(report.Null) != 1 || report.Null[0] != "auth.secret" {
+		t.Errorf("RequireKeys() = %+v, want pass with auth.secret null", report)
+	}
+
+	report, _ = RequireKeys(tempFile, []string{"database.url", "auth.secret", "cache.host"}, true)
+	if report.Passed || strings.Join(report.Missing, ",") != "auth.secret,cache.host" {
+		t.Errorf("RequireKeys() with null_is_missing = %+v, want auth.secret and cache.host missing", report)
+	}
+
+	if _, err := RequireKeys(tempFile, []string{""}, false); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("RequireKeys() with empty path error = %v, want INVALID_PATH", err)
+	}
+}
+
+func TestAssertValuesMatch(t *testing.T) {
+	data := map[string]interface{}{
+		"services": map[string]interface{}{
+			"api":    map[string]interface{}{"url": "https://api.example.com"},
+			"legacy": map[string]interface{}{"url": "http://old.example.com"},
+			"cdn":    map[string]interface{}{"url": 42.0},
+			"ftp":    map[string]interface{}{"url": "ftp://files"},
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+
+	report, err := AssertValuesMatch(tempFile, "services.*.url", "^https://")
+	if err != nil {
+		t.Fatalf("AssertValuesMatch() error = %v", err)
+	}
+	if report.Checked != 3 {
+		t.Errorf("AssertValuesMatch() checked %d values, want 3 strings", report.Checked)
+	}
+	want := []ValueMismatch{
+		{Path: "services.ftp.url", Value: "ftp://files"},
+		{Path: "services.legacy.url", Value: "http://old.example.com"},
+	}
+	if !deepEqual(report.Failures, want) {
+		t.Errorf("AssertValuesMatch() failures = %v, want %v", report.Failures, want)
+	}
+
+	if _, err := AssertValuesMatch(tempFile, "services.*.url", "(unclosed"); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("AssertValuesMatch() with bad regex error = %v, want INVALID_PATTERN", err)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	tempFile := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	GetKey(tempFile, "simple")
+	GetKey(tempFile, "simple")
+	KeyExists(tempFile, "simple")
+
+	report := CacheStats(tempFile)
+	if !report.Cached || report.Misses != 1 || report.Hits != 2 {
+		t.Errorf("CacheStats() = %+v, want cached with 1 miss and 2 hits", report)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	tempFile := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	if ClearCache(tempFile) {
+		t.Error("ClearCache() before any read should report nothing cached")
+	}
+
+	GetKey(tempFile, "simple")
+	if !ClearCache(tempFile) {
+		t.Error("ClearCache() after a read should report cached data")
+	}
+	if CacheStats(tempFile).Cached {
+		t.Error("Cache should be empty after ClearCache()")
+	}
+
+	GetKey(tempFile, "simple")
+	if report := CacheStats(tempFile); report.Misses != 2 {
+		t.Errorf("Read after ClearCache() should be a miss, got %+v", report)
+	}
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	const content = `{"a": 1.0, "b": 2, "c": 1e3, "d": 2.5, "e": [3.00, "4.0"]}`
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantChanged int
+		want        string
+	}{
+		{"int policy", IntegerPolicyInt, 3, `{"a":1,"b":2,"c":1000,"d":2.5,"e":[3,"4.0"]}`},
+		{"float policy", IntegerPolicyFloat, 3, `{"a":1.0,"b":2.0,"c":1000.0,"d":2.5,"e":[3.0,"4.0"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, simpleTestData)
+			defer os.Remove(tempFile)
+			defer jsonhandler.EvictHandler(tempFile)
+
+			if err := os.WriteFile(tempFile, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			changed, err := NormalizeNumbers(tempFile, tt.policy)
+			if err != nil {
+				t.Fatalf("NormalizeNumbers() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("NormalizeNumbers() changed = %d, want %d", changed, tt.wantChanged)
+			}
+
+			saved, err := os.ReadFile(tempFile)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, saved); err != nil {
+				t.Fatalf("Saved file is not valid JSON: %v", err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("Saved file = %s, want %s", compact.String(), tt.want)
+			}
+
+			// Readers see plain float64 values afterwards
+			value, err := GetKey(tempFile, "a")
+			if err != nil || value != 1.0 {
+				t.Errorf("GetKey() after normalize = %v (%v), want 1", value, err)
+			}
+
+			changed, err = NormalizeNumbers(tempFile, tt.policy)
+			if err != nil || changed != 0 {
+				t.Errorf("Second NormalizeNumbers() = %d, %v, want 0 changes", changed, err)
+			}
+
+			// Normalizing is an ordinary edit that can be undone
+			if err := Undo(tempFile); err != nil {
+				t.Fatalf("Undo() error = %v", err)
+			}
+			value, err = GetKey(tempFile, "c")
+			if err != nil || value != 1000.0 {
+				t.Errorf("GetKey() after undo = %v (%v), want 1000", value, err)
+			}
+			if undo, _ := jsonhandler.GetHandler(tempFile).HistoryLen(); undo != 0 {
+				t.Errorf("HistoryLen() undo after undo = %d, want 0", undo)
+			}
+		})
+	}
+
+	if _, err := NormalizeNumbers("unused.json", "double"); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("NormalizeNumbers() with unknown policy error = %v, want INVALID_POLICY", err)
+	}
+}
+
+func TestTransformStrings(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "  Alice ",
+		"count": 3.0,
+		"auth": map[string]interface{}{
+			"login":    map[string]interface{}{"title": "Log In", "tags": []interface{}{"Primary", 1.0}},
+			"register": map[string]interface{}{"title": "Sign Up"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		transform   string
+		pathGlob    string
+		wantChanged int
+		wantPath    string
+		wantValue   interface{}
+	}{
+		{"lower everything", "lower", "", 4, "auth.login.title", "log in"},
+		{"upper glob", "upper", "auth.*.title", 2, "auth.register.title", "SIGN UP"},
+		{"upper glob leaves others", "upper", "auth.*.title", 2, "name", "  Alice "},
+		{"upper subtree", "upper", "auth.login", 2, "auth.register.title", "Sign Up"},
+		{"trim", "trim", "name", 1, "name", "Alice"},
+		{"no match", "lower", "missing.*", 0, "name", "  Alice "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, data)
+			defer os.Remove(tempFile)
+			defer jsonhandler.EvictHandler(tempFile)
+
+			changed, err := TransformStrings(tempFile, tt.transform, tt.pathGlob)
+			if err != nil {
+				t.Fatalf("TransformStrings() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("TransformStrings() changed = %d, want %d", changed, tt.wantChanged)
+			}
+
+			value, err := GetKey(tempFile, tt.wantPath)
+			if err != nil || value != tt.wantValue {
+				t.Errorf("GetKey(%s) = %v (%v), want %v", tt.wantPath, value, err, tt.wantValue)
+			}
+		})
+	}
+
+	if _, err := TransformStrings("unused.json", "title", ""); !errors.Is(err, ErrInvalidTransform) {
+		t.Errorf("TransformStrings() with unknown transform error = %v, want INVALID_TRANSFORM", err)
+	}
+}
+
+func TestCheckKeyNaming(t *testing.T) {
+	data := map[string]interface{}{
+		"appName":  "demo",
+		"api_key":  "secret",
+		"max-size": 10.0,
+		"servers": []interface{}{
+			map[string]interface{}{"hostName": "db1", "Port": 5432.0},
+		},
+		"nested": map[string]interface{}{"retry_count": 3.0, "timeout": 30.0},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		convention string
+		want       []string
+	}{
+		{ConventionCamelCase, []string{"api_key", "max-size", "nested.retry_count", "servers.0.Port"}},
+		{ConventionSnakeCase, []string{"appName", "max-size", "servers.0.Port", "servers.0.hostName"}},
+		{ConventionKebabCase, []string{"api_key", "appName", "nested.retry_count", "servers.0.Port", "servers.0.hostName"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.convention, func(t *testing.T) {
+			got, err := CheckKeyNaming(tempFile, tt.convention)
+			if err != nil {
+				t.Fatalf("CheckKeyNaming() error = %v", err)
+			}
+			if !deepEqual(got, tt.want) {
+				t.Errorf("CheckKeyNaming() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CheckKeyNaming(tempFile, "PascalCase"); !errors.Is(err, ErrInvalidConvention) {
+		t.Errorf("CheckKeyNaming() with unknown convention error = %v, want INVALID_CONVENTION", err)
+	}
+}
+
+func TestConvertKeyName(t *testing.T) {
+	tests := []struct {
+		key        string
+		convention string
+		want       string
+	}{
+		{"api_key", ConventionCamelCase, "apiKey"},
+		{"HTTPServer", ConventionSnakeCase, "http_server"},
+		{"userID", ConventionKebabCase, "user-id"},
+		{"max-size", ConventionCamelCase, "maxSize"},
+		{"retry count 2", ConventionSnakeCase, "retry_count_2"},
+		{"alreadyCamel", ConventionCamelCase, "alreadyCamel"},
+		{"__", ConventionCamelCase, "__"},
+	}
+
+	for _, tt := range tests {
+		if got := convertKeyName(tt.key, tt.convention); got != tt.want {
+			t.Errorf("convertKeyName(%q, %s) = %q, want %q", tt.key, tt.convention, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeKeyNames(t *testing.T) {
+	data := map[string]interface{}{
+		"app_name": "demo",
+		"servers": []interface{}{
+			map[string]interface{}{"host_name": "db1"},
+		},
+		"retry_policy": map[string]interface{}{"max_attempts": 3.0},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	renames, err := NormalizeKeyNames(tempFile, ConventionCamelCase, false)
+	if err != nil {
+		t.Fatalf("NormalizeKeyNames() error = %v", err)
+	}
+
+	wantRenames := map[string]string{
+		"app_name":                  "appName",
+		"retry_policy":              "retryPolicy",
+		"retry_policy.max_attempts": "retryPolicy.maxAttempts",
+		"servers.0.host_name":       "servers.0.hostName",
+	}
+	if len(renames) != len(wantRenames) {
+		t.Errorf("NormalizeKeyNames() renames = %v, want %v", renames, wantRenames)
+	}
+	for oldPath, newPath := range wantRenames {
+		if renames[oldPath] != newPath {
+			t.Errorf("NormalizeKeyNames() renamed %s to %q, want %q", oldPath, renames[oldPath], newPath)
+		}
+	}
+
+	if value, err := GetKey(tempFile, "retryPolicy.maxAttempts"); err != nil || value != 3.0 {
+		t.Errorf("GetKey() after normalize = %v (%v), want 3", value, err)
+	}
+	if violations, _ := CheckKeyNaming(tempFile, ConventionCamelCase); len(violations) != 0 {
+		t.Errorf("CheckKeyNaming() after normalize = %v, want none", violations)
+	}
+
+	renames, err = NormalizeKeyNames(tempFile, ConventionCamelCase, false)
+	if err != nil || len(renames) != 0 {
+		t.Errorf("Second NormalizeKeyNames() = %v, %v, want no renames", renames, err)
+	}
+}
+
+func TestNormalizeKeyNamesCollisions(t *testing.T) {
+	data := map[string]interface{}{"userName": "a", "user_name": "b", "user-name": "c"}
+
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	if _, err := NormalizeKeyNames(tempFile, ConventionCamelCase, false); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("NormalizeKeyNames() collision error = %v, want KEY_EXISTS", err)
+	}
+	if value, _ := GetKey(tempFile, "user_name"); value != "b" {
+		t.Errorf("File should be unchanged after a collision, user_name = %v", value)
+	}
+
+	renames, err := NormalizeKeyNames(tempFile, ConventionCamelCase, true)
+	if err != nil {
+		t.Fatalf("NormalizeKeyNames() with suffixes error = %v", err)
+	}
+	// The unchanged key keeps its name; the others are suffixed in sorted key order
+	want := map[string]string{"user-name": "userName2", "user_name": "userName3"}
+	if len(renames) != len(want) || renames["user-name"] != want["user-name"] || renames["user_name"] != want["user_name"] {
+		t.Errorf("NormalizeKeyNames() renames = %v, want %v", renames, want)
+	}
+	if value, _ := GetKey(tempFile, "userName3"); value != "b" {
+		t.Errorf("GetKey(userName3) = %v, want b", value)
+	}
+}
+
+func TestGetKeyDepth(t *testing.T) {
+	data := map[string]interface{}{
+		"top":        "value",
+		"dotted.key": "literal",
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": 1.0},
+		},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		keyPath string
+		want    int
+		wantErr error
+	}{
+		{"top", 1, nil},
+		{"a.b", 2, nil},
+		{"a.b.c", 3, nil},
+		{"dotted.key", 1, nil},
+		{"a.missing", 0, ErrKeyNotFound},
+		{"", 0, ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		got, err := GetKeyDepth(tempFile, tt.keyPath)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetKeyDepth(%q) error = %v, want %v", tt.keyPath, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("GetKeyDepth(%q) = %d, %v, want %d", tt.keyPath, got, err, tt.want)
+		}
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want int
+	}{
+		{"empty", map[string]interface{}{}, 0},
+		{"flat", map[string]interface{}{"a": 1.0, "b": map[string]interface{}{}}, 1},
+		{"nested", map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": 1.0}}}, 3},
+		{"array", map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "x"}}}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, tt.data)
+			defer os.Remove(tempFile)
+			defer jsonhandler.EvictHandler(tempFile)
+
+			got, err := MaxDepth(tempFile)
+			if err != nil || got != tt.want {
+				t.Errorf("MaxDepth() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitByTopKey(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	outputDir := filepath.Join(t.TempDir(), "split")
+
+	files, err := SplitByTopKey(tempFile, outputDir, false)
+	if err != nil {
+		t.Fatalf("SplitByTopKey() error = %v", err)
+	}
+	if len(files) != len(sampleI18nData) {
+		t.Fatalf("SplitByTopKey() wrote %v, want one file per top-level key", files)
+	}
+	for _, file := range files {
+		defer jsonhandler.EvictHandler(file)
+	}
+
+	dashboardFile := filepath.Join(outputDir, "dashboard.json")
+	if value, err := GetKey(dashboardFile, "title"); err != nil || value != "Dashboard" {
+		t.Errorf("GetKey(title) in %s = %v (%v), want Dashboard", dashboardFile, value, err)
+	}
+
+	if _, err := SplitByTopKey(tempFile, outputDir, false); !errors.Is(err, ErrSplitError) {
+		t.Errorf("SplitByTopKey() over existing files error = %v, want SPLIT_ERROR", err)
+	}
+	if _, err := SplitByTopKey(tempFile, outputDir, true); err != nil {
+		t.Errorf("SplitByTopKey() with overwrite error = %v", err)
+	}
+
+	scalarFile := createTempJSONFile(t, map[string]interface{}{"section": map[string]interface{}{}, "version": 1.0})
+	defer os.Remove(scalarFile)
+	defer jsonhandler.EvictHandler(scalarFile)
+
+	scalarDir := filepath.Join(t.TempDir(), "scalar")
+	if _, err := SplitByTopKey(scalarFile, scalarDir, false); !errors.Is(err, ErrNotContainer) {
+		t.Errorf("SplitByTopKey() with a scalar top-level value error = %v, want NOT_CONTAINER", err)
+	}
+	if _, err := os.Stat(scalarDir); !os.IsNotExist(err) {
+		t.Error("SplitByTopKey() should not write anything when a check fails")
+	}
+}
+
+func TestCombineFiles(t *testing.T) {
+	dir := t.TempDir()
+	dashboardFile := filepath.Join(dir, "dashboard.json")
+	formsFile := filepath.Join(dir, "forms.json")
+	for file, data := range map[string]map[string]interface{}{
+		dashboardFile: {"title": "Dashboard"},
+		formsFile:     {"title": "Forms", "submit": "Send"},
+	} {
+		if err := jsonhandler.GetHandler(file).SaveJSON(data, 2); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+		defer jsonhandler.EvictHandler(file)
+	}
+
+	outputFile := filepath.Join(dir, "app.json")
+	defer jsonhandler.EvictHandler(outputFile)
+
+	keys, err := CombineFiles([]string{dashboardFile, formsFile}, outputFile, true, false)
+	if err != nil {
+		t.Fatalf("CombineFiles() error = %v", err)
+	}
+	if !deepEqual(keys, []string{"dashboard", "forms"}) {
+		t.Errorf("CombineFiles() keys = %v, want [dashboard forms]", keys)
+	}
+	if value, err := GetKey(outputFile, "forms.submit"); err != nil || value != "Send" {
+		t.Errorf("GetKey(forms.submit) = %v (%v), want Send", value, err)
+	}
+
+	// Both files define "title" at the root
+	if _, err := CombineFiles([]string{dashboardFile, formsFile}, outputFile, false, false); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("CombineFiles() with duplicate root keys error = %v, want KEY_EXISTS", err)
+	}
+
+	if _, err := CombineFiles([]string{dashboardFile}, dashboardFile, true, false); !errors.Is(err, ErrCombineError) {
+		t.Errorf("CombineFiles() onto an input error = %v, want COMBINE_ERROR", err)
+	}
+}
+
+func TestSplitThenCombine(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	files, err := SplitByTopKey(tempFile, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("SplitByTopKey() error = %v", err)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "combined.json")
+	defer jsonhandler.EvictHandler(outputFile)
+	if _, err := CombineFiles(files, outputFile, true, false); err != nil {
+		t.Fatalf("CombineFiles() error = %v", err)
+	}
+
+	original, _ := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+	combined, _ := jsonhandler.GetHandler(outputFile).LoadJSON(false)
+	if !deepEqual(original, combined) {
+		t.Errorf("Split then combine = %v, want %v", combined, original)
+	}
+}
+
+func TestRecentChangesFile(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	if records := RecentChanges(tempFile, 10); len(records) != 0 {
+		t.Errorf("RecentChanges() before any edit = %v, want none", records)
+	}
+
+	if err := UpdateKey(tempFile, "dashboard.title", "Home", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	if _, err := RemoveKey(tempFile, "forms"); err != nil {
+		t.Fatalf("RemoveKey() error = %v", err)
+	}
+
+	records := RecentChanges(tempFile, 10)
+	if len(records) != 2 {
+		t.Fatalf("RecentChanges() returned %d records, want 2", len(records))
+	}
+	if !deepEqual(records[0].Paths, []string{"forms"}) || !deepEqual(records[1].Paths, []string{"dashboard.title"}) {
+		t.Errorf("RecentChanges() = %+v", records)
+	}
+}
+
+func TestValidateShape(t *testing.T) {
+	shape := map[string]interface{}{
+		"name":    "example",
+		"port":    8080.0,
+		"tags":    []interface{}{"a"},
+		"db":      map[string]interface{}{"host": "localhost", "pool": 5.0},
+		"feature": map[string]interface{}{"enabled": true},
+	}
+	target := map[string]interface{}{
+		"name":    "prod",
+		"port":    "443",
+		"tags":    []interface{}{},
+		"db":      map[string]interface{}{"host": "db1", "user": "admin"},
+		"feature": map[string]interface{}{"enabled": false},
+		"debug":   true,
+	}
+
+	shapeFile := createTempJSONFile(t, shape)
+	defer os.Remove(shapeFile)
+	defer jsonhandler.EvictHandler(shapeFile)
+	targetFile := createTempJSONFile(t, target)
+	defer os.Remove(targetFile)
+	defer jsonhandler.EvictHandler(targetFile)
+
+	report, err := ValidateShape(targetFile, shapeFile, false)
+	if err != nil {
+		t.Fatalf("ValidateShape() error = %v", err)
+	}
+	if report.Passed {
+		t.Error("ValidateShape() should fail")
+	}
+	if !deepEqual(report.Missing, []string{"db.pool"}) {
+		t.Errorf("Missing = %v, want [db.pool]", report.Missing)
+	}
+	wantMismatch := TypeMismatch{Path: "port", Expected: "number", Actual: "string"}
+	if len(report.TypeMismatches) != 1 || report.TypeMismatches[0] != wantMismatch {
+		t.Errorf("TypeMismatches = %+v, want [%+v]", report.TypeMismatches, wantMismatch)
+	}
+	if report.Extra != nil {
+		t.Errorf("Extra should be omitted when not strict, got %v", report.Extra)
+	}
+
+	report, err = ValidateShape(targetFile, shapeFile, true)
+	if err != nil {
+		t.Fatalf("ValidateShape() strict error = %v", err)
+	}
+	if !deepEqual(report.Extra, []string{"db.user", "debug"}) {
+		t.Errorf("Extra = %v, want [db.user debug]", report.Extra)
+	}
+
+	report, err = ValidateShape(shapeFile, shapeFile, true)
+	if err != nil || !report.Passed {
+		t.Errorf("ValidateShape() of a file against itself = %+v, %v, want passed", report, err)
+	}
+}
+
+func TestResolveRefs(t *testing.T) {
+	data := map[string]interface{}{
+		"definitions": map[string]interface{}{
+			"address": map[string]interface{}{"city": "string"},
+			"person": map[string]interface{}{
+				"name":    "string",
+				"address": map[string]interface{}{"$ref": "#/definitions/address"},
+			},
+			"a/b": "escaped",
+		},
+		"owner":   map[string]interface{}{"$ref": "#/definitions/person", "description": "dropped"},
+		"tags":    []interface{}{map[string]interface{}{"$ref": "#/definitions/a~1b"}},
+		"foreign": map[string]interface{}{"$ref": "other.json#/x"},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	outputFile := filepath.Join(t.TempDir(), "resolved.json")
+	defer jsonhandler.EvictHandler(outputFile)
+
+	result, err := ResolveRefs(tempFile, outputFile, false)
+	if err != nil {
+		t.Fatalf("ResolveRefs() error = %v", err)
+	}
+	// person.address inside definitions, owner, owner's nested address and the array element
+	if result.Resolved != 4 {
+		t.Errorf("ResolveRefs() resolved = %d, want 4", result.Resolved)
+	}
+
+	want := map[string]interface{}{
+		"name":    "string",
+		"address": map[string]interface{}{"city": "string"},
+	}
+	if value, err := GetKey(outputFile, "owner"); err != nil || !deepEqual(value, want) {
+		t.Errorf("GetKey(owner) = %v (%v), want %v", value, err, want)
+	}
+	if value, err := GetKey(outputFile, "tags"); err != nil || !deepEqual(value, []interface{}{"escaped"}) {
+		t.Errorf("GetKey(tags) = %v (%v), want [escaped]", value, err)
+	}
+	if value, _ := GetKey(outputFile, "foreign"); !deepEqual(value, data["foreign"]) {
+		t.Errorf("Non-local reference should be kept, got %v", value)
+	}
+
+	// The source keeps its references
+	if value, _ := GetKey(tempFile, "owner.$ref"); value != "#/definitions/person" {
+		t.Errorf("Source file should be unchanged, owner.$ref = %v", value)
+	}
+}
+
+func TestResolveRefsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:    "self reference",
+			data:    map[string]interface{}{"a": map[string]interface{}{"$ref": "#/a"}},
+			wantErr: ErrCircularRef,
+		},
+		{
+			name: "reference to ancestor",
+			data: map[string]interface{}{
+				"node": map[string]interface{}{"child": map[string]interface{}{"$ref": "#/node"}},
+			},
+			wantErr: ErrCircularRef,
+		},
+		{
+			name: "mutual references",
+			data: map[string]interface{}{
+				"a": map[string]interface{}{"$ref": "#/b"},
+				"b": map[string]interface{}{"$ref": "#/a"},
+			},
+			wantErr: ErrCircularRef,
+		},
+		{
+			name:    "missing target",
+			data:    map[string]interface{}{"a": map[string]interface{}{"$ref": "#/missing"}},
+			wantErr: ErrRefError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, tt.data)
+			defer os.Remove(tempFile)
+			defer jsonhandler.EvictHandler(tempFile)
+
+			if _, err := ResolveRefs(tempFile, "", false); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResolveRefs() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListByType(t *testing.T) {
+	data := map[string]interface{}{
+		"enabled":  true,
+		"name":     "demo",
+		"retries":  3.0,
+		"fallback": nil,
+		"features": map[string]interface{}{"beta": false, "limit": nil},
+		"servers":  []interface{}{map[string]interface{}{"tls": true}},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		jsonType string
+		want     []string
+	}{
+		{"boolean", []string{"enabled", "features.beta", "servers.0.tls"}},
+		{"null", []string{"fallback", "features.limit"}},
+		{"number", []string{"retries"}},
+		{"object", []string{"features", "servers.0"}},
+		{"array", []string{"servers"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jsonType, func(t *testing.T) {
+			got, err := ListByType(tempFile, tt.jsonType)
+			if err != nil {
+				t.Fatalf("ListByType() error = %v", err)
+			}
+			if !deepEqual(got, tt.want) {
+				t.Errorf("ListByType(%s) = %v, want %v", tt.jsonType, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ListByType(tempFile, "integer"); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("ListByType() with unknown type error = %v, want INVALID_TYPE", err)
+	}
+}
+
+func TestPruneEmpty(t *testing.T) {
+	data := map[string]interface{}{
+		"keep":   "value",
+		"empty":  map[string]interface{}{},
+		"nested": map[string]interface{}{"inner": map[string]interface{}{"list": []interface{}{}}},
+		"items":  []interface{}{map[string]interface{}{}, "a", nil, []interface{}{}},
+		"unset":  nil,
+	}
+
+	tests := []struct {
+		name        string
+		removeNull  bool
+		wantRemoved []string
+		want        map[string]interface{}
+	}{
+		{
+			name:        "containers only",
+			removeNull:  false,
+			wantRemoved: []string{"empty", "items.0", "items.3", "nested", "nested.inner", "nested.inner.list"},
+			want:        map[string]interface{}{"keep": "value", "items": []interface{}{"a", nil}, "unset": nil},
+		},
+		{
+			name:        "with nulls",
+			removeNull:  true,
+			wantRemoved: []string{"empty", "items.0", "items.2", "items.3", "nested", "nested.inner", "nested.inner.list", "unset"},
+			want:        map[string]interface{}{"keep": "value", "items": []interface{}{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempFile := createTempJSONFile(t, data)
+			defer os.Remove(tempFile)
+			defer jsonhandler.EvictHandler(tempFile)
+
+			removed, err := PruneEmpty(tempFile, tt.removeNull)
+			if err != nil {
+				t.Fatalf("PruneEmpty() error = %v", err)
+			}
+			if !deepEqual(removed, tt.wantRemoved) {
+				t.Errorf("PruneEmpty() removed = %v, want %v", removed, tt.wantRemoved)
+			}
+
+			got, _ := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+			if !deepEqual(got, tt.want) {
+				t.Errorf("Pruned document = %v, want %v", got, tt.want)
+			}
+
+			if removed, err := PruneEmpty(tempFile, tt.removeNull); err != nil || len(removed) != 0 {
+				t.Errorf("Second PruneEmpty() = %v, %v, want nothing removed", removed, err)
+			}
+		})
+	}
+}
+
+func TestSectionHashes(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	before, err := SectionHashes(tempFile)
+	if err != nil {
+		t.Fatalf("SectionHashes() error = %v", err)
+	}
+	if len(before) != len(sampleI18nData) {
+		t.Fatalf("SectionHashes() = %v, want one hash per top-level key", before)
+	}
+	for key, hash := range before {
+		if len(hash) != 64 {
+			t.Errorf("Hash of %s = %q, want 64 hex characters", key, hash)
+		}
+	}
+
+	// Reformatting the file leaves the hashes alone
+	content, _ := json.Marshal(sampleI18nData)
+	if err := os.WriteFile(tempFile, content, 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	jsonhandler.EvictHandler(tempFile)
+	reformatted, err := SectionHashes(tempFile)
+	if err != nil || !deepEqual(reformatted, before) {
+		t.Errorf("SectionHashes() after reformatting = %v (%v), want %v", reformatted, err, before)
+	}
+
+	if err := UpdateKey(tempFile, "dashboard.title", "Home", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	after, err := SectionHashes(tempFile)
+	if err != nil {
+		t.Fatalf("SectionHashes() error = %v", err)
+	}
+	for key := range before {
+		if changed := after[key] != before[key]; changed != (key == "dashboard") {
+			t.Errorf("Hash of %s changed = %v after editing dashboard", key, changed)
+		}
+	}
+}
+
+func TestImportNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	ndjsonFile := filepath.Join(dir, "events.ndjson")
+	content := "{\"id\": 1}\r\n\n{\"id\": 2, \"tags\": [\"a\"]}\nnot json\n\"plain\"\n"
+	if err := os.WriteFile(ndjsonFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	defer jsonhandler.EvictHandler(ndjsonFile)
+
+	jsonFile := filepath.Join(dir, "events.json")
+	defer jsonhandler.EvictHandler(jsonFile)
+
+	_, err := ImportNDJSON(ndjsonFile, jsonFile, "records", false, false)
+	if !errors.Is(err, ErrNDJSONError) || !strings.Contains(err.Error(), "Line 4") {
+		t.Errorf("ImportNDJSON() error = %v, want NDJSON_ERROR for line 4", err)
+	}
+
+	result, err := ImportNDJSON(ndjsonFile, jsonFile, "records", true, false)
+	if err != nil {
+		t.Fatalf("ImportNDJSON() error = %v", err)
+	}
+	if result.Records != 3 || len(result.Skipped) != 1 || result.Skipped[0].Line != 4 {
+		t.Errorf("ImportNDJSON() = %+v, want 3 records and line 4 skipped", result)
+	}
+
+	want := []interface{}{
+		map[string]interface{}{"id": 1.0},
+		map[string]interface{}{"id": 2.0, "tags": []interface{}{"a"}},
+		"plain",
+	}
+	if value, err := GetKey(jsonFile, "records"); err != nil || !deepEqual(value, want) {
+		t.Errorf("GetKey(records) = %v (%v), want %v", value, err, want)
+	}
+
+	if _, err := ImportNDJSON(ndjsonFile, jsonFile, "", true, false); err != nil {
+		t.Fatalf("ImportNDJSON() without key error = %v", err)
+	}
+	saved, _ := os.ReadFile(jsonFile)
+	var array []interface{}
+	if err := json.Unmarshal(saved, &array); err != nil || !deepEqual(array, want) {
+		t.Errorf("Top-level array = %s (%v), want %v", saved, err, want)
+	}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	data := map[string]interface{}{
+		"records": []interface{}{
+			map[string]interface{}{"id": 1.0, "note": "line\nbreak"},
+			"plain",
+			[]interface{}{1.0, 2.0},
+		},
+		"name": "demo",
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	outputFile := filepath.Join(t.TempDir(), "records.ndjson")
+	defer jsonhandler.EvictHandler(outputFile)
+
+	lines, err := ExportNDJSON(tempFile, "records", outputFile, false)
+	if err != nil {
+		t.Fatalf("ExportNDJSON() error = %v", err)
+	}
+	if lines != 3 {
+		t.Errorf("ExportNDJSON() = %d lines, want 3", lines)
+	}
+
+	content, _ := os.ReadFile(outputFile)
+	want := "{\"id\":1,\"note\":\"line\\nbreak\"}\n\"plain\"\n[1,2]\n"
+	if string(content) != want {
+		t.Errorf("NDJSON output = %q, want %q", content, want)
+	}
+
+	if _, err := ExportNDJSON(tempFile, "name", outputFile, false); !errors.Is(err, ErrNotArray) {
+		t.Errorf("ExportNDJSON() of a string error = %v, want NOT_ARRAY", err)
+	}
+	if _, err := ExportNDJSON(tempFile, "", outputFile, false); !errors.Is(err, ErrNotArray) {
+		t.Errorf("ExportNDJSON() of an object root error = %v, want NOT_ARRAY", err)
+	}
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	ndjsonFile := filepath.Join(dir, "in.ndjson")
+	content := "{\"a\":1}\n{\"b\":[true,null]}\n"
+	if err := os.WriteFile(ndjsonFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	jsonFile := filepath.Join(dir, "array.json")
+	outputFile := filepath.Join(dir, "out.ndjson")
+	for _, file := range []string{ndjsonFile, jsonFile, outputFile} {
+		defer jsonhandler.EvictHandler(file)
+	}
+
+	if _, err := ImportNDJSON(ndjsonFile, jsonFile, "", false, false); err != nil {
+		t.Fatalf("ImportNDJSON() error = %v", err)
+	}
+	if _, err := ExportNDJSON(jsonFile, "", outputFile, false); err != nil {
+		t.Fatalf("ExportNDJSON() error = %v", err)
+	}
+
+	exported, _ := os.ReadFile(outputFile)
+	if string(exported) != content {
+		t.Errorf("Round trip = %q, want %q", exported, content)
+	}
+}
+
+func TestEffectiveValue(t *testing.T) {
+	defaults := createTempJSONFile(t, map[string]interface{}{
+		"db":      map[string]interface{}{"host": "localhost", "port": 5432.0},
+		"cache":   map[string]interface{}{"ttl": 60.0},
+		"logging": "info",
+	})
+	defer os.Remove(defaults)
+	defer jsonhandler.EvictHandler(defaults)
+	overrides := createTempJSONFile(t, map[string]interface{}{
+		"db":    map[string]interface{}{"host": "db.internal"},
+		"cache": "disabled",
+	})
+	defer os.Remove(overrides)
+	defer jsonhandler.EvictHandler(overrides)
+
+	files := []string{defaults, overrides}
+
+	tests := []struct {
+		keyPath       string
+		wantValue     interface{}
+		wantSource    string
+		wantDefinedIn []string
+	}{
+		{"db.host", "db.internal", overrides, []string{defaults, overrides}},
+		{"db.port", 5432.0, defaults, []string{defaults}},
+		{"db", map[string]interface{}{"host": "db.internal", "port": 5432.0}, overrides, []string{defaults, overrides}},
+		{"logging", "info", defaults, []string{defaults}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyPath, func(t *testing.T) {
+			result, err := EffectiveValue(tt.keyPath, files)
+			if err != nil {
+				t.Fatalf("EffectiveValue() error = %v", err)
+			}
+			if !deepEqual(result.Value, tt.wantValue) || result.Source != tt.wantSource || !deepEqual(result.DefinedIn, tt.wantDefinedIn) {
+				t.Errorf("EffectiveValue() = %+v, want value %v from %s", result, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+
+	// The override replaced the cache object with a string
+	if _, err := EffectiveValue("cache.ttl", files); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("EffectiveValue() of an overridden parent error = %v, want KEY_NOT_FOUND", err)
+	}
+
+	// Merging must not leak into the cached documents
+	if value, _ := GetKey(defaults, "db.host"); value != "localhost" {
+		t.Errorf("defaults db.host = %v after merging, want localhost", value)
+	}
+}
+
+func TestMissingKeys(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	expected := []string{"dashboard.title", "dashboard.subtitle", "forms", "auth.logout", "dashboard.subtitle"}
+	missing, err := MissingKeys(tempFile, expected)
+	if err != nil {
+		t.Fatalf("MissingKeys() error = %v", err)
+	}
+	if !deepEqual(missing, []string{"dashboard.subtitle", "auth.logout"}) {
+		t.Errorf("MissingKeys() = %v, want [dashboard.subtitle auth.logout]", missing)
+	}
+
+	if _, err := MissingKeys(tempFile, []string{""}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("MissingKeys() with empty path error = %v, want INVALID_PATH", err)
+	}
+}
+
+func TestUnusedKeys(t *testing.T) {
+	data := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"login":  map[string]interface{}{"title": "Sign In", "submit": "Go"},
+			"logout": "Sign Out",
+		},
+		"errors":  map[string]interface{}{"404": "Not found", "500": "Server error"},
+		"legacy":  "Old",
+		"authors": "Team",
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		name       string
+		usedPaths  []string
+		alwaysUsed []string
+		want       []string
+	}{
+		{"nothing used", nil, nil, []string{"auth.login.submit", "auth.login.title", "auth.logout", "authors", "errors.404", "errors.500", "legacy"}},
+		{"object covers leaves", []string{"auth.login", "legacy"}, nil, []string{"auth.logout", "authors", "errors.404", "errors.500"}},
+		{"prefix is not a substring match", []string{"auth"}, nil, []string{"authors", "errors.404", "errors.500", "legacy"}},
+		{"always used", []string{"auth.logout"}, []string{"errors"}, []string{"auth.login.submit", "auth.login.title", "authors", "legacy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnusedKeys(tempFile, tt.usedPaths, tt.alwaysUsed)
+			if err != nil {
+				t.Fatalf("UnusedKeys() error = %v", err)
+			}
+			if !deepEqual(got, tt.want) {
+				t.Errorf("UnusedKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewSave(t *testing.T) {
+	data := map[string]interface{}{"html": "<b>", "nested": map[string]interface{}{"n": 1.0}}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	before, _ := os.ReadFile(tempFile)
+
+	preview, err := PreviewSave(tempFile, 2)
+	if err != nil {
+		t.Fatalf("PreviewSave() error = %v", err)
+	}
+	want := "{\n  \"html\": \"<b>\",\n  \"nested\": {\n    \"n\": 1\n  }\n}\n"
+	if preview != want {
+		t.Errorf("PreviewSave() = %q, want %q", preview, want)
+	}
+
+	SetEscapeHTML(tempFile, true)
+	preview, err = PreviewSave(tempFile, 0)
+	if err != nil {
+		t.Fatalf("PreviewSave() error = %v", err)
+	}
+	if want := "{\"html\":\"\\u003cb\\u003e\",\"nested\":{\"n\":1}}\n"; preview != want {
+		t.Errorf("PreviewSave() compact with escaping = %q, want %q", preview, want)
+	}
+
+	// The preview matches what a save writes, and nothing was written before it
+	if after, _ := os.ReadFile(tempFile); !bytes.Equal(after, before) {
+		t.Error("PreviewSave() should not modify the file")
+	}
+	if err := UpdateKey(tempFile, "nested.n", 1.0, false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	preview, _ = PreviewSave(tempFile, 2)
+	if saved, _ := os.ReadFile(tempFile); string(saved) != preview {
+		t.Errorf("Saved file = %q, want the preview %q", saved, preview)
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"pages": map[string]interface{}{
+			"home":  map[string]interface{}{"title": "Home"},
+			"empty": map[string]interface{}{},
+		},
+	}
+	template := map[string]interface{}{
+		"title":   "${name} settings",
+		"actions": []interface{}{"Save ${name}", "Cancel"},
+		"price":   "$5 and ${currency}",
+		"order":   3.0,
+	}
+	vars := map[string]string{"name": "Profile", "currency": "EUR"}
+
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	if err := ApplyTemplate(tempFile, "pages.profile", template, vars, false); err != nil {
+		t.Fatalf("ApplyTemplate() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"title":   "Profile settings",
+		"actions": []interface{}{"Save Profile", "Cancel"},
+		"price":   "$5 and EUR",
+		"order":   3.0,
+	}
+	if value, err := GetKey(tempFile, "pages.profile"); err != nil || !deepEqual(value, want) {
+		t.Errorf("GetKey(pages.profile) = %v (%v), want %v", value, err, want)
+	}
+	if template["title"] != "${name} settings" {
+		t.Error("ApplyTemplate() should not modify the template")
+	}
+
+	// Empty containers may be filled, existing values need force
+	if err := ApplyTemplate(tempFile, "pages.empty", template, vars, false); err != nil {
+		t.Errorf("ApplyTemplate() over an empty object error = %v", err)
+	}
+	if err := ApplyTemplate(tempFile, "pages.home", template, vars, false); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("ApplyTemplate() over an existing value error = %v, want KEY_EXISTS", err)
+	}
+	if err := ApplyTemplate(tempFile, "pages.home", template, vars, true); err != nil {
+		t.Errorf("ApplyTemplate() with force error = %v", err)
+	}
+
+	err := ApplyTemplate(tempFile, "pages.other", template, map[string]string{"name": "Other"}, false)
+	if !errors.Is(err, ErrUndefinedVar) || !strings.Contains(err.Error(), "currency") {
+		t.Errorf("ApplyTemplate() with a missing variable error = %v, want UNDEFINED_VARIABLE naming currency", err)
+	}
+	if _, err := GetKey(tempFile, "pages.other"); !errors.Is(err, ErrKeyNotFound) {
+		t.Error("ApplyTemplate() should not write anything when a variable is missing")
+	}
+}
+
+func TestPathsOfValue(t *testing.T) {
+	data := map[string]interface{}{
+		"title":  "Dashboard",
+		"nav":    map[string]interface{}{"home": "Dashboard", "back": nil},
+		"items":  []interface{}{"Dashboard", map[string]interface{}{"id": 1.0}},
+		"unset":  nil,
+		"single": map[string]interface{}{"id": 1.0},
+	}
+	tempFile := createTempJSONFile(t, data)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{"string", "Dashboard", []string{"items.0", "nav.home", "title"}},
+		{"object", map[string]interface{}{"id": 1}, []string{"items.1", "single"}},
+		{"null", nil, []string{"nav.back", "unset"}},
+		{"no match", "Missing", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PathsOfValue(tempFile, tt.value)
+			if err != nil {
+				t.Fatalf("PathsOfValue() error = %v", err)
+			}
+			if !deepEqual(got, tt.want) {
+				t.Errorf("PathsOfValue(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReorderToMatch(t *testing.T) {
+	reference := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(reference)
+	defer jsonhandler.EvictHandler(reference)
+	target := createTempJSONFile(t, simpleTestData)
+	defer os.Remove(target)
+	defer jsonhandler.EvictHandler(target)
+
+	if err := os.WriteFile(reference, []byte(`{"title": "x", "menu": {"open": "x", "close": "x"}, "items": [{"id": 1, "name": "x"}]}`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(target, []byte(`{"extra": 1.50, "items": [{"name": "b", "id": 2}], "menu": {"close": "c", "open": "o"}, "title": "<t>"}`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reordered, err := ReorderToMatch(target, reference)
+	if err != nil {
+		t.Fatalf("ReorderToMatch() error = %v", err)
+	}
+	if reordered != 3 {
+		t.Errorf("ReorderToMatch() reordered = %d, want 3", reordered)
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "title": "<t>",
+  "menu": {
+    "open": "o",
+    "close": "c"
+  },
+  "items": [
+    {
+      "id": 2,
+      "name": "b"
+    }
+  ],
+  "extra": 1.50
+}
+`
+	if string(content) != want {
+		t.Errorf("ReorderToMatch() wrote:\n%s\nwant:\n%s", content, want)
+	}
+
+	// A second pass has nothing left to do
+	reordered, err = ReorderToMatch(target, reference)
+	if err != nil {
+		t.Fatalf("ReorderToMatch() error = %v", err)
+	}
+	if reordered != 0 {
+		t.Errorf("ReorderToMatch() second pass reordered = %d, want 0", reordered)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3,\n  \"d\": 4,\n  \"e\": 5,\n  \"f\": 6,\n  \"g\": 7,\n  \"h\": 8,\n  \"i\": 9\n}\n"
+	after := "{\n  \"a\": 10,\n  \"b\": 2,\n  \"c\": 3,\n  \"d\": 4,\n  \"e\": 5,\n  \"f\": 6,\n  \"g\": 7,\n  \"h\": 8,\n  \"i\": 9,\n  \"j\": 10\n}"
+
+	want := `--- old.json
++++ new.json
+@@ -1,5 +1,5 @@
+ {
+-  "a": 1,
++  "a": 10,
+   "b": 2,
+   "c": 3,
+   "d": 4,
+@@ -7,5 +7,6 @@
+   "f": 6,
+   "g": 7,
+   "h": 8,
+-  "i": 9
+-}
++  "i": 9,
++  "j": 10
++}
+\ No newline at end of file
+`
+	if got := UnifiedDiff("old.json", "new.json", []byte(before), []byte(after)); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("old.json", "new.json", []byte(before), []byte(before)); got != "" {
+		t.Errorf("UnifiedDiff() of identical content = %q, want empty", got)
+	}
+
+	want = "--- old.json\n+++ new.json\n@@ -0,0 +1 @@\n+{}\n"
+	if got := UnifiedDiff("old.json", "new.json", nil, []byte("{}\n")); got != want {
+		t.Errorf("UnifiedDiff() from empty = %q, want %q", got, want)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	// lcsLength is the reference the edit count is checked against: a shortest edit
+	// script removes and adds exactly the lines outside a longest common subsequence
+	lcsLength := func(a, b []string) int {
+		row := make([]int, len(b)+1)
+		for i := range a {
+			diagonal := 0
+			for j := range b {
+				above := row[j+1]
+				if a[i] == b[j] {
+					row[j+1] = diagonal + 1
+				} else if row[j] > row[j+1] {
+					row[j+1] = row[j]
+				}
+				diagonal = above
+			}
+		}
+		return row[len(b)]
+	}
+
+	checkScript := func(t *testing.T, a, b []string, wantEdits int) {
+		t.Helper()
+		script := diffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, line := range script {
+			if line.kind != '+' {
+				gotA = append(gotA, line.text)
+			}
+			if line.kind != '-' {
+				gotB = append(gotB, line.text)
+			}
+			if line.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diffLines() script does not turn %q into %q", a, b)
+		}
+		if edits != wantEdits {
+			t.Errorf("diffLines() made %d edits, want %d", edits, wantEdits)
+		}
+	}
+
+	// Small pseudo-random documents over a few distinct lines, compared with the LCS
+	seed := uint32(1)
+	randomLines := func() []string {
+		lines := make([]string, 0, 12)
+		seed = seed*1664525 + 1013904223
+		for i := 0; i < int(seed>>28); i++ {
+			seed = seed*1664525 + 1013904223
+			lines = append(lines, string(rune('a'+seed>>30))+"\n")
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		checkScript(t, a, b, len(a)+len(b)-2*lcsLength(a, b))
+	}
+
+	// Reversing every line is the worst case; the old trace-based search ran out of memory here
+	lines := make([]string, 10000)
+	reversed := make([]string, len(lines))
+	for i := range lines {
+		lines[i] = fmt.Sprintf("  \"key%d\": %d,\n", i, i)
+		reversed[len(lines)-1-i] = lines[i]
+	}
+	checkScript(t, lines, reversed, 2*len(lines)-2)
+}
+
+func TestNestFlatKeys(t *testing.T) {
+	t.Run("nests and merges", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, map[string]interface{}{
+			"a.b.c": 1.0,
+			"a.b.d": 2.0,
+			"a":     map[string]interface{}{"x": true},
+			"a.e":   map[string]interface{}{"f": "g"},
+			"plain": "kept",
+		})
+		defer os.Remove(tempFile)
+		defer jsonhandler.EvictHandler(tempFile)
+
+		nested, err := NestFlatKeys(tempFile, ".")
+		if err != nil {
+			t.Fatalf("NestFlatKeys() error = %v", err)
+		}
+		if want := []string{"a.b.c", "a.b.d", "a.e"}; !deepEqual(nested, want) {
+			t.Errorf("NestFlatKeys() = %v, want %v", nested, want)
+		}
+
+		data, err := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"a": map[string]interface{}{
+				"x": true,
+				"b": map[string]interface{}{"c": 1.0, "d": 2.0},
+				"e": map[string]interface{}{"f": "g"},
+			},
+			"plain": "kept",
+		}
+		if !deepEqual(data, want) {
+			t.Errorf("NestFlatKeys() left %v, want %v", data, want)
+		}
+	})
+
+	t.Run("custom delimiter", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, map[string]interface{}{"a/b": 1.0, "c.d": 2.0})
+		defer os.Remove(tempFile)
+		defer jsonhandler.EvictHandler(tempFile)
+
+		if _, err := NestFlatKeys(tempFile, "/"); err != nil {
+			t.Fatalf("NestFlatKeys() error = %v", err)
+		}
+		value, err := GetKey(tempFile, "a.b")
+		if err != nil || value != 1.0 {
+			t.Errorf("GetKey(a.b) = %v, %v, want 1", value, err)
+		}
+		value, err = GetKey(tempFile, "c.d")
+		if err != nil || value != 2.0 {
+			t.Errorf("GetKey(c.d) = %v, %v, want 2", value, err)
+		}
+	})
+
+	t.Run("conflict leaves file untouched", func(t *testing.T) {
+		tempFile := createTempJSONFile(t, map[string]interface{}{"a": "leaf", "a.b": 1.0})
+		defer os.Remove(tempFile)
+		defer jsonhandler.EvictHandler(tempFile)
+
+		before, _ := os.ReadFile(tempFile)
+		if _, err := NestFlatKeys(tempFile, "."); !errors.Is(err, ErrKeyExists) {
+			t.Errorf("NestFlatKeys() error = %v, want KEY_EXISTS", err)
+		}
+		after, _ := os.ReadFile(tempFile)
+		if !bytes.Equal(before, after) {
+			t.Error("NestFlatKeys() modified the file despite the conflict")
+		}
+	})
+}
+
+func TestGetAncestors(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"a":     map[string]interface{}{"b": map[string]interface{}{"c": 1.0}},
+		"x.y":   "literal",
+		"plain": "value",
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	ancestors, err := GetAncestors(tempFile, "a.b.c")
+	if err != nil {
+		t.Fatalf("GetAncestors() error = %v", err)
+	}
+	want := []AncestorValue{
+		{"a", map[string]interface{}{"b": map[string]interface{}{"c": 1.0}}},
+		{"a.b", map[string]interface{}{"c": 1.0}},
+		{"a.b.c", 1.0},
+	}
+	if len(ancestors) != len(want) {
+		t.Fatalf("GetAncestors() returned %d levels, want %d", len(ancestors), len(want))
+	}
+	for i := range want {
+		if ancestors[i].Path != want[i].Path || !deepEqual(ancestors[i].Value, want[i].Value) {
+			t.Errorf("GetAncestors()[%d] = %+v, want %+v", i, ancestors[i], want[i])
+		}
+	}
+
+	ancestors, err = GetAncestors(tempFile, "x.y")
+	if err != nil || len(ancestors) != 1 || ancestors[0].Value != "literal" {
+		t.Errorf("GetAncestors(x.y) = %+v, %v, want the literal key only", ancestors, err)
+	}
+
+	if _, err := GetAncestors(tempFile, "a.b.missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetAncestors() error = %v, want KEY_NOT_FOUND", err)
+	}
+	if _, err := GetAncestors(tempFile, "plain.deeper"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetAncestors() through a string error = %v, want KEY_NOT_FOUND", err)
+	}
+	if _, err := GetAncestors(tempFile, ""); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("GetAncestors() with empty path error = %v, want INVALID_PATH", err)
+	}
+}
+
+func TestAssertNumericRange(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"services": map[string]interface{}{
+			"api":    map[string]interface{}{"timeout": 30.0},
+			"db":     map[string]interface{}{"timeout": 0.0},
+			"queue":  map[string]interface{}{"timeout": 301.0},
+			"cache":  map[string]interface{}{"timeout": "60"},
+			"worker": map[string]interface{}{"timeout": 300.0},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	report, err := AssertNumericRange(tempFile, "services.*.timeout", 1, 300)
+	if err != nil {
+		t.Fatalf("AssertNumericRange() error = %v", err)
+	}
+	if report.Checked != 5 {
+		t.Errorf("AssertNumericRange() checked = %d, want 5", report.Checked)
+	}
+	wantRange := []RangeViolation{{"services.db.timeout", 0}, {"services.queue.timeout", 301}}
+	if !deepEqual(report.OutOfRange, wantRange) {
+		t.Errorf("AssertNumericRange() out of range = %v, want %v", report.OutOfRange, wantRange)
+	}
+	wantType := []TypeViolation{{"services.cache.timeout", "string"}}
+	if !deepEqual(report.WrongType, wantType) {
+		t.Errorf("AssertNumericRange() wrong type = %v, want %v", report.WrongType, wantType)
+	}
+
+	if _, err := AssertNumericRange(tempFile, "services.*.timeout", 10, 1); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("AssertNumericRange() error = %v, want INVALID_RANGE", err)
+	}
+}
+
+func TestListKeysWithValues(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"section": map[string]interface{}{
+			"title":   "A fairly long title string",
+			"count":   3.0,
+			"enabled": true,
+			"none":    nil,
+			"items":   []interface{}{1.0, 2.0},
+			"nested":  map[string]interface{}{"a": 1.0, "b": 2.0, "c": 3.0},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	keyPath := "section"
+	previews, err := ListKeysWithValues(tempFile, &keyPath, 10)
+	if err != nil {
+		t.Fatalf("ListKeysWithValues() error = %v", err)
+	}
+	want := []KeyPreview{
+		{"count", "number", "3"},
+		{"enabled", "boolean", "true"},
+		{"items", "array", "[2 items]"},
+		{"nested", "object", "{3 keys}"},
+		{"none", "null", "null"},
+		{"title", "string", "\"A fairly …"},
+	}
+	if !deepEqual(previews, want) {
+		t.Errorf("ListKeysWithValues() = %v, want %v", previews, want)
+	}
+
+	previews, err = ListKeysWithValues(tempFile, nil, 0)
+	if err != nil || len(previews) != 1 || previews[0].Preview != "{6 keys}" {
+		t.Errorf("ListKeysWithValues(root) = %v, %v, want one section summary", previews, err)
+	}
+
+	missing := "missing"
+	if _, err := ListKeysWithValues(tempFile, &missing, 10); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("ListKeysWithValues() error = %v, want KEY_NOT_FOUND", err)
+	}
+	for _, scalar := range []string{"section.title", "section.items"} {
+		if _, err := ListKeysWithValues(tempFile, &scalar, 10); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("ListKeysWithValues(%s) error = %v, want INVALID_PATH", scalar, err)
+		}
+	}
+}
+
+func TestCreateFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "locales", "de", "messages.json")
+	defer jsonhandler.EvictHandler(filePath)
+
+	if err := CreateFile(filePath, nil, false); err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	keys, err := ListKeys(filePath, nil)
+	if err != nil || len(keys) != 0 {
+		t.Errorf("ListKeys() after CreateFile() = %v, %v, want no keys", keys, err)
+	}
+
+	if err := CreateFile(filePath, map[string]interface{}{"a": 1.0}, false); !errors.Is(err, ErrFileExists) {
+		t.Errorf("CreateFile() on existing file error = %v, want FILE_EXISTS", err)
+	}
+
+	if err := CreateFile(filePath, map[string]interface{}{"a": 1.0}, true); err != nil {
+		t.Fatalf("CreateFile() with overwrite error = %v", err)
+	}
+	value, err := GetKey(filePath, "a")
+	if err != nil || value != 1.0 {
+		t.Errorf("GetKey() after overwrite = %v, %v, want 1", value, err)
+	}
+
+	if err := CreateFile(dir, nil, true); !errors.Is(err, jsonhandler.ErrNotRegularFile) {
+		t.Errorf("CreateFile() on a directory error = %v, want NOT_A_REGULAR_FILE", err)
+	}
+}
+
+func TestCreateDirs(t *testing.T) {
+	tempFile := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	outputFile := filepath.Join(t.TempDir(), "configs", "new", "app.json")
+	defer jsonhandler.EvictHandler(outputFile)
+
+	if _, err := Pick(tempFile, []string{"dashboard"}, outputFile, false); err == nil {
+		t.Fatal("Pick() into a missing directory succeeded without create_dirs")
+	}
+
+	if _, err := Pick(tempFile, []string{"dashboard"}, outputFile, true); err != nil {
+		t.Fatalf("Pick() with create_dirs error = %v", err)
+	}
+	if _, err := GetKey(outputFile, "dashboard.title"); err != nil {
+		t.Errorf("GetKey() on created file error = %v", err)
+	}
+}
+
+func TestFilesEqual(t *testing.T) {
+	fileA := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(fileA)
+	defer jsonhandler.EvictHandler(fileA)
+	fileB := createTempJSONFile(t, sampleI18nData)
+	defer os.Remove(fileB)
+	defer jsonhandler.EvictHandler(fileB)
+
+	// Same document, different formatting
+	content, err := json.Marshal(sampleI18nData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileB, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	comparison, err := FilesEqual(fileA, fileB)
+	if err != nil {
+		t.Fatalf("FilesEqual() error = %v", err)
+	}
+	if !comparison.Equal || comparison.DifferingPaths != 0 {
+		t.Errorf("FilesEqual() = %+v, want equal", comparison)
+	}
+
+	if err := UpdateKey(fileB, "dashboard.title", "Changed", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddKey(fileB, "dashboard.extra", "New"); err != nil {
+		t.Fatal(err)
+	}
+
+	comparison, err = FilesEqual(fileA, fileB)
+	if err != nil {
+		t.Fatalf("FilesEqual() error = %v", err)
+	}
+	if comparison.Equal || comparison.DifferingPaths != 2 {
+		t.Errorf("FilesEqual() = %+v, want 2 differing paths", comparison)
+	}
+}
+
+func TestTypeDiff(t *testing.T) {
+	fileA := createTempJSONFile(t, map[string]interface{}{
+		"count":   3.0,
+		"label":   "x",
+		"onlyA":   true,
+		"nested":  map[string]interface{}{"flag": true, "list": []interface{}{1.0, "two"}},
+		"section": map[string]interface{}{"a": 1.0},
+	})
+	defer os.Remove(fileA)
+	defer jsonhandler.EvictHandler(fileA)
+	fileB := createTempJSONFile(t, map[string]interface{}{
+		"count":   "3",
+		"label":   "y",
+		"onlyB":   nil,
+		"nested":  map[string]interface{}{"flag": "true", "list": []interface{}{1.0, 2.0, 3.0}},
+		"section": "flattened",
+	})
+	defer os.Remove(fileB)
+	defer jsonhandler.EvictHandler(fileB)
+
+	differences, err := TypeDiff(fileA, fileB)
+	if err != nil {
+		t.Fatalf("TypeDiff() error = %v", err)
+	}
+	want := []TypeDifference{
+		{"count", "number", "string"},
+		{"nested.flag", "boolean", "string"},
+		{"nested.list.1", "string", "number"},
+		{"section", "object", "string"},
+	}
+	if !deepEqual(differences, want) {
+		t.Errorf("TypeDiff() = %v, want %v", differences, want)
+	}
+}
+
+func TestGenerateTSInterface(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"name":         "app",
+		"port":         8080.0,
+		"debug":        false,
+		"parent":       nil,
+		"tags":         []interface{}{"a", "b"},
+		"empty":        []interface{}{},
+		"mixed":        []interface{}{1.0, "x", nil},
+		"content-type": "json",
+		"server": map[string]interface{}{
+			"host": "localhost",
+		},
+		"routes": []interface{}{
+			map[string]interface{}{"path": "/", "auth": true},
+			map[string]interface{}{"path": "/health", "timeout": nil},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	source, err := GenerateTSInterface(tempFile, "AppConfig")
+	if err != nil {
+		t.Fatalf("GenerateTSInterface() error = %v", err)
+	}
+
+	want := `export interface AppConfig {
+  "content-type": string;
+  debug: boolean;
+  empty: unknown[];
+  mixed: (string | number | null)[];
+  name: string;
+  parent: null;
+  port: number;
+  routes: AppConfigRoutesItem[];
+  server: AppConfigServer;
+  tags: string[];
+}
+
+export interface AppConfigRoutesItem {
+  auth?: boolean;
+  path: string;
+  timeout?: null;
+}
+
+export interface AppConfigServer {
+  host: string;
+}
+`
+	if source != want {
+		t.Errorf("GenerateTSInterface() =\n%s\nwant:\n%s", source, want)
+	}
+
+	if _, err := GenerateTSInterface(tempFile, "not valid"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("GenerateTSInterface() error = %v, want INVALID_NAME", err)
+	}
+}
+
+func TestDeepestPath(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": 1.0}},
+		"x": map[string]interface{}{"y": map[string]interface{}{"z": 2.0}},
+		"list": []interface{}{
+			map[string]interface{}{"name": "first"},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	// a.b.c and x.y.z tie with list.0.name; a.b.c comes first in key order
+	deepest, err := DeepestPath(tempFile)
+	if err != nil {
+		t.Fatalf("DeepestPath() error = %v", err)
+	}
+	if deepest.Path != "a.b.c" || deepest.Depth != 3 {
+		t.Errorf("DeepestPath() = %+v, want a.b.c at depth 3", deepest)
+	}
+
+	if err := AddKey(tempFile, "x.y.w", map[string]interface{}{"v": true}); err != nil {
+		t.Fatal(err)
+	}
+	deepest, err = DeepestPath(tempFile)
+	if err != nil {
+		t.Fatalf("DeepestPath() error = %v", err)
+	}
+	if deepest.Path != "x.y.w.v" || deepest.Depth != 4 {
+		t.Errorf("DeepestPath() = %+v, want x.y.w.v at depth 4", deepest)
+	}
+}
+
+func TestSetMatching(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"features": map[string]interface{}{
+			"search":  map[string]interface{}{"enabled": true},
+			"export":  map[string]interface{}{"enabled": false},
+			"beta":    map[string]interface{}{"enabled": map[string]interface{}{"web": true}},
+			"sharing": map[string]interface{}{"enabled": true},
+			"legacy":  map[string]interface{}{},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	want := []string{"features.search.enabled", "features.sharing.enabled"}
+
+	paths, err := SetMatching(tempFile, "features.*.enabled", false, true)
+	if err != nil {
+		t.Fatalf("SetMatching() dry run error = %v", err)
+	}
+	if !deepEqual(paths, want) {
+		t.Errorf("SetMatching() dry run = %v, want %v", paths, want)
+	}
+	if value, _ := GetKey(tempFile, "features.search.enabled"); value != true {
+		t.Error("SetMatching() dry run modified the file")
+	}
+
+	paths, err = SetMatching(tempFile, "features.*.enabled", false, false)
+	if err != nil {
+		t.Fatalf("SetMatching() error = %v", err)
+	}
+	if !deepEqual(paths, want) {
+		t.Errorf("SetMatching() = %v, want %v", paths, want)
+	}
+	for _, path := range want {
+		if value, _ := GetKey(tempFile, path); value != false {
+			t.Errorf("GetKey(%s) = %v, want false", path, value)
+		}
+	}
+	if value, _ := GetKey(tempFile, "features.beta.enabled.web"); value != true {
+		t.Error("SetMatching() replaced an object, which is not a leaf")
+	}
+	if exists, _ := KeyExists(tempFile, "features.legacy.enabled"); exists {
+		t.Error("SetMatching() created a new key")
+	}
+
+	// A key containing dots is set in place, not at the nested path it spells
+	dottedFile := createTempJSONFile(t, map[string]interface{}{
+		"legacy": map[string]interface{}{"x.y": 1.0, "x": map[string]interface{}{"y": 2.0}},
+	})
+	defer os.Remove(dottedFile)
+	defer jsonhandler.EvictHandler(dottedFile)
+
+	paths, err = SetMatching(dottedFile, "legacy.*", "NEW", false)
+	if err != nil || !deepEqual(paths, []string{"legacy.x.y"}) {
+		t.Fatalf("SetMatching() dotted keys = %v, %v, want [legacy.x.y]", paths, err)
+	}
+	legacy, err := GetKey(dottedFile, "legacy")
+	dottedWant := map[string]interface{}{"x.y": "NEW", "x": map[string]interface{}{"y": 2.0}}
+	if err != nil || !deepEqual(legacy, dottedWant) {
+		t.Errorf("legacy after SetMatching() = %v, %v, want %v", legacy, err, dottedWant)
+	}
+}
+
+func TestCompleteness(t *testing.T) {
+	referenceFile := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{
+			"title":    "Home",
+			"subtitle": "Welcome",
+			"cta":      "Start",
+		},
+		"errors": map[string]interface{}{"notFound": "Not found"},
+	})
+	defer os.Remove(referenceFile)
+	defer jsonhandler.EvictHandler(referenceFile)
+
+	targetFile := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{
+			"title":    "Startseite",
+			"subtitle": "  ",
+			"cta":      nil,
+		},
+		"extra": "Nur hier",
+	})
+	defer os.Remove(targetFile)
+	defer jsonhandler.EvictHandler(targetFile)
+
+	report, err := Completeness(referenceFile, targetFile)
+	if err != nil {
+		t.Fatalf("Completeness() error = %v", err)
+	}
+
+	want := &CompletenessReport{Percentage: 25, Total: 4, Translated: 1, Missing: 1, Empty: 2}
+	if *report != *want {
+		t.Errorf("Completeness() = %+v, want %+v", report, want)
+	}
+
+	report, err = Completeness(referenceFile, referenceFile)
+	if err != nil {
+		t.Fatalf("Completeness() error = %v", err)
+	}
+	if report.Percentage != 100 {
+		t.Errorf("Completeness() of a file against itself = %v%%, want 100%%", report.Percentage)
+	}
+}
+
+func TestCompletenessArrays(t *testing.T) {
+	referenceFile := createTempJSONFile(t, map[string]interface{}{
+		"greeting": "Hello",
+		"plural":   []interface{}{"one", "other"},
+		"tags":     []interface{}{},
+	})
+	defer os.Remove(referenceFile)
+	defer jsonhandler.EvictHandler(referenceFile)
+
+	tests := []struct {
+		name   string
+		target map[string]interface{}
+		want   CompletenessReport
+	}{
+		{
+			name: "fully translated",
+			target: map[string]interface{}{
+				"greeting": "Hallo",
+				"plural":   []interface{}{"eins", "andere"},
+				"tags":     []interface{}{},
+			},
+			want: CompletenessReport{Percentage: 100, Total: 2, Translated: 2},
+		},
+		{
+			name:   "empty array",
+			target: map[string]interface{}{"greeting": "Hallo", "plural": []interface{}{}},
+			want:   CompletenessReport{Percentage: 50, Total: 2, Translated: 1, Empty: 1},
+		},
+		{
+			name:   "missing array",
+			target: map[string]interface{}{"greeting": "Hallo"},
+			want:   CompletenessReport{Percentage: 50, Total: 2, Translated: 1, Missing: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targetFile := createTempJSONFile(t, tt.target)
+			defer os.Remove(targetFile)
+			defer jsonhandler.EvictHandler(targetFile)
+
+			report, err := Completeness(referenceFile, targetFile)
+			if err != nil {
+				t.Fatalf("Completeness() error = %v", err)
+			}
+			if *report != tt.want {
+				t.Errorf("Completeness() = %+v, want %+v", report, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraftSubtree(t *testing.T) {
+	targetFile := createTempJSONFile(t, map[string]interface{}{
+		"app":    map[string]interface{}{"name": "demo"},
+		"routes": "placeholder",
+	})
+	defer os.Remove(targetFile)
+	defer jsonhandler.EvictHandler(targetFile)
+
+	sourceFile := createTempJSONFile(t, map[string]interface{}{
+		"routes": map[string]interface{}{"home": "/", "about": "/about"},
+		"meta":   map[string]interface{}{"generated": true},
+	})
+	defer os.Remove(sourceFile)
+	defer jsonhandler.EvictHandler(sourceFile)
+
+	if err := GraftSubtree(targetFile, "routes", sourceFile, "routes"); err != nil {
+		t.Fatalf("GraftSubtree() error = %v", err)
+	}
+	value, _ := GetKey(targetFile, "routes")
+	if !deepEqual(value, map[string]interface{}{"home": "/", "about": "/about"}) {
+		t.Errorf("GraftSubtree() routes = %v", value)
+	}
+
+	if err := GraftSubtree(targetFile, "generated.source", sourceFile, ""); err != nil {
+		t.Fatalf("GraftSubtree() whole file error = %v", err)
+	}
+	if value, _ := GetKey(targetFile, "generated.source.meta.generated"); value != true {
+		t.Errorf("GraftSubtree() whole file graft = %v, want true", value)
+	}
+
+	// The graft is a copy of the source
+	if err := UpdateKey(targetFile, "routes.home", "/start", false); err != nil {
+		t.Fatalf("UpdateKey() error = %v", err)
+	}
+	if value, _ := GetKey(sourceFile, "routes.home"); value != "/" {
+		t.Errorf("Editing the graft changed the source: routes.home = %v", value)
+	}
+
+	err := GraftSubtree(targetFile, "routes", sourceFile, "missing")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GraftSubtree() missing source path error = %v, want %v", err, ErrKeyNotFound)
+	}
+}
+
+func TestCheckStringHealth(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "test_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer jsonhandler.EvictHandler(tempFile.Name())
+
+	content := "{\"clean\": \"Hello\\nworld\", \"bell\": \"Ding\\u0007\", \"padded\": \" Hi \", " +
+		"\"broken\": \"caf\xe9\", \"list\": [\"ok\", \"tail\\t\"], \"count\": 3}"
+	if _, err := tempFile.WriteString(content); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	tempFile.Close()
+
+	issues, err := CheckStringHealth(tempFile.Name())
+	if err != nil {
+		t.Fatalf("CheckStringHealth() error = %v", err)
+	}
+
+	want := []StringHealthIssue{
+		{Path: "bell", Issues: []string{StringIssueControlCharacters}},
+		{Path: "broken", Issues: []string{StringIssueInvalidUTF8}},
+		{Path: "list.1", Issues: []string{StringIssueTrailingWhitespace}},
+		{Path: "padded", Issues: []string{StringIssueLeadingWhitespace, StringIssueTrailingWhitespace}},
+	}
+	if !deepEqual(issues, want) {
+		t.Errorf("CheckStringHealth() = %+v, want %+v", issues, want)
+	}
+
+	changed, err := StripControlCharacters(tempFile.Name())
+	if err != nil {
+		t.Fatalf("StripControlCharacters() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("StripControlCharacters() changed %d string(s), want 1", changed)
+	}
+	if value, _ := GetKey(tempFile.Name(), "bell"); value != "Ding" {
+		t.Errorf("bell = %q, want %q", value, "Ding")
+	}
+	if value, _ := GetKey(tempFile.Name(), "clean"); value != "Hello\nworld" {
+		t.Errorf("clean = %q, newlines should be kept", value)
+	}
+}
+
+func TestListKeysPage(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"e": 5, "c": 3, "a": 1, "d": 4, "b": 2,
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		name    string
+		offset  int
+		limit   int
+		want    []string
+		hasMore bool
+	}{
+		{"first page", 0, 2, []string{"a", "b"}, true},
+		{"middle page", 2, 2, []string{"c", "d"}, true},
+		{"last page", 4, 2, []string{"e"}, false},
+		{"no limit", 1, 0, []string{"b", "c", "d", "e"}, false},
+		{"past the end", 9, 2, []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := ListKeysPage(tempFile, nil, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("ListKeysPage() error = %v", err)
+			}
+			if !deepEqual(page.Keys, tt.want) || page.HasMore != tt.hasMore || page.Total != 5 {
+				t.Errorf("ListKeysPage() = %+v, want keys %v, has_more %v, total 5", page, tt.want, tt.hasMore)
+			}
+		})
+	}
+
+	if _, err := ListKeysPage(tempFile, nil, -1, 2); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("ListKeysPage() negative offset error = %v, want %v", err, ErrInvalidRange)
+	}
+}
+
+func TestAssertArrayUnique(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"routes": []interface{}{
+			map[string]interface{}{"id": "home", "path": "/"},
+			map[string]interface{}{"id": "about", "path": "/about"},
+			map[string]interface{}{"id": "home", "path": "/index"},
+			map[string]interface{}{"path": "/orphan"},
+			map[string]interface{}{"id": "about", "path": "/about"},
+		},
+		"tags":  []interface{}{"a", "b", "a"},
+		"title": "Routes",
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	report, err := AssertArrayUnique(tempFile, "routes", "id")
+	if err != nil {
+		t.Fatalf("AssertArrayUnique() error = %v", err)
+	}
+	want := &UniquenessReport{
+		Passed: false,
+		Duplicates: []DuplicateValue{
+			{Value: "home", Indices: []int{0, 2}},
+			{Value: "about", Indices: []int{1, 4}},
+		},
+		Skipped: []int{3},
+	}
+	if !deepEqual(report, want) {
+		t.Errorf("AssertArrayUnique() = %+v, want %+v", report, want)
+	}
+
+	report, err = AssertArrayUnique(tempFile, "routes", "path")
+	if err != nil {
+		t.Fatalf("AssertArrayUnique() error = %v", err)
+	}
+	if report.Passed || len(report.Duplicates) != 1 || !deepEqual(report.Duplicates[0].Indices, []int{1, 4}) {
+		t.Errorf("AssertArrayUnique() by path = %+v", report)
+	}
+
+	report, err = AssertArrayUnique(tempFile, "tags", "")
+	if err != nil {
+		t.Fatalf("AssertArrayUnique() error = %v", err)
+	}
+	if report.Passed || !deepEqual(report.Duplicates, []DuplicateValue{{Value: "a", Indices: []int{0, 2}}}) {
+		t.Errorf("AssertArrayUnique() whole elements = %+v", report)
+	}
+
+	if _, err := AssertArrayUnique(tempFile, "title", "id"); !errors.Is(err, ErrNotArray) {
+		t.Errorf("AssertArrayUnique() on a string error = %v, want %v", err, ErrNotArray)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"auth": map[string]interface{}{"token": nil, "user": "alice"},
+		"name": "demo",
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	tests := []struct {
+		keyPath string
+		want    ProbeResult
+	}{
+		{"auth.user", ProbeResult{Exists: true, Value: "alice"}},
+		{"auth.token", ProbeResult{Exists: true, IsNull: true}},
+		{"auth.missing", ProbeResult{}},
+		{"name.first", ProbeResult{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyPath, func(t *testing.T) {
+			result, err := Probe(tempFile, tt.keyPath)
+			if err != nil {
+				t.Fatalf("Probe() error = %v", err)
+			}
+			if *result != tt.want {
+				t.Errorf("Probe() = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+
+	if _, err := Probe(tempFile, ""); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("Probe() invalid path error = %v, want %v", err, ErrInvalidPath)
+	}
+}
+
+func TestFillMissingFromReference(t *testing.T) {
+	referenceFile := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{
+			"title": "Home",
+			"cta":   "Start",
+			"tags":  []interface{}{"new", "hot"},
+		},
+		"footer": map[string]interface{}{"copyright": "ACME", "year": 2024},
+		"errors": map[string]interface{}{"notFound": "Not found"},
+	})
+	defer os.Remove(referenceFile)
+	defer jsonhandler.EvictHandler(referenceFile)
+
+	targetFile := createTempJSONFile(t, map[string]interface{}{
+		"home":   map[string]interface{}{"title": "Startseite", "cta": ""},
+		"errors": "Fehler",
+	})
+	defer os.Remove(targetFile)
+	defer jsonhandler.EvictHandler(targetFile)
+
+	added, err := FillMissingFromReference(targetFile, referenceFile, "[TODO] ")
+	if err != nil {
+		t.Fatalf("FillMissingFromReference() error = %v", err)
+	}
+
+	want := []string{"footer.copyright", "footer.year", "home.tags"}
+	if !deepEqual(added, want) {
+		t.Errorf("FillMissingFromReference() = %v, want %v", added, want)
+	}
+
+	data, _ := jsonhandler.GetHandler(targetFile).LoadJSON(false)
+	expected := map[string]interface{}{
+		"home": map[string]interface{}{
+			"title": "Startseite",
+			"cta":   "",
+			"tags":  []interface{}{"[TODO] new", "[TODO] hot"},
+		},
+		"footer": map[string]interface{}{"copyright": "[TODO] ACME", "year": 2024.0},
+		"errors": "Fehler",
+	}
+	if !deepEqual(data, expected) {
+		t.Errorf("Target after fill = %v, want %v", data, expected)
+	}
+
+	// The reference is left untouched
+	if value, _ := GetKey(referenceFile, "footer.copyright"); value != "ACME" {
+		t.Errorf("Reference footer.copyright = %v, want ACME", value)
+	}
+
+	added, err = FillMissingFromReference(targetFile, referenceFile, "")
+	if err != nil {
+		t.Fatalf("FillMissingFromReference() second run error = %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("FillMissingFromReference() second run = %v, want nothing", added)
+	}
+
+	// Keys containing dots are copied as the literal keys they are
+	dottedReference := createTempJSONFile(t, map[string]interface{}{
+		"a.b": "top",
+		"msg": map[string]interface{}{"x.y": "Hello"},
+	})
+	defer os.Remove(dottedReference)
+	defer jsonhandler.EvictHandler(dottedReference)
+	dottedTarget := createTempJSONFile(t, map[string]interface{}{"msg": map[string]interface{}{}})
+	defer os.Remove(dottedTarget)
+	defer jsonhandler.EvictHandler(dottedTarget)
+
+	added, err = FillMissingFromReference(dottedTarget, dottedReference, "")
+	if err != nil || !deepEqual(added, []string{"a.b", "msg.x.y"}) {
+		t.Fatalf("FillMissingFromReference() dotted keys = %v, %v, want [a.b msg.x.y]", added, err)
+	}
+	data, err = jsonhandler.GetHandler(dottedTarget).LoadJSON(false)
+	dottedWant := map[string]interface{}{"a.b": "top", "msg": map[string]interface{}{"x.y": "Hello"}}
+	if err != nil || !deepEqual(data, dottedWant) {
+		t.Errorf("Target after dotted fill = %v, %v, want %v", data, err, dottedWant)
+	}
+	if added, err = FillMissingFromReference(dottedTarget, dottedReference, ""); err != nil || len(added) != 0 {
+		t.Errorf("FillMissingFromReference() dotted second run = %v, %v, want nothing", added, err)
+	}
+}
+
+func TestRenameKeyEverywhere(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"label": "Root",
+		"button": map[string]interface{}{
+			"label": map[string]interface{}{"label": "Nested"},
+		},
+		"fields": []interface{}{
+			map[string]interface{}{"label": "Name"},
+			map[string]interface{}{"label": "Email", "text": "already here"},
+		},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	report, err := RenameKeyEverywhere(tempFile, "label", "text")
+	if err != nil {
+		t.Fatalf("RenameKeyEverywhere() error = %v", err)
+	}
+
+	want := &KeyRenameReport{
+		Renamed: map[string]string{
+			"label":              "text",
+			"button.label":       "button.text",
+			"button.label.label": "button.text.text",
+			"fields.0.label":     "fields.0.text",
+		},
+		Conflicts: []string{"fields.1.label"},
+	}
+	if !deepEqual(report, want) {
+		t.Errorf("RenameKeyEverywhere() = %+v, want %+v", report, want)
+	}
+
+	data, _ := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+	expected := map[string]interface{}{
+		"text": "Root",
+		"button": map[string]interface{}{
+			"text": map[string]interface{}{"text": "Nested"},
+		},
+		"fields": []interface{}{
+			map[string]interface{}{"text": "Name"},
+			map[string]interface{}{"label": "Email", "text": "already here"},
+		},
+	}
+	if !deepEqual(data, expected) {
+		t.Errorf("File after rename = %v, want %v", data, expected)
+	}
+
+	if _, err := RenameKeyEverywhere(tempFile, "text", "text"); !errors.Is(err, ErrSameKey) {
+		t.Errorf("RenameKeyEverywhere() same name error = %v, want %v", err, ErrSameKey)
+	}
+}
+
+func TestLongestStrings(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"short": "Hi",
+		"long":  "A much longer sentence",
+		"nested": map[string]interface{}{
+			"umlauts": "Größenänderung",
+			"tie":     "Größenänderung",
+		},
+		"items": []interface{}{"medium text", 12345678901234567890.0},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	lengths, err := LongestStrings(tempFile, 3)
+	if err != nil {
+		t.Fatalf("LongestStrings() error = %v", err)
+	}
+
+	want := []StringLength{
+		{Path: "long", Length: 22},
+		{Path: "nested.tie", Length: 14},
+		{Path: "nested.umlauts", Length: 14},
+	}
+	if !deepEqual(lengths, want) {
+		t.Errorf("LongestStrings() = %v, want %v", lengths, want)
+	}
+
+	all, err := LongestStrings(tempFile, 0)
+	if err != nil {
+		t.Fatalf("LongestStrings() error = %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("LongestStrings() with no limit returned %d strings, want 5", len(all))
+	}
+}
+
+func TestSetWhere(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"level": "error", "color": "grey"},
+			map[string]interface{}{"level": "warning", "color": "grey"},
+			map[string]interface{}{"level": "error"},
+			map[string]interface{}{"level": "error", "color": "red"},
+			"not an object",
+		},
+		"name": "lint",
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	updated, err := SetWhere(tempFile, "rules", "level", "error", "color", "red")
+	if err != nil {
+		t.Fatalf("SetWhere() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("SetWhere() updated %d element(s), want 2", updated)
+	}
+
+	rules, _ := GetKey(tempFile, "rules")
+	want := []interface{}{
+		map[string]interface{}{"level": "error", "color": "red"},
+		map[string]interface{}{"level": "warning", "color": "grey"},
+		map[string]interface{}{"level": "error", "color": "red"},
+		map[string]interface{}{"level": "error", "color": "red"},
+		"not an object",
+	}
+	if !deepEqual(rules, want) {
+		t.Errorf("rules after SetWhere() = %v, want %v", rules, want)
+	}
+
+	updated, err = SetWhere(tempFile, "rules", "level", "error", "style.bold", true)
+	if err != nil {
+		t.Fatalf("SetWhere() nested field error = %v", err)
+	}
+	if value, _ := GetKey(tempFile, "rules"); updated != 3 || !deepEqual(value.([]interface{})[0], map[string]interface{}{
+		"level": "error", "color": "red", "style": map[string]interface{}{"bold": true},
+	}) {
+		t.Errorf("SetWhere() nested field updated %d, rules = %v", updated, value)
+	}
+
+	if _, err := SetWhere(tempFile, "name", "level", "error", "color", "red"); !errors.Is(err, ErrNotArray) {
+		t.Errorf("SetWhere() on a string error = %v, want %v", err, ErrNotArray)
+	}
+}
+
+func TestIsUsable(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"object.json":  `{"key": "value"}`,
+		"array.json":   `[1, 2, 3]`,
+		"invalid.json": `{"key": }`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want Usability
+	}{
+		{"object.json", Usability{Exists: true, ValidJSON: true, IsObject: true}},
+		{"array.json", Usability{Exists: true, ValidJSON: true}},
+		{"invalid.json", Usability{Exists: true}},
+		{"missing.json", Usability{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, tt.name)
+			defer jsonhandler.EvictHandler(filePath)
+
+			usability, err := IsUsable(filePath)
+			if err != nil {
+				t.Fatalf("IsUsable() error = %v", err)
+			}
+			if *usability != tt.want {
+				t.Errorf("IsUsable() = %+v, want %+v", usability, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchEdit(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"app": map[string]interface{}{"title": "Demo", "beta": true},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	results, err := BatchEdit(tempFile, []map[string]interface{}{
+		{"action": "add", "path": "app.version", "value": "1.0"},
+		{"action": "remove", "path": "app.beta"},
+		{"action": "rename", "path": "app.title", "new_path": "app.name"},
+		{"action": "update", "path": "app.version", "value": "1.1"},
+	})
+	if err != nil {
+		t.Fatalf("BatchEdit() error = %v", err)
+	}
+	for _, result := range results {
+		if !result.Applied || result.Error != "" {
+			t.Errorf("BatchEdit() result = %+v, want applied", result)
+		}
+	}
+
+	data, _ := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+	want := map[string]interface{}{"app": map[string]interface{}{"name": "Demo", "version": "1.1"}}
+	if !deepEqual(data, want) {
+		t.Errorf("File after BatchEdit() = %v, want %v", data, want)
+	}
+
+	// Invalid instructions are reported per item and nothing is applied
+	results, err = BatchEdit(tempFile, []map[string]interface{}{
+		{"action": "add", "path": "app.extra", "value": 1},
+		{"action": "upsert", "path": "app.name", "value": "x"},
+		{"action": "update", "path": "app.name"},
+	})
+	if !errors.Is(err, ErrTransaction) {
+		t.Fatalf("BatchEdit() invalid instructions error = %v, want %v", err, ErrTransaction)
+	}
+	if results[0].Error != "" || !strings.Contains(results[1].Error, "unknown action 'upsert'") || !strings.Contains(results[2].Error, "needs a value") {
+		t.Errorf("BatchEdit() invalid instruction results = %+v", results)
+	}
+	if exists, _ := KeyExists(tempFile, "app.extra"); exists {
+		t.Error("BatchEdit() applied instructions from an invalid batch")
+	}
+
+	// A failing instruction rolls back the whole batch
+	results, err = BatchEdit(tempFile, []map[string]interface{}{
+		{"action": "add", "path": "app.extra", "value": 1},
+		{"action": "remove", "path": "app.missing"},
+	})
+	if !errors.Is(err, ErrTransaction) {
+		t.Fatalf("BatchEdit() failing instruction error = %v, want %v", err, ErrTransaction)
+	}
+	if results[0].Applied || results[1].Applied || results[1].Error == "" {
+		t.Errorf("BatchEdit() failing instruction results = %+v", results)
+	}
+	if exists, _ := KeyExists(tempFile, "app.extra"); exists {
+		t.Error("BatchEdit() saved a batch with a failing instruction")
+	}
+}
+
+func TestDiagnoseJSON(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "test_*.json")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer jsonhandler.EvictHandler(tempFile.Name())
+
+	if _, err := tempFile.WriteString("{'name': \"demo\", tags: [1, 2,]}"); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	tempFile.Close()
+
+	diagnosis, err := DiagnoseJSON(tempFile.Name())
+	if err != nil {
+		t.Fatalf("DiagnoseJSON() error = %v", err)
+	}
+	if diagnosis.Valid {
+		t.Error("DiagnoseJSON() Valid = true for invalid JSON")
+	}
+
+	kinds := make([]string, len(diagnosis.Issues))
+	for i, issue := range diagnosis.Issues {
+		kinds[i] = issue.Kind
+	}
+	want := []string{jsonhandler.IssueSingleQuoted, jsonhandler.IssueUnquotedKey, jsonhandler.IssueTrailingComma}
+	if !deepEqual(kinds, want) {
+		t.Errorf("DiagnoseJSON() issue kinds = %v, want %v", kinds, want)
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	dir := t.TempDir()
+	sourceFile := filepath.Join(dir, "config.json")
+	source := "{name: 'demo', \"tags\": ['a', 'b',],}"
+	if err := os.WriteFile(sourceFile, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer jsonhandler.EvictHandler(sourceFile)
+
+	result, err := RepairJSON(sourceFile, "", false)
+	if err != nil {
+		t.Fatalf("RepairJSON() error = %v", err)
+	}
+	if want := `{"name": "demo", "tags": ["a", "b"]}`; result.Content != want {
+		t.Errorf("RepairJSON() content = %s, want %s", result.Content, want)
+	}
+	if len(result.Changes) != 6 {
+		t.Errorf("RepairJSON() made %d change(s), want 6", len(result.Changes))
+	}
+
+	outputFile := filepath.Join(dir, "fixed", "config.json")
+	defer jsonhandler.EvictHandler(outputFile)
+	if _, err := RepairJSON(sourceFile, outputFile, true); err != nil {
+		t.Fatalf("RepairJSON() to file error = %v", err)
+	}
+	if value, _ := GetKey(outputFile, "name"); value != "demo" {
+		t.Errorf("Repaired file name = %v, want demo", value)
+	}
+	if content, _ := os.ReadFile(sourceFile); string(content) != source {
+		t.Error("RepairJSON() modified the source file")
+	}
+
+	if _, err := RepairJSON(sourceFile, sourceFile, false); !errors.Is(err, ErrRepairError) {
+		t.Errorf("RepairJSON() onto the source error = %v, want %v", err, ErrRepairError)
+	}
+
+	brokenFile := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(brokenFile, []byte(`{"a": 1,, "b": }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer jsonhandler.EvictHandler(brokenFile)
+	if _, err := RepairJSON(brokenFile, "", false); !errors.Is(err, ErrRepairError) {
+		t.Errorf("RepairJSON() unrepairable error = %v, want %v", err, ErrRepairError)
+	}
+}
+
+func TestGroupPathsByType(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"name":    "demo",
+		"port":    8080,
+		"debug":   false,
+		"proxy":   nil,
+		"servers": []interface{}{"a", 2},
+		"limits":  map[string]interface{}{"max": 10},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	groups, err := GroupPathsByType(tempFile)
+	if err != nil {
+		t.Fatalf("GroupPathsByType() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"string":  {"name", "servers.0"},
+		"number":  {"limits.max", "port", "servers.1"},
+		"boolean": {"debug"},
+		"null":    {"proxy"},
+		"object":  {"limits"},
+		"array":   {"servers"},
+	}
+	if !deepEqual(groups, want) {
+		t.Errorf("GroupPathsByType() = %v, want %v", groups, want)
+	}
+}
+
+func TestCheckPluralForms(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"cart": map[string]interface{}{
+			"items": map[string]interface{}{"one": "{count} Produkt", "other": "{count} Produkte"},
+			"files": map[string]interface{}{"=0": "Keine Dateien", "one": "Eine Datei", "few": "{count} Dateien"},
+		},
+		"inbox": map[string]interface{}{"other": "{count} messages"},
+		"title": map[string]interface{}{"one": "Title", "label": "Not plural"},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	issues, err := CheckPluralForms(tempFile, "pl-PL")
+	if err != nil {
+		t.Fatalf("CheckPluralForms() error = %v", err)
+	}
+	want := []PluralFormIssue{
+		{Path: "cart.files", Missing: []string{"many", "other"}},
+		{Path: "cart.items", Missing: []string{"few", "many"}},
+		{Path: "inbox", Missing: []string{"one", "few", "many"}},
+	}
+	if !deepEqual(issues, want) {
+		t.Errorf("CheckPluralForms(pl) = %v, want %v", issues, want)
+	}
+
+	issues, err = CheckPluralForms(tempFile, "ja")
+	if err != nil {
+		t.Fatalf("CheckPluralForms() error = %v", err)
+	}
+	if !deepEqual(issues, []PluralFormIssue{{Path: "cart.files", Missing: []string{"other"}}}) {
+		t.Errorf("CheckPluralForms(ja) = %v", issues)
+	}
+
+	if _, err := CheckPluralForms(tempFile, "xx"); !errors.Is(err, ErrInvalidLocale) {
+		t.Errorf("CheckPluralForms() unknown locale error = %v, want %v", err, ErrInvalidLocale)
+	}
+}
+
+func TestMultiFileKeyMatrix(t *testing.T) {
+	en := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{"title": "Home", "cta": "Start"},
+		"bye":  "Bye",
+	})
+	defer os.Remove(en)
+	defer jsonhandler.EvictHandler(en)
+
+	de := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{"title": "Start"},
+		"bye":  "Tschüss",
+	})
+	defer os.Remove(de)
+	defer jsonhandler.EvictHandler(de)
+
+	fr := createTempJSONFile(t, map[string]interface{}{
+		"home":  map[string]interface{}{"title": "Accueil"},
+		"extra": "Seulement ici",
+	})
+	defer os.Remove(fr)
+	defer jsonhandler.EvictHandler(fr)
+
+	matrix, err := MultiFileKeyMatrix([]string{en, de, fr})
+	if err != nil {
+		t.Fatalf("MultiFileKeyMatrix() error = %v", err)
+	}
+
+	want := &KeyMatrix{
+		Files:        []string{en, de, fr},
+		TotalPaths:   4,
+		MissingCount: map[string]int{en: 1, de: 2, fr: 2},
+		Missing: []PathPresence{
+			{Path: "bye", MissingFrom: []string{fr}},
+			{Path: "extra", MissingFrom: []string{en, de}},
+			{Path: "home.cta", MissingFrom: []string{de, fr}},
+		},
+	}
+	if !deepEqual(matrix, want) {
+		t.Errorf("MultiFileKeyMatrix() = %+v, want %+v", matrix, want)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	tests := []struct {
+		name             string
+		pathGlob         string
+		op               string
+		operand          interface{}
+		wantChanged      int
+		wantIncompatible []TypeViolation
+		want             map[string]interface{}
+	}{
+		{
+			name:        "multiply numbers under matching paths",
+			pathGlob:    "services.*.timeout",
+			op:          "mul",
+			operand:     1000,
+			wantChanged: 2,
+			wantIncompatible: []TypeViolation{
+				{Path: "services.web.timeout", Type: "string"},
+			},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"api": map[string]interface{}{"timeout": 1000.0, "name": "api"},
+					"db":  map[string]interface{}{"timeout": 2500.0, "name": "db"},
+					"web": map[string]interface{}{"timeout": "30s", "name": "web"},
+				},
+				"tags": []interface{}{"a", "b"},
+			},
+		},
+		{
+			name:        "uppercase whole document skips numbers",
+			op:          "upper",
+			wantChanged: 6,
+			wantIncompatible: []TypeViolation{
+				{Path: "services.api.timeout", Type: "number"},
+				{Path: "services.db.timeout", Type: "number"},
+			},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"api": map[string]interface{}{"timeout": 1.0, "name": "API"},
+					"db":  map[string]interface{}{"timeout": 2.5, "name": "DB"},
+					"web": map[string]interface{}{"timeout": "30S", "name": "WEB"},
+				},
+				"tags": []interface{}{"A", "B"},
+			},
+		},
+		{
+			name:        "replace substrings",
+			pathGlob:    "services.*.timeout",
+			op:          "replace",
+			operand:     map[string]interface{}{"old": "s", "new": " seconds"},
+			wantChanged: 1,
+			wantIncompatible: []TypeViolation{
+				{Path: "services.api.timeout", Type: "number"},
+				{Path: "services.db.timeout", Type: "number"},
+			},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"api": map[string]interface{}{"timeout": 1.0, "name": "api"},
+					"db":  map[string]interface{}{"timeout": 2.5, "name": "db"},
+					"web": map[string]interface{}{"timeout": "30 seconds", "name": "web"},
+				},
+				"tags": []interface{}{"a", "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := createTempJSONFile(t, map[string]interface{}{
+				"services": map[string]interface{}{
+					"api": map[string]interface{}{"timeout": 1, "name": "api"},
+					"db":  map[string]interface{}{"timeout": 2.5, "name": "db"},
+					"web": map[string]interface{}{"timeout": "30s", "name": "web"},
+				},
+				"tags": []interface{}{"a", "b"},
+			})
+			defer os.Remove(testFile)
+			defer jsonhandler.EvictHandler(testFile)
+
+			result, err := MapValues(testFile, tt.pathGlob, tt.op, tt.operand)
+			if err != nil {
+				t.Fatalf("MapValues() error = %v", err)
+			}
+			if result.Changed != tt.wantChanged {
+				t.Errorf("MapValues() changed = %d, want %d", result.Changed, tt.wantChanged)
+			}
+			if !deepEqual(result.Incompatible, tt.wantIncompatible) {
+				t.Errorf("MapValues() incompatible = %v, want %v", result.Incompatible, tt.wantIncompatible)
+			}
+
+			data, err := jsonhandler.GetHandler(testFile).LoadJSON(false)
+			if err != nil {
+				t.Fatalf("LoadJSON() error = %v", err)
+			}
+			if !deepEqual(data, tt.want) {
+				t.Errorf("File content = %v, want %v", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapValuesInvalidOperation(t *testing.T) {
+	testFile := createTempJSONFile(t, map[string]interface{}{"count": 1})
+	defer os.Remove(testFile)
+	defer jsonhandler.EvictHandler(testFile)
+
+	tests := []struct {
+		name    string
+		op      string
+		operand interface{}
+	}{
+		{name: "unknown op", op: "div", operand: 2},
+		{name: "add without number", op: "add", operand: "2"},
+		{name: "replace without old", op: "replace", operand: map[string]interface{}{"new": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MapValues(testFile, "", tt.op, tt.operand)
+			if !errors.Is(err, ErrInvalidTransform) {
+				t.Errorf("MapValues() error = %v, want %v", err, ErrInvalidTransform)
+			}
+		})
+	}
+}
+
+func TestStrictKeyParity(t *testing.T) {
+	en := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{"title": "Home", "cta": "Start"},
+		"bye":  "Bye",
+	})
+	defer os.Remove(en)
+	defer jsonhandler.EvictHandler(en)
+
+	enCopy := createTempJSONFile(t, map[string]interface{}{
+		"home": map[string]interface{}{"title": "Home", "cta": "Go"},
+		"bye":  "See you",
+	})
+	defer os.Remove(enCopy)
+	defer jsonhandler.EvictHandler(enCopy)
+
+	de := createTempJSONFile(t, map[string]interface{}{
+		"home":  map[string]interface{}{"title": "Start"},
+		"bye":   "Tschüss",
+		"extra": "Nur hier",
+	})
+	defer os.Remove(de)
+	defer jsonhandler.EvictHandler(de)
+
+	report, err := StrictKeyParity([]string{en, enCopy})
+	if err != nil {
+		t.Fatalf("StrictKeyParity() error = %v", err)
+	}
+	if !report.Identical || len(report.Files) != 0 {
+		t.Errorf("StrictKeyParity() = %+v, want identical", report)
+	}
+
+	report, err = StrictKeyParity([]string{en, enCopy, de})
+	if err != nil {
+		t.Fatalf("StrictKeyParity() error = %v", err)
+	}
+	want := &ParityReport{
+		Identical: false,
+		Files: []FileParity{
+			{File: en, Missing: []string{"extra"}, Extra: []string{"home.cta"}},
+			{File: enCopy, Missing: []string{"extra"}, Extra: []string{"home.cta"}},
+			{File: de, Missing: []string{"home.cta"}, Extra: []string{"extra"}},
+		},
+	}
+	if !deepEqual(report, want) {
+		t.Errorf("StrictKeyParity() = %+v, want %+v", report, want)
+	}
+
+	if _, err := StrictKeyParity(nil); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("StrictKeyParity(nil) error = %v, want %v", err, ErrInvalidPath)
+	}
+}
+
+func TestGenerateMock(t *testing.T) {
+	templateFile := createTempJSONFile(t, map[string]interface{}{
+		"name":    "prod",
+		"port":    8080,
+		"debug":   true,
+		"comment": nil,
+		"hosts": []interface{}{
+			map[string]interface{}{"host": "a.example.com", "weight": 3},
+			map[string]interface{}{"host": "b.example.com", "weight": 1},
+		},
+		"tags": []interface{}{},
+	})
+	defer os.Remove(templateFile)
+	defer jsonhandler.EvictHandler(templateFile)
+
+	outputFile := filepath.Join(t.TempDir(), "mock.json")
+	defer jsonhandler.EvictHandler(outputFile)
+
+	mock, err := GenerateMock(templateFile, outputFile, false)
+	if err != nil {
+		t.Fatalf("GenerateMock() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"name":    "string",
+		"port":    0.0,
+		"debug":   false,
+		"comment": nil,
+		"hosts": []interface{}{
+			map[string]interface{}{"host": "string", "weight": 0.0},
+		},
+		"tags": []interface{}{},
+	}
+	if !deepEqual(mock, want) {
+		t.Errorf("GenerateMock() = %v, want %v", mock, want)
+	}
+
+	written, err := jsonhandler.GetHandler(outputFile).LoadJSON(false)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if !deepEqual(written, want) {
+		t.Errorf("Written mock = %v, want %v", written, want)
+	}
+
+	if _, err := GenerateMock(templateFile, templateFile, false); !errors.Is(err, ErrMockError) {
+		t.Errorf("GenerateMock() onto template error = %v, want %v", err, ErrMockError)
+	}
+}
+
+func TestBenchmark(t *testing.T) {
+	testFile := createTempJSONFile(t, map[string]interface{}{
+		"name":  "demo",
+		"items": []interface{}{1, 2, 3},
+		"meta":  map[string]interface{}{"ok": true},
+	})
+	defer os.Remove(testFile)
+	defer jsonhandler.EvictHandler(testFile)
+
+	before, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+
+	result, err := Benchmark(testFile)
+	if err != nil {
+		t.Fatalf("Benchmark() error = %v", err)
+	}
+
+	if result.FileSize != int64(len(before)) {
+		t.Errorf("Benchmark() FileSize = %d, want %d", result.FileSize, len(before))
+	}
+	if result.Values != 7 {
+		t.Errorf("Benchmark() Values = %d, want 7", result.Values)
+	}
+	if result.MemoryBytes <= 0 {
+		t.Errorf("Benchmark() MemoryBytes = %d, want a positive estimate", result.MemoryBytes)
+	}
+	if result.ParseTime < 0 || result.WalkTime < 0 || result.SerializeTime < 0 || result.WriteTime < 0 {
+		t.Errorf("Benchmark() has negative times: %+v", result)
+	}
+
+	after, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("Benchmark() modified the file")
+	}
+
+	if _, err := Benchmark(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, jsonhandler.ErrFileNotFound) {
+		t.Errorf("Benchmark() missing file error = %v, want %v", err, jsonhandler.ErrFileNotFound)
+	}
+}
+
+func TestCopyWithin(t *testing.T) {
+	initial := map[string]interface{}{
+		"defaults": map[string]interface{}{
+			"retry": map[string]interface{}{"count": 3, "backoff": "1s"},
+		},
+		"services": map[string]interface{}{
+			"api": map[string]interface{}{"retry": "none"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		srcPath   string
+		destPath  string
+		overwrite bool
+		wantErr   error
+		wantDest  interface{}
+	}{
+		{
+			name:     "copy into new path",
+			srcPath:  "defaults.retry",
+			destPath: "services.web.retry",
+			wantDest: map[string]interface{}{"count": 3.0, "backoff": "1s"},
+		},
+		{
+			name:     "existing destination without overwrite",
+			srcPath:  "defaults.retry",
+			destPath: "services.api.retry",
+			wantErr:  ErrKeyExists,
+		},
+		{
+			name:      "existing destination with overwrite",
+			srcPath:   "defaults.retry",
+			destPath:  "services.api.retry",
+			overwrite: true,
+			wantDest:  map[string]interface{}{"count": 3.0, "backoff": "1s"},
+		},
+		{
+			name:     "missing source",
+			srcPath:  "defaults.timeout",
+			destPath: "services.api.timeout",
+			wantErr:  ErrKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := createTempJSONFile(t, initial)
+			defer os.Remove(testFile)
+			defer jsonhandler.EvictHandler(testFile)
+
+			err := CopyWithin(testFile, tt.srcPath, tt.destPath, tt.overwrite)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CopyWithin() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CopyWithin() error = %v", err)
+			}
+
+			dest, err := GetKey(testFile, tt.destPath)
+			if err != nil {
+				t.Fatalf("GetKey() error = %v", err)
+			}
+			if !deepEqual(dest, tt.wantDest) {
+				t.Errorf("Destination = %v, want %v", dest, tt.wantDest)
+			}
+
+			// The copy is independent of the source, which stays in place
+			if err := UpdateKey(testFile, tt.destPath+".count", 5, false); err != nil {
+				t.Fatalf("UpdateKey() error = %v", err)
+			}
+			source, err := GetKey(testFile, tt.srcPath)
+			if err != nil {
+				t.Fatalf("GetKey() error = %v", err)
+			}
+			if !deepEqual(source, map[string]interface{}{"count": 3.0, "backoff": "1s"}) {
+				t.Errorf("Source = %v, want it unchanged", source)
+			}
+		})
+	}
+}
+
+func TestFindKeysByPattern(t *testing.T) {
+	testFile := createTempJSONFile(t, map[string]interface{}{
+		"onClick": "handleClick",
+		"button": map[string]interface{}{
+			"onHover": "handleHover",
+			"online":  true,
+			"label":   "OK",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"onSelect": "pick"},
+			"onFocus",
+		},
+	})
+	defer os.Remove(testFile)
+	defer jsonhandler.EvictHandler(testFile)
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "event handlers",
+			pattern: "^on[A-Z]",
+			want:    []string{"button.onHover", "items.0.onSelect", "onClick"},
+		},
+		{
+			name:    "array indices never match",
+			pattern: "^[0-9]+$",
+			want:    []string{},
+		},
+		{
+			name:    "invalid regex",
+			pattern: "on(",
+			wantErr: ErrInvalidPattern,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := FindKeysByPattern(testFile, tt.pattern)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("FindKeysByPattern() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindKeysByPattern() error = %v", err)
+			}
+			if !deepEqual(paths, tt.want) {
+				t.Errorf("FindKeysByPattern() = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceBooleans(t *testing.T) {
+	initial := map[string]interface{}{
+		"flags": map[string]interface{}{
+			"beta":    "Yes",
+			"dark":    " off ",
+			"legacy":  "0",
+			"note":    "maybe",
+			"enabled": true,
+		},
+		"list":  []interface{}{"TRUE", "n", "other"},
+		"title": "no",
+	}
+
+	tests := []struct {
+		name      string
+		pathGlob  string
+		want      []string
+		wantTitle interface{}
+	}{
+		{
+			name:      "whole document",
+			want:      []string{"flags.beta", "flags.dark", "flags.legacy", "list.0", "list.1", "title"},
+			wantTitle: false,
+		},
+		{
+			name:      "matching paths only",
+			pathGlob:  "flags.*",
+			want:      []string{"flags.beta", "flags.dark", "flags.legacy"},
+			wantTitle: "no",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := createTempJSONFile(t, initial)
+			defer os.Remove(testFile)
+			defer jsonhandler.EvictHandler(testFile)
+
+			converted, err := CoerceBooleans(testFile, tt.pathGlob)
+			if err != nil {
+				t.Fatalf("CoerceBooleans() error = %v", err)
+			}
+			if !deepEqual(converted, tt.want) {
+				t.Errorf("CoerceBooleans() = %v, want %v", converted, tt.want)
+			}
+
+			flags, err := GetKey(testFile, "flags")
+			if err != nil {
+				t.Fatalf("GetKey() error = %v", err)
+			}
+			wantFlags := map[string]interface{}{
+				"beta":    true,
+				"dark":    false,
+				"legacy":  false,
+				"note":    "maybe",
+				"enabled": true,
+			}
+			if !deepEqual(flags, wantFlags) {
+				t.Errorf("flags = %v, want %v", flags, wantFlags)
+			}
+
+			title, err := GetKey(testFile, "title")
+			if err != nil {
+				t.Fatalf("GetKey() error = %v", err)
+			}
+			if title != tt.wantTitle {
+				t.Errorf("title = %v, want %v", title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestAuditLogCoversReads(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	if err := jsonhandler.SetAuditLog(logPath); err != nil {
+		t.Fatalf("SetAuditLog() error = %v", err)
+	}
+	defer jsonhandler.SetAuditLog("")
+
+	testFile := createTempJSONFile(t, map[string]interface{}{
+		"db":    map[string]interface{}{"password": "secret"},
+		"users": []interface{}{map[string]interface{}{"name": "ann"}},
+	})
+	defer os.Remove(testFile)
+	defer jsonhandler.EvictHandler(testFile)
+
+	if _, err := GetKey(testFile, "db.password"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if _, err := ListKeysWithValues(testFile, nil, 20); err != nil {
+		t.Fatalf("ListKeysWithValues() error = %v", err)
+	}
+	if _, err := ExportNDJSON(testFile, "users", filepath.Join(t.TempDir(), "users.ndjson"), false); err != nil {
+		t.Fatalf("ExportNDJSON() error = %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	reads := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record jsonhandler.AccessRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Audit line %q is not JSON: %v", line, err)
+		}
+		if record.File != testFile {
+			continue
+		}
+		if record.Operation != jsonhandler.AuditRead {
+			t.Errorf("Unexpected audit record %+v", record)
+		}
+		reads[record.KeyPath] = true
+	}
+
+	// Key reads, including the export's, name their key; the listing reads the whole document
+	want := map[string]bool{"db.password": true, "": true, "users": true}
+	if !deepEqual(reads, want) {
+		t.Errorf("Audited reads = %v, want %v", reads, want)
+	}
+}
+
+func TestGetKeyDepthReadsOnce(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	if err := jsonhandler.SetAuditLog(logPath); err != nil {
+		t.Fatalf("SetAuditLog() error = %v", err)
+	}
+	defer jsonhandler.SetAuditLog("")
+
+	testFile := createTempJSONFile(t, map[string]interface{}{
+		"db": map[string]interface{}{"password": "secret"},
+	})
+	defer os.Remove(testFile)
+	defer jsonhandler.EvictHandler(testFile)
+
+	if depth, err := GetKeyDepth(testFile, "db.password"); err != nil || depth != 2 {
+		t.Fatalf("GetKeyDepth() = %d, %v, want 2", depth, err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	var records []jsonhandler.AccessRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record jsonhandler.AccessRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Audit line %q is not JSON: %v", line, err)
+		}
+		if record.File == testFile {
+			records = append(records, record)
+		}
+	}
+	if len(records) != 1 || records[0].KeyPath != "db.password" {
+		t.Errorf("Audited reads = %+v, want a single read of db.password", records)
+	}
+}
+
+func TestGlobRewritesDottedKeys(t *testing.T) {
+	tempFile := createTempJSONFile(t, map[string]interface{}{
+		"msg":   map[string]interface{}{"x.y": "hello"},
+		"count": map[string]interface{}{"a.b": 2.0},
+		"flags": map[string]interface{}{"on.web": "yes"},
+	})
+	defer os.Remove(tempFile)
+	defer jsonhandler.EvictHandler(tempFile)
+
+	if changed, err := TransformStrings(tempFile, "upper", "msg.*"); err != nil || changed != 1 {
+		t.Errorf("TransformStrings() = %d, %v, want 1 change", changed, err)
+	}
+	if result, err := MapValues(tempFile, "count.*", "mul", 3.0); err != nil || result.Changed != 1 {
+		t.Errorf("MapValues() = %+v, %v, want 1 change", result, err)
+	}
+	if converted, err := CoerceBooleans(tempFile, "flags.*"); err != nil || len(converted) != 1 {
+		t.Errorf("CoerceBooleans() = %v, %v, want 1 conversion", converted, err)
+	}
+
+	data, err := jsonhandler.GetHandler(tempFile).LoadJSON(false)
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"msg":   map[string]interface{}{"x.y": "HELLO"},
+		"count": map[string]interface{}{"a.b": 6.0},
+		"flags": map[string]interface{}{"on.web": true},
+	}
+	if !deepEqual(data, want) {
+		t.Errorf("File content = %v, want %v", data, want)
+	}
+}
+
 // Helper functions
 
 func createTempJSONFile(t *testing.T, data map[string]interface{}) string {
@@ -3,6 +3,8 @@ package pathresolver
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -237,4 +239,109 @@ func SetValueAtPath(data map[string]interface{}, keyPath string, value interface
 
 	parent[finalKey] = value
 	return nil
+}
+
+// Match is a value found by ExpandWildcardPath. It is stored in Parent under Key, so callers
+// can replace or delete it in place; Segments are the keys leading to it from the root, and
+// Path joins them with dots for display. Keys may themselves contain dots, so Path cannot be
+// resolved again reliably.
+type Match struct {
+	Path     string
+	Segments []string
+	Parent   map[string]interface{}
+	Key      string
+	Value    interface{}
+}
+
+// ExpandWildcardPath resolves a dot-notation pattern in which '*' segments match any
+// object key, returning the matches sorted by path. Like NavigateToKey, a root key equal to
+// the whole pattern is matched literally. A pattern that matches nothing yields no matches
+// rather than an error.
+func ExpandWildcardPath(data interface{}, pattern string) ([]Match, error) {
+	if err := ValidatePath(pattern); err != nil {
+		return nil, err
+	}
+
+	if root, ok := data.(map[string]interface{}); ok {
+		if value, exists := root[pattern]; exists {
+			return []Match{{Path: pattern, Segments: []string{pattern}, Parent: root, Key: pattern, Value: value}}, nil
+		}
+	}
+
+	results := []Match{}
+	expandWildcard(data, SplitPath(pattern), nil, &results)
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, nil
+}
+
+// expandWildcard walks the remaining pattern segments, recording every full match
+func expandWildcard(current interface{}, segments []string, prefix []string, results *[]Match) {
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	// Copy the prefix so sibling branches don't share a backing array
+	visit := func(key string, value interface{}) {
+		path := append(prefix[:len(prefix):len(prefix)], key)
+		if len(segments) == 1 {
+			*results = append(*results, Match{Path: strings.Join(path, "."), Segments: path, Parent: currentMap, Key: key, Value: value})
+			return
+		}
+		expandWildcard(value, segments[1:], path, results)
+	}
+
+	if segments[0] == "*" {
+		for key, value := range currentMap {
+			visit(key, value)
+		}
+		return
+	}
+
+	if value, exists := currentMap[segments[0]]; exists {
+		visit(segments[0], value)
+	}
+}
+
+// WalkFunc is called for every value visited by Walk. Returning false skips the value's children.
+type WalkFunc func(path string, value interface{}) bool
+
+// Walk visits every value nested under data depth-first, with object keys in sorted order.
+// Array elements are addressed by index, e.g. "items.0.name". The root itself is not visited.
+func Walk(data interface{}, fn WalkFunc) {
+	walkChildren(data, "", fn)
+}
+
+// walkChildren visits the children of value, whose own path is prefix
+func walkChildren(value interface{}, prefix string, fn WalkFunc) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkValue(v[key], JoinPath(prefix, key), fn)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkValue(item, JoinPath(prefix, strconv.Itoa(i)), fn)
+		}
+	}
+}
+
+// walkValue visits a single value and, unless told to skip, its children
+func walkValue(value interface{}, path string, fn WalkFunc) {
+	if fn(path, value) {
+		walkChildren(value, path, fn)
+	}
+}
+
+// JoinPath appends a key to a dot-notation path, treating an empty prefix as the root
+func JoinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
 }
\ No newline at end of file
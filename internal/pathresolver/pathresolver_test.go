@@ -305,6 +305,133 @@ func TestSetValueAtPath(t *testing.T) {
 	}
 }
 
+func TestExpandWildcardPath(t *testing.T) {
+	testData := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"login": map[string]interface{}{
+				"title": "Sign In",
+			},
+			"register": map[string]interface{}{
+				"title": "Create Account",
+			},
+			"note": "not an object",
+		},
+		"forms": map[string]interface{}{
+			"buttons": map[string]interface{}{
+				"submit": "Submit",
+			},
+		},
+		"msg": map[string]interface{}{
+			"x.y": "dotted",
+		},
+		"auth.*": "literal",
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:    "dotted key",
+			pattern: "msg.*",
+			want:    map[string]interface{}{"msg.x.y": "dotted"},
+		},
+		{
+			name:    "literal root key",
+			pattern: "auth.*",
+			want:    map[string]interface{}{"auth.*": "literal"},
+		},
+		{
+			name:    "single wildcard",
+			pattern: "auth.*.title",
+			want: map[string]interface{}{
+				"auth.login.title":    "Sign In",
+				"auth.register.title": "Create Account",
+			},
+		},
+		{
+			name:    "multiple wildcards",
+			pattern: "*.*.submit",
+			want:    map[string]interface{}{"forms.buttons.submit": "Submit"},
+		},
+		{
+			name:    "no wildcard",
+			pattern: "auth.login.title",
+			want:    map[string]interface{}{"auth.login.title": "Sign In"},
+		},
+		{
+			name:    "no match",
+			pattern: "missing.*",
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "empty pattern",
+			pattern: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExpandWildcardPath(testData, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpandWildcardPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(result) != len(tt.want) {
+				t.Errorf("ExpandWildcardPath() = %v, want %v", result, tt.want)
+				return
+			}
+			for _, match := range result {
+				if want, ok := tt.want[match.Path]; !ok || match.Value != want {
+					t.Errorf("ExpandWildcardPath() matched %s = %v, want %v", match.Path, match.Value, want)
+				}
+				if match.Parent[match.Key] != match.Value || match.Segments[len(match.Segments)-1] != match.Key {
+					t.Errorf("ExpandWildcardPath() match %s does not locate its value: %+v", match.Path, match)
+				}
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	testData := map[string]interface{}{
+		"b": []interface{}{"x", map[string]interface{}{"c": "y"}},
+		"a": "value",
+	}
+
+	var visited []string
+	Walk(testData, func(path string, value interface{}) bool {
+		visited = append(visited, path)
+		return true
+	})
+
+	want := []string{"a", "b", "b.0", "b.1", "b.1.c"}
+	if len(visited) != len(want) {
+		t.Fatalf("Walk() visited %v, want %v", visited, want)
+	}
+	for i, path := range want {
+		if visited[i] != path {
+			t.Errorf("Walk() visited[%d] = %s, want %s", i, visited[i], path)
+		}
+	}
+
+	// Returning false should skip children
+	visited = nil
+	Walk(testData, func(path string, value interface{}) bool {
+		visited = append(visited, path)
+		return false
+	})
+	if len(visited) != 2 {
+		t.Errorf("Walk() with skip visited %v, want only top-level keys", visited)
+	}
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s